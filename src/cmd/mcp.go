@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
@@ -27,11 +28,18 @@ func init() {
 }
 
 func mcpServer(_ *cobra.Command, _ []string) {
+	if err := config.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
 	// Prepare folders if not exist
 	err := utils.CreateFolder(config.PathQrCode, config.PathSendItems, config.PathStorages, config.PathMedia)
 	if err != nil {
 		log.Fatalf("Failed to create folders: %v", err)
 	}
+	if err := whatsapp.LoadWebhookSecret(); err != nil {
+		log.Fatalf("Failed to load the webhook secret: %v", err)
+	}
 
 	// Set auto reconnect to whatsapp server after booting
 	go helpers.SetAutoConnectAfterBooting(appUsecase)
@@ -1,19 +1,32 @@
 package cmd
 
 import (
+	"archive/zip"
+	"bufio"
 	"context"
 	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"mime"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
@@ -26,8 +39,9 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/template/html/v2"
 	"github.com/sirupsen/logrus"
+	"github.com/skip2/go-qrcode"
 	"github.com/spf13/cobra"
-	_ "go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow"
 	waProto "go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"google.golang.org/protobuf/proto"
@@ -50,6 +64,13 @@ var (
 )
 
 func restServer(_ *cobra.Command, _ []string) {
+	if err := config.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	if !slices.Contains(utils.SupportedLanguages(), config.AppLanguage) {
+		logrus.Warnf("Unsupported language %q, responses will be in %s", config.AppLanguage, utils.DefaultLanguage)
+	}
+
 	err := os.MkdirAll(config.PathQrCode, 0755)
 	if err != nil {
 		log.Fatalln(err)
@@ -66,15 +87,26 @@ func restServer(_ *cobra.Command, _ []string) {
 	if err != nil {
 		log.Fatalln(err)
 	}
+	if err := whatsapp.LoadWebhookSecret(); err != nil {
+		log.Fatalf("Failed to load the webhook secret: %v", err)
+	}
 
 	engine := html.NewFileSystem(http.FS(EmbedIndex), ".html")
 	engine.AddFunc("isEnableBasicAuth", func(token any) bool {
 		return token != nil
 	})
-	app := fiber.New(fiber.Config{
+	fiberConfig := fiber.Config{
 		Views:     engine,
 		BodyLimit: int(config.WhatsappSettingMaxVideoSize),
-	})
+	}
+	// Forwarded headers are only honored from trusted proxies, otherwise any
+	// client could spoof its IP, protocol or host.
+	if len(config.AppTrustedProxies) > 0 {
+		fiberConfig.EnableTrustedProxyCheck = true
+		fiberConfig.TrustedProxies = config.AppTrustedProxies
+		fiberConfig.ProxyHeader = config.AppProxyHeader
+	}
+	app := fiber.New(fiberConfig)
 
 	app.Static("/statics", "./statics")
 	app.Use("/components", filesystem.New(filesystem.Config{
@@ -90,106 +122,292 @@ func restServer(_ *cobra.Command, _ []string) {
 
 	app.Use(middleware.Recovery())
 	app.Use(middleware.BasicAuth())
-	if config.AppDebug {
-		app.Use(logger.New())
+	if config.AppDebug || config.AppAccessLog {
+		app.Use(logger.New(logger.Config{
+			Format: config.AppAccessLogFormat,
+			Next: func(c *fiber.Ctx) bool {
+				return slices.Contains(config.AppAccessLogExclude, c.Path())
+			},
+		}))
 	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowHeaders: "Origin, Content-Type, Accept, " + middleware.IdempotencyHeader,
 	}))
 
 	if len(config.AppBasicAuthCredential) > 0 {
 		account := make(map[string]string)
+		// The format was checked by config.Validate at startup.
 		for _, basicAuth := range config.AppBasicAuthCredential {
-			ba := strings.Split(basicAuth, ":")
-			if len(ba) != 2 {
-				log.Fatalln("Basic auth is not valid, please this following format <user>:<secret>")
-			}
-			account[ba[0]] = ba[1]
+			user, secret, _ := strings.Cut(basicAuth, ":")
+			account[user] = secret
 		}
 
 		app.Use(basicauth.New(basicauth.Config{
 			Users: account,
 		}))
 	}
+	app.Use(middleware.Idempotency())
 
-	// Endpoint para enviar mensagens com citação
+	// Endpoint para enviar mensagens com citação.
+	// Deprecated: mantido como alias de /chat/send/text.
 	app.Post("/send/message", func(c *fiber.Ctx) error {
 		var request struct {
-			Phone          string `json:"Phone"`
-			Jid            string `json:"Jid"` // Mantido para compatibilidade com grupos
-			Message        string `json:"message"`
-			ReplyMessageID string `json:"reply_message_id"`
+			Phone              string            `json:"Phone"`
+			Jid                string            `json:"Jid"` // Mantido para compatibilidade com grupos
+			Message            string            `json:"message"`
+			Template           string            `json:"template"`
+			Variables          map[string]string `json:"variables"`
+			Strict             *bool             `json:"strict"`
+			ReplyMessageID     string            `json:"reply_message_id"`
+			ReplyChat          string            `json:"reply_chat"`
+			SkipSignature      bool              `json:"skip_signature"`
+			EphemeralSeconds   uint32            `json:"ephemeral_seconds"`
+			SkipLookup         bool              `json:"skip_lookup"`
+			AutoDeleteAfter    int               `json:"auto_delete_after"`
+			MessageID          string            `json:"message_id"`
+			MentionAll         bool              `json:"mention_all"`
+			DisableLinkPreview bool              `json:"disable_link_preview"`
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Corpo da requisição inválido"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
+		c.Set("Deprecation", "true")
+		c.Set("Link", `</chat/send/text>; rel="successor-version"`)
 
 		// Validar se pelo menos Phone ou Jid foi fornecido
 		if request.Phone == "" && request.Jid == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone ou Jid é obrigatório"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_or_jid_required")})
+		}
+
+		// Jid identifica o grupo; nesse caso Phone é o autor da mensagem citada
+		text := chatTextRequest{
+			Phone:              request.Phone,
+			Message:            request.Message,
+			Template:           request.Template,
+			Variables:          request.Variables,
+			Strict:             request.Strict,
+			ReplyMessageID:     request.ReplyMessageID,
+			ReplyChat:          request.ReplyChat,
+			SkipSignature:      request.SkipSignature,
+			EphemeralSeconds:   request.EphemeralSeconds,
+			SkipLookup:         request.SkipLookup,
+			AutoDeleteAfter:    request.AutoDeleteAfter,
+			MessageID:          request.MessageID,
+			MentionAll:         request.MentionAll,
+			DisableLinkPreview: request.DisableLinkPreview,
 		}
-		if request.Message == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Message é obrigatório"})
+		if request.Jid != "" {
+			text.Phone = request.Jid
+			text.ReplyParticipant = request.Phone
+		}
+
+		resp, status, err := sendChatText(c.UserContext(), text)
+		if err != nil {
+			return errorResponse(c, status, err)
+		}
+		return sendResponse(c, textSentBody(utils.T("message_sent"), resp, text), resp.ID)
+	})
+
+	app.Post("/chat/send/text", func(c *fiber.Ctx) error {
+		var request chatTextRequest
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+
+		resp, status, err := sendChatText(c.UserContext(), request)
+		if err != nil {
+			return errorResponse(c, status, err)
+		}
+		return sendResponse(c, textSentBody(utils.T("text_sent"), resp, request), resp.ID)
+	})
+
+	// Send a product of this account's catalog as a product card, e.g.
+	// {"Phone": "628123", "product_id": "8372625432", "message": "Here it is"}
+	app.Post("/chat/send/product", func(c *fiber.Ctx) error {
+		var request struct {
+			Phone         string `json:"Phone"`
+			ProductID     string `json:"product_id"` // catalog product ID or retailer ID
+			Message       string `json:"message"`
+			Footer        string `json:"footer"`
+			SkipSignature bool   `json:"skip_signature"`
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+		if request.Phone == "" || request.ProductID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_product_required")})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Cliente WhatsApp não inicializado"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
+		}
+
+		product, err := whatsapp.FindCatalogProduct(c.UserContext(), request.ProductID)
+		if errors.Is(err, whatsapp.ErrProductNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("product_not_found", request.ProductID)})
+		} else if err != nil {
+			return sendFailed(c, err, "catalog_query_failed")
+		}
+
+		resp, err := whatsapp.SendProductMessage(c.UserContext(), jid, product, utils.AppendSignature(request.Message, request.SkipSignature), request.Footer)
+		if err != nil {
+			return sendFailed(c, err, "send_message_failed")
+		}
+		return sendResponse(c, fiber.Map{"status": utils.T("product_sent"), "message_id": resp.ID}, resp.ID)
+	})
+
+	// Ask a chat to share its location or phone number. The answer is forwarded with
+	// type location_request_response or phone_request_response and the request_id.
+	app.Post("/chat/request-location", func(c *fiber.Ctx) error {
+		return sendRequestPrompt(c, "location")
+	})
+	app.Post("/chat/request-phone", func(c *fiber.Ctx) error {
+		return sendRequestPrompt(c, "phone")
+	})
+
+	// Tally of the votes stored for a poll, e.g. GET /chat/<jid>/poll/<poll id>/results?format=csv
+	app.Get("/chat/:jid/poll/:id/results", func(c *fiber.Ctx) error {
+		jid, err := whatsapp.ParseJID(c.Params("jid"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_jid", err)})
+		}
+		format := c.Query("format", "json")
+		if format != "json" && format != "csv" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_export_format")})
+		}
+
+		results, err := whatsapp.GetPollResults(jid, c.Params("id"))
+		if errors.Is(err, whatsapp.ErrPollResultsDisabled) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": utils.T("poll_results_disabled")})
+		} else if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_poll_votes_failed", err)})
+		}
+		if !results.Known && results.TotalVoters == 0 {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("poll_results_not_found", c.Params("id"))})
+		}
+		if format == "json" {
+			return c.JSON(results)
+		}
+
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="poll-%s.csv"`, results.PollID))
+		writer := csv.NewWriter(c)
+		_ = writer.Write([]string{"option", "votes", "voters"})
+		for _, option := range results.Options {
+			_ = writer.Write([]string{option.Name, strconv.Itoa(option.Votes), strings.Join(option.Voters, " ")})
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+
+	// Vote in a poll the server has sent or received, by option text or zero-based index, e.g.
+	// {"Phone": "628123", "message_id": "3EB0...", "options": ["Yes"], "option_indexes": [2]}
+	app.Post("/chat/poll/vote", func(c *fiber.Ctx) error {
+		var request struct {
+			Phone         string   `json:"Phone"`
+			MessageID     string   `json:"message_id"`
+			Options       []string `json:"options"`
+			OptionIndexes []int    `json:"option_indexes"`
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+		if request.Phone == "" || request.MessageID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_message_id_required")})
 		}
 
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
 		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Cliente WhatsApp não conectado ou logado"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
 		}
 
-		// Determinar o JID a ser usado (Phone para contatos individuais, Jid para grupos)
-		var jid types.JID
-		var err error
-		if request.Jid != "" {
-			jid, err = whatsapp.ParseJID(request.Jid)
-			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Jid inválido: %v", err)})
-			}
-		} else {
-			jid, err = whatsapp.ParseJID(request.Phone)
-			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Phone inválido: %v", err)})
-			}
+		chatJID, err := whatsapp.ResolveRecipientJID(request.Phone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
-		msg := &waProto.Message{
-			ExtendedTextMessage: &waProto.ExtendedTextMessage{
-				Text: proto.String(request.Message),
-			},
+		poll, ok := whatsapp.FindPoll(request.MessageID)
+		if !ok {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("poll_not_found", request.MessageID)})
+		}
+		if poll.Chat.ToNonAD() != chatJID.ToNonAD() {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("poll_wrong_chat", request.MessageID, poll.Chat.String())})
+		}
+		selected, err := poll.PollSelection(request.Options, request.OptionIndexes)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		if request.ReplyMessageID != "" {
-			participant := jid.String()
-			if strings.Contains(jid.String(), "@g.us") {
-				if request.Phone == "" {
-					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone é obrigatório para citações em grupos"})
-				}
-				senderJID, err := whatsapp.ParseJID(request.Phone)
-				if err != nil {
-					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Phone inválido para citação: %v", err)})
-				}
-				participant = senderJID.String()
-			}
-			msg.ExtendedTextMessage.ContextInfo = &waProto.ContextInfo{
-				StanzaID:      proto.String(request.ReplyMessageID),
-				Participant:   proto.String(participant),
-				QuotedMessage: &waProto.Message{Conversation: proto.String("")},
-			}
+		vote, err := whatsapp.BuildPollVote(c.UserContext(), request.MessageID, selected)
+		if err != nil {
+			return sendFailed(c, err, "poll_vote_failed")
+		}
+		resp, err := whatsapp.SendMessage(c.UserContext(), chatJID, vote)
+		if err != nil {
+			return sendFailed(c, err, "poll_vote_failed")
+		}
+		whatsapp.RecordOwnPollVote(poll.Chat, request.MessageID, selected)
+		logrus.Infof("Voted %v in poll %s of %s", selected, request.MessageID, chatJID.String())
+		return sendResponse(c, fiber.Map{"status": utils.T("poll_voted"), "message_id": resp.ID, "options": selected}, resp.ID)
+	})
+
+	// Expert endpoint: sends a waE2E.Message given as protojson, e.g.
+	// {"phone": "628123", "message": {"conversation": "hi"}}
+	app.Post("/chat/send/raw", func(c *fiber.Ctx) error {
+		if !config.WhatsappRawMessage {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": utils.T("raw_message_disabled")})
+		}
+
+		var request struct {
+			Phone   string          `json:"phone"`
+			Message json.RawMessage `json:"message"`
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+		if request.Phone == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_required")})
+		}
+		if len(request.Message) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("message_required")})
+		}
+
+		msg, err := whatsapp.ParseRawMessage(request.Message)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
 		}
 
-		_, err = waCli.SendMessage(context.Background(), jid, msg)
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
 		if err != nil {
-			logrus.Errorf("Falha ao enviar mensagem para %s: %v", jid.String(), err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Falha ao enviar mensagem: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
-		logrus.Infof("Mensagem enviada com sucesso para %s", jid.String())
 
-		return c.JSON(fiber.Map{"status": "Mensagem enviada"})
+		logrus.Warnf("Sending raw message to %s", jid.String())
+		resp, err := whatsapp.SendMessage(c.UserContext(), jid, msg)
+		if err != nil {
+			return sendFailed(c, err, "send_message_failed")
+		}
+		return sendResponse(c, fiber.Map{"status": utils.T("raw_message_sent"), "message_id": resp.ID}, resp.ID)
 	})
 
 	app.Post("/send-presence", func(c *fiber.Ctx) error {
@@ -199,21 +417,21 @@ func restServer(_ *cobra.Command, _ []string) {
 			Duration int64  `json:"duration"`
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
 
 		if request.Phone == "" || request.Presence == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone and presence are required"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_presence_required")})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not initialized"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
 		}
 
-		jid, err := whatsapp.ParseJID(request.Phone)
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid Phone: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
 		var presence types.ChatPresence
@@ -226,24 +444,15 @@ func restServer(_ *cobra.Command, _ []string) {
 			presence = types.ChatPresenceComposing
 			media = types.ChatPresenceMediaAudio
 		default:
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid presence type, must be 'typing' or 'recording'"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_presence")})
 		}
 
-		err = waCli.SendChatPresence(jid, presence, media)
+		sent, err := whatsapp.SendChatPresence(jid, presence, media, time.Duration(request.Duration)*time.Second)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to send presence: %v", err)})
-		}
-
-		if request.Duration > 0 {
-			go func() {
-				time.Sleep(time.Duration(request.Duration) * time.Second)
-				if err := waCli.SendChatPresence(jid, types.ChatPresencePaused, types.ChatPresenceMediaText); err != nil {
-					logrus.Errorf("Failed to send paused presence: %v", err)
-				}
-			}()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("send_presence_failed", err)})
 		}
 
-		return c.JSON(fiber.Map{"status": fmt.Sprintf("Presence %s sent to %s", request.Presence, request.Phone)})
+		return c.JSON(fiber.Map{"status": utils.T("presence_sent", request.Presence, request.Phone), "coalesced": !sent})
 	})
 
 	app.Post("/call-ended", func(c *fiber.Ctx) error {
@@ -252,21 +461,21 @@ func restServer(_ *cobra.Command, _ []string) {
 			Phone  string `json:"Phone"`
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
 
 		if request.CallID == "" || request.Phone == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "call_id and Phone are required"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("call_id_and_phone_required")})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not initialized"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
 		}
 
-		jid, err := whatsapp.ParseJID(request.Phone)
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid Phone: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
 		cacheKey := request.CallID + ":" + request.Phone
@@ -279,15 +488,14 @@ func restServer(_ *cobra.Command, _ []string) {
 			})
 		}
 
-		go func() {
-			time.Sleep(cacheTTL)
+		utils.DefaultScheduler.Schedule("call-ended:"+cacheKey, cacheTTL, func() {
 			callWebhookCache.Delete(cacheKey)
-		}()
+		})
 
 		err = waCli.RejectCall(jid, request.CallID)
 		if err != nil {
 			callWebhookCache.Delete(cacheKey)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to reject call: %v", err)})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("reject_call_failed", err)})
 		}
 
 		if len(config.WhatsappWebhook) > 0 {
@@ -315,31 +523,53 @@ func restServer(_ *cobra.Command, _ []string) {
 		})
 	})
 
+	app.Get("/calls/cache", listCallCache)
+	app.Delete("/calls/cache", clearCallCache)
+
 	app.Post("/chat/send/audio", func(c *fiber.Ctx) error {
 		var request struct {
-			Phone string `json:"Phone"`
-			Media string `json:"media"`
+			Phone            string `json:"Phone"`
+			Media            string `json:"media"`
+			ViewOnce         bool   `json:"view_once"` // sent as a voice note, OGG/Opus only
+			EphemeralSeconds uint32 `json:"ephemeral_seconds"`
+			SkipLookup       bool   `json:"skip_lookup"` // Phone is already the registered JID
+			MessageID        string `json:"message_id"`  // sent under this ID instead of a generated one
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
 
 		if request.Phone == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone is required"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_required")})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not initialized"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
 		}
 
 		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not connected or logged in"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
-		jid, err := whatsapp.ParseJID(request.Phone)
+		ctx := context.Background()
+		if request.EphemeralSeconds > 0 {
+			if err := whatsapp.ValidateEphemeral(waCli, jid, request.EphemeralSeconds); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+			ctx = whatsapp.WithEphemeral(ctx, request.EphemeralSeconds)
+		}
+		if request.SkipLookup {
+			ctx = whatsapp.WithoutRecipientLookup(ctx)
+		}
+		ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid Phone: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		var audioData []byte
@@ -348,20 +578,20 @@ func restServer(_ *cobra.Command, _ []string) {
 		if strings.HasPrefix(request.Media, "data:audio/") || strings.Contains(request.Media, ",") {
 			parts := strings.SplitN(request.Media, ",", 2)
 			if len(parts) != 2 {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Base64 format"})
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_base64")})
 			}
 			mimeType = strings.TrimPrefix(strings.Split(parts[0], ";")[0], "data:")
 			audioData, err = base64.StdEncoding.DecodeString(parts[1])
 			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Failed to decode Base64: %v", err)})
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("decode_base64_failed", err)})
 			}
 		} else {
 			if _, err := os.Stat(request.Media); os.IsNotExist(err) {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("File not found: %s", request.Media)})
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("file_not_found_path", request.Media)})
 			}
 			audioData, err = os.ReadFile(request.Media)
 			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to read file: %v", err)})
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_file_failed", err)})
 			}
 			mimeType = determineMimeType(request.Media)
 			if mimeType == "" {
@@ -369,6 +599,14 @@ func restServer(_ *cobra.Command, _ []string) {
 				logrus.Warnf("MIME type not detected by extension for file %s, auto-detected as %s", request.Media, mimeType)
 			}
 		}
+		if !strings.HasPrefix(mimeType, "audio/") {
+			// Generic types such as application/octet-stream or application/ogg say nothing
+			// about the codec, so look at the header bytes before rejecting the file.
+			if sniffed := utils.SniffAudioMimeType(audioData); sniffed != "" {
+				logrus.Infof("Audio type %s sniffed from file header, was detected as %s", sniffed, mimeType)
+				mimeType = sniffed
+			}
+		}
 
 		switch mimeType {
 		case "audio/opus", "audio/ogg":
@@ -380,67 +618,98 @@ func restServer(_ *cobra.Command, _ []string) {
 		case "audio/aac":
 			mimeType = "audio/aac"
 		default:
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Unsupported audio format: %s", mimeType)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("unsupported_audio_format", mimeType)})
 		}
 		logrus.Infof("Detected MIME type for media: %s", mimeType)
+		audioData, mimeType = whatsapp.TranscodeAudio(ctx, audioData, mimeType)
+		if request.ViewOnce {
+			if err := whatsapp.ValidateViewOnce("audio", mimeType); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+		}
 
-		tempPath := filepath.Join(config.PathMedia, fmt.Sprintf("temp_%s", filepath.Base(request.Media)))
-		if err := os.WriteFile(tempPath, audioData, 0644); err != nil {
+		if tempPath, err := utils.SafeJoin(config.PathMedia, utils.OutboundMediaPrefix+filepath.Base(request.Media)); err != nil {
+			logrus.Errorf("Refusing to save temp file: %v", err)
+		} else if err := os.WriteFile(tempPath, audioData, 0644); err != nil {
 			logrus.Errorf("Failed to save temp file: %v", err)
 		} else {
 			logrus.Infof("Temporary file saved at %s for debugging", tempPath)
 		}
 
-		err = whatsapp.SendAudioMessage(context.Background(), jid, audioData, mimeType)
+		resp, err := whatsapp.SendAudioMessage(ctx, jid, audioData, mimeType, request.ViewOnce)
 		if err != nil {
 			logrus.Errorf("Failed to send audio message to %s: %v", jid.String(), err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to send audio message: %v", err)})
+			return sendFailed(c, err, "send_audio_failed")
 		}
 		logrus.Infof("Audio message sent successfully to %s", jid.String())
 
-		return c.JSON(fiber.Map{"status": "Audio sent"})
+		return sendResponse(c, fiber.Map{"status": utils.T("audio_sent"), "message_id": resp.ID}, resp.ID)
 	})
 
 	app.Post("/chat/send/document", func(c *fiber.Ctx) error {
 		var request struct {
-			Phone        string `json:"Phone"`
-			FileName     string `json:"FileName"`
-			Caption      string `json:"Caption"`
-			DocumentPath string `json:"DocumentPath"`
-			IsForwarded  bool   `json:"is_forwarded"`
+			Phone            string `json:"Phone"`
+			FileName         string `json:"FileName"`
+			Caption          string `json:"Caption"`
+			DocumentPath     string `json:"DocumentPath"`
+			Thumbnail        string `json:"thumbnail"` // optional base64 JPEG preview
+			IsForwarded      bool   `json:"is_forwarded"`
+			ViewOnce         bool   `json:"view_once"` // rejected, WhatsApp has no view-once documents
+			SkipSignature    bool   `json:"skip_signature"`
+			EphemeralSeconds uint32 `json:"ephemeral_seconds"`
+			SkipLookup       bool   `json:"skip_lookup"` // Phone is already the registered JID
+			MessageID        string `json:"message_id"`  // sent under this ID instead of a generated one
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
 
 		if request.Phone == "" || request.DocumentPath == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone and DocumentPath are required"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_document_required")})
+		}
+		if request.ViewOnce {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": whatsapp.ValidateViewOnce("document", "").Error()})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not initialized"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
 		}
 
 		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not connected or logged in"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
-		jid, err := whatsapp.ParseJID(request.Phone)
+		ctx := context.Background()
+		if request.EphemeralSeconds > 0 {
+			if err := whatsapp.ValidateEphemeral(waCli, jid, request.EphemeralSeconds); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+			ctx = whatsapp.WithEphemeral(ctx, request.EphemeralSeconds)
+		}
+		if request.SkipLookup {
+			ctx = whatsapp.WithoutRecipientLookup(ctx)
+		}
+		ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid Phone: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		if _, err := os.Stat(request.DocumentPath); os.IsNotExist(err) {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("File not found: %s", request.DocumentPath)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("file_not_found_path", request.DocumentPath)})
 		}
 		documentData, err := os.ReadFile(request.DocumentPath)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to read file: %v", err)})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_file_failed", err)})
 		}
 
 		if int64(len(documentData)) > config.WhatsappSettingMaxFileSize {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Document size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxFileSize)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("document_too_large", config.WhatsappSettingMaxFileSize)})
 		}
 
 		mimeType := determineMimeType(request.DocumentPath)
@@ -449,63 +718,88 @@ func restServer(_ *cobra.Command, _ []string) {
 			logrus.Warnf("MIME type not detected by extension for file %s, auto-detected as %s", request.DocumentPath, mimeType)
 		}
 
-		tempPath := filepath.Join(config.PathMedia, fmt.Sprintf("temp_%s", request.FileName))
-		if err := os.WriteFile(tempPath, documentData, 0644); err != nil {
+		var thumbnail []byte
+		if request.Thumbnail != "" {
+			thumbnail, err = base64.StdEncoding.DecodeString(request.Thumbnail)
+			if err != nil || http.DetectContentType(thumbnail) != "image/jpeg" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_thumbnail")})
+			}
+		}
+
+		if tempPath, err := utils.SafeJoin(config.PathMedia, utils.OutboundMediaPrefix+request.FileName); err != nil {
+			logrus.Errorf("Refusing to save temp file: %v", err)
+		} else if err := os.WriteFile(tempPath, documentData, 0644); err != nil {
 			logrus.Errorf("Failed to save temp file: %v", err)
 		} else {
 			logrus.Infof("Temporary file saved at %s for debugging", tempPath)
 		}
 
-		err = whatsapp.SendDocumentMessage(context.Background(), jid, documentData, mimeType, request.FileName, request.Caption, request.IsForwarded)
+		resp, err := whatsapp.SendDocumentMessage(ctx, jid, documentData, mimeType, request.FileName, utils.AppendCaptionSignature(request.Caption, request.SkipSignature), request.IsForwarded, request.ViewOnce, thumbnail)
 		if err != nil {
 			logrus.Errorf("Failed to send document message to %s: %v", jid.String(), err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to send document message: %v", err)})
+			return sendFailed(c, err, "send_document_failed")
 		}
 		logrus.Infof("Document message sent successfully to %s", jid.String())
 
-		return c.JSON(fiber.Map{"status": "Document sent"})
+		return sendResponse(c, fiber.Map{"status": utils.T("document_sent"), "message_id": resp.ID}, resp.ID)
 	})
 
 	app.Post("/chat/send/video", func(c *fiber.Ctx) error {
 		var request struct {
-			Phone       string `json:"Phone"`
-			Caption     string `json:"Caption"`
-			VideoPath   string `json:"VideoPath"`
-			ViewOnce    bool   `json:"view_once"`
-			IsForwarded bool   `json:"is_forwarded"`
+			Phone            string `json:"Phone"`
+			Caption          string `json:"Caption"`
+			VideoPath        string `json:"VideoPath"`
+			ViewOnce         bool   `json:"view_once"`
+			IsForwarded      bool   `json:"is_forwarded"`
+			GifPlayback      bool   `json:"gif_playback"`
+			SkipSignature    bool   `json:"skip_signature"`
+			EphemeralSeconds uint32 `json:"ephemeral_seconds"`
+			SkipLookup       bool   `json:"skip_lookup"` // Phone is already the registered JID
+			MessageID        string `json:"message_id"`  // sent under this ID instead of a generated one
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
 
 		if request.Phone == "" || request.VideoPath == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone and VideoPath are required"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_video_required")})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not initialized"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
 		}
 
 		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not connected or logged in"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
 		}
 
-		jid, err := whatsapp.ParseJID(request.Phone)
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid Phone: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
-		if _, err := os.Stat(request.VideoPath); os.IsNotExist(err) {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("File not found: %s", request.VideoPath)})
+		ctx := context.Background()
+		if request.EphemeralSeconds > 0 {
+			if err := whatsapp.ValidateEphemeral(waCli, jid, request.EphemeralSeconds); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+			ctx = whatsapp.WithEphemeral(ctx, request.EphemeralSeconds)
 		}
-		videoData, err := os.ReadFile(request.VideoPath)
+		if request.SkipLookup {
+			ctx = whatsapp.WithoutRecipientLookup(ctx)
+		}
+		ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to read file: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 
-		if int64(len(videoData)) > config.WhatsappSettingMaxVideoSize {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Video size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxVideoSize)})
+		if _, err := os.Stat(request.VideoPath); os.IsNotExist(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("file_not_found_path", request.VideoPath)})
+		}
+		videoData, err := os.ReadFile(request.VideoPath)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_file_failed", err)})
 		}
 
 		mimeType := determineMimeType(request.VideoPath)
@@ -513,64 +807,89 @@ func restServer(_ *cobra.Command, _ []string) {
 			mimeType = http.DetectContentType(videoData)
 			logrus.Warnf("MIME type not detected by extension for file %s, auto-detected as %s", request.VideoPath, mimeType)
 		}
+		videoData, mimeType = whatsapp.TranscodeVideo(ctx, videoData, mimeType)
 
-		tempPath := filepath.Join(config.PathMedia, fmt.Sprintf("temp_%s", filepath.Base(request.VideoPath)))
-		if err := os.WriteFile(tempPath, videoData, 0644); err != nil {
+		if int64(len(videoData)) > config.WhatsappSettingMaxVideoSize {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("video_too_large", config.WhatsappSettingMaxVideoSize)})
+		}
+
+		if tempPath, err := utils.SafeJoin(config.PathMedia, utils.OutboundMediaPrefix+filepath.Base(request.VideoPath)); err != nil {
+			logrus.Errorf("Refusing to save temp file: %v", err)
+		} else if err := os.WriteFile(tempPath, videoData, 0644); err != nil {
 			logrus.Errorf("Failed to save temp file: %v", err)
 		} else {
 			logrus.Infof("Temporary file saved at %s for debugging", tempPath)
 		}
 
-		err = whatsapp.SendVideoMessage(context.Background(), jid, videoData, mimeType, filepath.Base(request.VideoPath), request.Caption, request.ViewOnce, request.IsForwarded)
+		resp, err := whatsapp.SendVideoMessage(ctx, jid, videoData, mimeType, filepath.Base(request.VideoPath), utils.AppendCaptionSignature(request.Caption, request.SkipSignature), request.ViewOnce, request.IsForwarded, request.GifPlayback)
 		if err != nil {
 			logrus.Errorf("Failed to send video message to %s: %v", jid.String(), err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to send video message: %v", err)})
+			return sendFailed(c, err, "send_video_failed")
 		}
 		logrus.Infof("Video message sent successfully to %s", jid.String())
 
-		return c.JSON(fiber.Map{"status": "Video sent"})
+		return sendResponse(c, fiber.Map{"status": utils.T("video_sent"), "message_id": resp.ID}, resp.ID)
 	})
 
 	app.Post("/chat/send/image", func(c *fiber.Ctx) error {
 		var request struct {
-			Phone       string `json:"Phone"`
-			Caption     string `json:"Caption"`
-			ImagePath   string `json:"ImagePath"`
-			ViewOnce    bool   `json:"view_once"`
-			IsForwarded bool   `json:"is_forwarded"`
+			Phone            string `json:"Phone"`
+			Caption          string `json:"Caption"`
+			ImagePath        string `json:"ImagePath"`
+			ViewOnce         bool   `json:"view_once"`
+			IsForwarded      bool   `json:"is_forwarded"`
+			SkipSignature    bool   `json:"skip_signature"`
+			EphemeralSeconds uint32 `json:"ephemeral_seconds"`
+			SkipLookup       bool   `json:"skip_lookup"` // Phone is already the registered JID
+			MessageID        string `json:"message_id"`  // sent under this ID instead of a generated one
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
 
 		if request.Phone == "" || request.ImagePath == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone and ImagePath are required"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_image_required")})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not initialized"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
 		}
 
 		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not connected or logged in"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
-		jid, err := whatsapp.ParseJID(request.Phone)
+		ctx := context.Background()
+		if request.EphemeralSeconds > 0 {
+			if err := whatsapp.ValidateEphemeral(waCli, jid, request.EphemeralSeconds); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+			ctx = whatsapp.WithEphemeral(ctx, request.EphemeralSeconds)
+		}
+		if request.SkipLookup {
+			ctx = whatsapp.WithoutRecipientLookup(ctx)
+		}
+		ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid Phone: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		if _, err := os.Stat(request.ImagePath); os.IsNotExist(err) {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("File not found: %s", request.ImagePath)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("file_not_found_path", request.ImagePath)})
 		}
 		imageData, err := os.ReadFile(request.ImagePath)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to read file: %v", err)})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_file_failed", err)})
 		}
 
 		if int64(len(imageData)) > config.WhatsappSettingMaxFileSize {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Image size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxFileSize)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("image_too_large", config.WhatsappSettingMaxFileSize)})
 		}
 
 		mimeType := determineMimeType(request.ImagePath)
@@ -579,21 +898,96 @@ func restServer(_ *cobra.Command, _ []string) {
 			logrus.Warnf("MIME type not detected by extension for file %s, auto-detected as %s", request.ImagePath, mimeType)
 		}
 
-		tempPath := filepath.Join(config.PathMedia, fmt.Sprintf("temp_%s", filepath.Base(request.ImagePath)))
-		if err := os.WriteFile(tempPath, imageData, 0644); err != nil {
+		if tempPath, err := utils.SafeJoin(config.PathMedia, utils.OutboundMediaPrefix+filepath.Base(request.ImagePath)); err != nil {
+			logrus.Errorf("Refusing to save temp file: %v", err)
+		} else if err := os.WriteFile(tempPath, imageData, 0644); err != nil {
 			logrus.Errorf("Failed to save temp file: %v", err)
 		} else {
 			logrus.Infof("Temporary file saved at %s for debugging", tempPath)
 		}
 
-		err = whatsapp.SendImageMessage(context.Background(), jid, imageData, mimeType, filepath.Base(request.ImagePath), request.Caption, request.ViewOnce, request.IsForwarded)
+		resp, err := whatsapp.SendImageMessage(ctx, jid, imageData, mimeType, filepath.Base(request.ImagePath), utils.AppendCaptionSignature(request.Caption, request.SkipSignature), request.ViewOnce, request.IsForwarded)
 		if err != nil {
 			logrus.Errorf("Failed to send image message to %s: %v", jid.String(), err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to send image message: %v", err)})
+			return sendFailed(c, err, "send_image_failed")
 		}
 		logrus.Infof("Image message sent successfully to %s", jid.String())
 
-		return c.JSON(fiber.Map{"status": "Image sent"})
+		return sendResponse(c, fiber.Map{"status": utils.T("image_sent"), "message_id": resp.ID}, resp.ID)
+	})
+
+	// Image with up to three buttons under it, e.g. {"Phone": "628123", "ImagePath": "statics/promo.jpg",
+	// "text": "Spring sale", "buttons": [{"type": "url", "text": "Visit website", "url": "https://example.com"}]}
+	app.Post("/chat/send/image-cta", func(c *fiber.Ctx) error {
+		var request struct {
+			Phone         string               `json:"Phone"`
+			ImagePath     string               `json:"ImagePath"`
+			Text          string               `json:"text"`
+			Footer        string               `json:"footer"`
+			Buttons       []whatsapp.CTAButton `json:"buttons"`
+			SkipSignature bool                 `json:"skip_signature"`
+			SkipLookup    bool                 `json:"skip_lookup"` // Phone is already the registered JID
+			MessageID     string               `json:"message_id"`  // sent under this ID instead of a generated one
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+
+		if request.Phone == "" || request.ImagePath == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_image_required")})
+		}
+		if err := whatsapp.ValidateCTAButtons(request.Buttons); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
+
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
+		}
+		ctx := context.Background()
+		if request.SkipLookup {
+			ctx = whatsapp.WithoutRecipientLookup(ctx)
+		}
+		ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if _, err := os.Stat(request.ImagePath); os.IsNotExist(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("file_not_found_path", request.ImagePath)})
+		}
+		imageData, err := os.ReadFile(request.ImagePath)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_file_failed", err)})
+		}
+
+		if int64(len(imageData)) > config.WhatsappSettingMaxFileSize {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("image_too_large", config.WhatsappSettingMaxFileSize)})
+		}
+
+		mimeType := determineMimeType(request.ImagePath)
+		if mimeType == "" {
+			mimeType = http.DetectContentType(imageData)
+		}
+
+		text := utils.AppendCaptionSignature(request.Text, request.SkipSignature)
+		resp, err := whatsapp.SendImageCTA(ctx, jid, imageData, mimeType, text, request.Footer, request.Buttons)
+		if err != nil {
+			logrus.Errorf("Failed to send image with buttons to %s: %v", jid.String(), err)
+			return sendFailed(c, err, "send_image_failed")
+		}
+		logrus.Infof("Image with %d buttons sent to %s", len(request.Buttons), jid.String())
+
+		return sendResponse(c, fiber.Map{"status": utils.T("image_sent"), "message_id": resp.ID}, resp.ID)
 	})
 
 	app.Post("/chat/send/location", func(c *fiber.Ctx) error {
@@ -601,171 +995,1559 @@ func restServer(_ *cobra.Command, _ []string) {
 			Phone     string  `json:"Phone"`
 			Latitude  float64 `json:"latitude"`
 			Longitude float64 `json:"longitude"`
+			MessageID string  `json:"message_id"` // sent under this ID instead of a generated one
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
 
 		if request.Phone == "" || request.Latitude == 0 || request.Longitude == 0 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone, latitude, and longitude are required"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("location_required")})
+		}
+
+		ctx, err := whatsapp.WithRequestedMessageID(context.Background(), request.MessageID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not initialized"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
 		}
 
 		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not connected or logged in"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
 		}
 
-		jid, err := whatsapp.ParseJID(request.Phone)
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid Phone: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
-		err = whatsapp.SendLocationMessage(context.Background(), jid, request.Latitude, request.Longitude)
+		resp, err := whatsapp.SendLocationMessage(ctx, jid, request.Latitude, request.Longitude)
 		if err != nil {
 			logrus.Errorf("Failed to send location message to %s: %v", jid.String(), err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to send location message: %v", err)})
+			return sendFailed(c, err, "send_location_failed")
 		}
 		logrus.Infof("Location message sent successfully to %s", jid.String())
 
-		return c.JSON(fiber.Map{"status": "Location sent"})
+		return sendResponse(c, fiber.Map{"status": utils.T("location_sent"), "message_id": resp.ID}, resp.ID)
 	})
 
-	app.Post("/chat/delete-message", func(c *fiber.Ctx) error {
+	// Live location that stops by itself, e.g. {"Phone": "...", "latitude": -23.5, "longitude": -46.6, "duration_seconds": 900}.
+	// The returned task_id can be cancelled with DELETE /tasks/:id to stop sharing early.
+	app.Post("/chat/send/location/live", func(c *fiber.Ctx) error {
 		var request struct {
-			Phone     string `json:"Phone"`
-			MessageID string `json:"message_id"`
+			Phone           string  `json:"Phone"`
+			Latitude        float64 `json:"latitude"`
+			Longitude       float64 `json:"longitude"`
+			AccuracyMeters  uint32  `json:"accuracy_meters"`
+			Caption         string  `json:"caption"`
+			DurationSeconds int     `json:"duration_seconds"`
+			MessageID       string  `json:"message_id"` // sent under this ID instead of a generated one
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
 
-		if request.Phone == "" || request.MessageID == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone and message_id are required"})
+		if request.Phone == "" || request.Latitude == 0 || request.Longitude == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("location_required")})
+		}
+		duration := time.Duration(request.DurationSeconds) * time.Second
+		if duration <= 0 || duration > whatsapp.MaxLiveLocationDuration {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("live_location_duration_invalid", int(whatsapp.MaxLiveLocationDuration.Seconds()))})
+		}
+
+		ctx, err := whatsapp.WithRequestedMessageID(context.Background(), request.MessageID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not initialized"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
 		}
 
 		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not connected or logged in"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
 		}
 
-		jid, err := whatsapp.ParseJID(request.Phone)
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid Phone: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
-		messageID := types.MessageID(request.MessageID)
-		_, err = waCli.RevokeMessage(jid, messageID)
+		location := whatsapp.LiveLocation{
+			Latitude:       request.Latitude,
+			Longitude:      request.Longitude,
+			AccuracyMeters: request.AccuracyMeters,
+			Caption:        request.Caption,
+		}
+		resp, taskID, err := whatsapp.StartLiveLocation(ctx, jid, location, duration)
 		if err != nil {
-			logrus.Errorf("Failed to revoke message %s in chat %s: %v", messageID, jid.String(), err)
-			if strings.Contains(err.Error(), "too old") || strings.Contains(err.Error(), "not allowed") {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Message deletion not allowed: likely too old or not sent by you"})
-			}
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to revoke message: %v", err)})
+			logrus.Errorf("Failed to send live location to %s: %v", jid.String(), err)
+			return sendFailed(c, err, "send_location_failed")
 		}
-		logrus.Infof("Message %s revoked successfully in chat %s", messageID, jid.String())
+		logrus.Infof("Live location sent to %s for %s", jid.String(), duration)
 
-		return c.JSON(fiber.Map{"status": fmt.Sprintf("Message %s deleted", messageID)})
+		return sendResponse(c, fiber.Map{
+			"status":     utils.T("live_location_sent"),
+			"message_id": resp.ID,
+			"task_id":    taskID,
+			"stops_at":   resp.Timestamp.Add(duration).Format(time.RFC3339),
+		}, resp.ID)
 	})
 
-	app.Post("/chat/mark-read", func(c *fiber.Ctx) error {
+	// Send a WhatsApp Flow, e.g. {"Phone": "628123", "flow_id": "1234567890", "flow_token": "order-42",
+	// "cta": "Book now", "body": "Pick a time slot", "screen": "BOOKING", "data": {"branch": "downtown"}}
+	app.Post("/chat/send/flow", func(c *fiber.Ctx) error {
 		var request struct {
-			Phone     string `json:"Phone"`
-			MessageID string `json:"message_id"`
-			Sender    string `json:"sender"`
-			Played    bool   `json:"played"`
+			Phone     string         `json:"Phone"`
+			FlowID    string         `json:"flow_id"`
+			FlowToken string         `json:"flow_token"`
+			CTA       string         `json:"cta"`
+			Body      string         `json:"body"`
+			Header    string         `json:"header"`
+			Footer    string         `json:"footer"`
+			Screen    string         `json:"screen"`
+			Data      map[string]any `json:"data"`
+			Draft     bool           `json:"draft"`
+			MessageID string         `json:"message_id"` // sent under this ID instead of a generated one
 		}
 		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
 		}
 
-		if request.Phone == "" || request.MessageID == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Phone and message_id are required"})
+		if request.Phone == "" || request.FlowID == "" || request.CTA == "" || request.Body == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("flow_required")})
+		}
+
+		ctx, err := whatsapp.WithRequestedMessageID(c.UserContext(), request.MessageID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
 
 		waCli := whatsapp.GetWaCli()
 		if waCli == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not initialized"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
 		}
 
 		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "WhatsApp client not connected or logged in"})
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
 		}
 
-		chatJID, err := whatsapp.ParseJID(request.Phone)
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
 		if err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid Phone: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
 		}
 
-		var senderJID types.JID
-		if request.Sender != "" {
-			senderJID, err = whatsapp.ParseJID(request.Sender)
-			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("Invalid sender JID: %v", err)})
-			}
-		} else if strings.Contains(chatJID.String(), "@g.us") {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Sender is required for group chats"})
+		flow := whatsapp.Flow{
+			ID:     request.FlowID,
+			Token:  request.FlowToken,
+			CTA:    request.CTA,
+			Body:   request.Body,
+			Header: request.Header,
+			Footer: request.Footer,
+			Screen: request.Screen,
+			Data:   request.Data,
+			Draft:  request.Draft,
+		}
+		resp, err := whatsapp.SendFlow(ctx, jid, flow)
+		if err != nil {
+			logrus.Errorf("Failed to send flow %s to %s: %v", request.FlowID, jid.String(), err)
+			return sendFailed(c, err, "send_message_failed")
 		}
 
-		messageID := types.MessageID(request.MessageID)
-		timestamp := time.Now()
+		return sendResponse(c, fiber.Map{"status": utils.T("flow_sent", request.FlowID), "message_id": resp.ID}, resp.ID)
+	})
 
-		var receiptTypeExtra []types.ReceiptType
-		if request.Played {
-			receiptTypeExtra = append(receiptTypeExtra, types.ReceiptTypePlayed)
-		} else {
-			receiptTypeExtra = append(receiptTypeExtra, types.ReceiptTypeRead)
+	// Send a contact card built from a phone number, e.g. POST /chat/send/contact-by-phone
+	// {"Phone": "5511...", "contact_phone": "+55 11 9...", "contact_name": "Support"}
+	app.Post("/chat/send/contact-by-phone", func(c *fiber.Ctx) error {
+		var request struct {
+			Phone        string `json:"Phone"`
+			ContactPhone string `json:"contact_phone"`
+			ContactName  string `json:"contact_name"`
+			MessageID    string `json:"message_id"` // sent under this ID instead of a generated one
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+
+		if request.Phone == "" || request.ContactPhone == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("contact_phone_required")})
+		}
+		if _, _, err := whatsapp.ContactVCard(request.ContactPhone, request.ContactName); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_contact_phone", err)})
 		}
 
-		logrus.Debugf("Marking message %s as read in chat %s with sender %s, played: %v", messageID, chatJID.String(), senderJID.String(), request.Played)
-		err = waCli.MarkRead([]types.MessageID{messageID}, timestamp, chatJID, senderJID, receiptTypeExtra...)
+		ctx, err := whatsapp.WithRequestedMessageID(c.UserContext(), request.MessageID)
 		if err != nil {
-			logrus.Errorf("Failed to mark message %s as read in chat %s: %v", messageID, chatJID.String(), err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fmt.Sprintf("Failed to mark message as read: %v", err)})
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
-		logrus.Infof("Message %s marked as read in chat %s", messageID, chatJID.String())
 
-		return c.JSON(fiber.Map{"status": fmt.Sprintf("Message %s marked as read", messageID)})
-	})
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
 
-	rest.InitRestApp(app, appUsecase)
-	rest.InitRestSend(app, sendUsecase)
-	rest.InitRestUser(app, userUsecase)
-	rest.InitRestMessage(app, messageUsecase)
-	rest.InitRestGroup(app, groupUsecase)
-	rest.InitRestNewsletter(app, newsletterUsecase)
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
 
-	app.Get("/", func(c *fiber.Ctx) error {
-		return c.Render("views/index", fiber.Map{
-			"AppHost":        fmt.Sprintf("%s://%s", c.Protocol(), c.Hostname()),
-			"AppVersion":     config.AppVersion,
-			"BasicAuthToken": c.UserContext().Value(middleware.AuthorizationValue("BASIC_AUTH")),
-			"MaxFileSize":    humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize)),
-			"MaxVideoSize":   humanize.Bytes(uint64(config.WhatsappSettingMaxVideoSize)),
-		})
-	})
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
+		}
 
-	websocket.RegisterRoutes(app, appUsecase)
-	go websocket.RunHub()
+		resp, err := whatsapp.SendContactByPhone(ctx, jid, request.ContactPhone, request.ContactName)
+		if err != nil {
+			logrus.Errorf("Failed to send contact %s to %s: %v", request.ContactPhone, jid.String(), err)
+			return sendFailed(c, err, "send_message_failed")
+		}
+
+		return sendResponse(c, fiber.Map{"status": utils.T("contact_sent", request.ContactPhone), "message_id": resp.ID}, resp.ID)
+	})
+
+	app.Post("/chat/delete-message", func(c *fiber.Ctx) error {
+		var request struct {
+			Phone     string `json:"Phone"`
+			MessageID string `json:"message_id"`
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+
+		if request.Phone == "" || request.MessageID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_message_id_required")})
+		}
+
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
+
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
+		}
+
+		messageID := types.MessageID(request.MessageID)
+		_, err = waCli.RevokeMessage(jid, messageID)
+		if err != nil {
+			logrus.Errorf("Failed to revoke message %s in chat %s: %v", messageID, jid.String(), err)
+			if strings.Contains(err.Error(), "too old") || strings.Contains(err.Error(), "not allowed") {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("delete_not_allowed")})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("revoke_failed", err)})
+		}
+		logrus.Infof("Message %s revoked successfully in chat %s", messageID, jid.String())
+
+		return c.JSON(fiber.Map{"status": utils.T("message_deleted", messageID)})
+	})
+
+	app.Post("/chat/mark-read", markChatRead)
+
+	// Stored message with what a client needs to quote it, or 404 when unknown.
+	app.Get("/chat/:jid/message/:id", func(c *fiber.Ctx) error {
+		jid, err := whatsapp.ParseJID(c.Params("jid"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_jid", err)})
+		}
+
+		message, err := utils.FindChatHistoryMessage(jid.String(), c.Params("id"))
+		if errors.Is(err, utils.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("message_not_found", c.Params("id"), jid.String())})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_message_failed", err)})
+		}
+
+		return c.JSON(fiber.Map{
+			"message_id":   message.MessageID,
+			"chat_jid":     message.ChatJID,
+			"sender_jid":   message.SenderJID,
+			"from_me":      message.FromMe,
+			"timestamp":    message.Timestamp.Format(time.RFC3339),
+			"type":         message.Type,
+			"text":         message.Content,
+			"text_preview": textPreview(message.Content, 100),
+			"media_type":   message.MediaType,
+			"reactions":    message.Reactions,
+		})
+	})
+
+	// Media of a stored message, downloaded and decrypted on demand.
+	app.Get("/chat/:jid/message/:id/media", func(c *fiber.Ctx) error {
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+		jid, err := whatsapp.ParseJID(c.Params("jid"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_jid", err)})
+		}
+
+		media, err := whatsapp.DownloadStoredMedia(c.UserContext(), jid, c.Params("id"))
+		if errors.Is(err, whatsapp.ErrMediaNotStored) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("message_media_not_found", c.Params("id"), jid.String())})
+		}
+		if err != nil {
+			logrus.Errorf("Failed to download media of %s: %v", c.Params("id"), err)
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": utils.T("download_media_failed", err)})
+		}
+
+		fileName := media.FileName
+		if fileName == "" {
+			fileName = c.Params("id") + utils.MediaExtension(media.MimeType)
+		}
+		c.Set(fiber.HeaderContentType, media.MimeType)
+		c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+		return c.Send(media.Data)
+	})
+
+	app.Get("/chat/:jid/message/:id/status", func(c *fiber.Ctx) error {
+		jid, err := whatsapp.ParseJID(c.Params("jid"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_jid", err)})
+		}
+
+		receipt, err := utils.FindMessageReceipt(c.Params("id"))
+		if errors.Is(err, utils.ErrRecordNotFound) || (err == nil && receipt.ChatJID != jid.String()) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("message_not_sent_by_device", c.Params("id"), jid.String())})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_message_status_failed", err)})
+		}
+
+		return c.JSON(fiber.Map{
+			"message_id": receipt.MessageID,
+			"chat_jid":   receipt.ChatJID,
+			"status":     receipt.Status,
+			"timestamp":  receipt.Timestamp.Format(time.RFC3339),
+		})
+	})
+
+	// Stored messages of a chat, newest first, including history backfilled at login.
+	app.Get("/chat/:jid/messages", func(c *fiber.Ctx) error {
+		jid, err := whatsapp.ParseJID(c.Params("jid"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_jid", err)})
+		}
+		limit := c.QueryInt("limit", 50)
+		if limit < 1 || limit > 1000 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("limit_out_of_range", 1, 1000)})
+		}
+
+		messages, err := utils.GetChatHistory(jid.String(), limit)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_chat_history_failed", err)})
+		}
+		return c.JSON(fiber.Map{"chat_jid": jid.String(), "messages": messages})
+	})
+
+	// Compliance export of a chat, oldest first, e.g. GET /chat/<jid>/export?format=csv&from=2025-01-01&to=2025-01-31
+	app.Get("/chat/:jid/export", func(c *fiber.Ctx) error {
+		jid, err := whatsapp.ParseJID(c.Params("jid"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_jid", err)})
+		}
+		format := c.Query("format", "json")
+		if format != "json" && format != "csv" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_export_format")})
+		}
+		from, err := parseRangeDate(c.Query("from"), false)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_from", err)})
+		}
+		to, err := parseRangeDate(c.Query("to"), true)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_to", err)})
+		}
+
+		messages, err := chatExportMessages(jid, from, to)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_chat_history_failed", err)})
+		}
+
+		fileName := fmt.Sprintf("chat-%s.%s", jid.User, format)
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, fileName))
+		if format == "csv" {
+			c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		} else {
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		}
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			if err := writeChatExport(w, format, messages); err != nil {
+				logrus.Errorf("Failed to export chat %s: %v", jid.String(), err)
+			}
+		})
+		return nil
+	})
+
+	// Attachments of a chat export as a ZIP with a manifest.json, e.g.
+	// GET /chat/<jid>/export/media.zip?from=2025-01-01&to=2025-01-31&max_size=104857600
+	app.Get("/chat/:jid/export/media.zip", func(c *fiber.Ctx) error {
+		jid, err := whatsapp.ParseJID(c.Params("jid"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_jid", err)})
+		}
+		from, err := parseRangeDate(c.Query("from"), false)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_from", err)})
+		}
+		to, err := parseRangeDate(c.Query("to"), true)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_to", err)})
+		}
+		var maxSize int64
+		if value := c.Query("max_size"); value != "" {
+			maxSize, err = strconv.ParseInt(value, 10, 64)
+			if err != nil || maxSize < 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_max_size", value)})
+			}
+		}
+
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+
+		messages, err := chatExportMessages(jid, from, to)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("read_chat_history_failed", err)})
+		}
+
+		c.Set(fiber.HeaderContentType, "application/zip")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="chat-%s-media.zip"`, jid.User))
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			download := func(id string) (whatsapp.StoredMedia, error) {
+				return whatsapp.DownloadStoredMedia(context.Background(), jid, id)
+			}
+			if err := writeChatMediaArchive(w, messages, maxSize, download); err != nil {
+				logrus.Errorf("Failed to export media of chat %s: %v", jid.String(), err)
+			}
+		})
+		return nil
+	})
+
+	// WhatsApp Business labels
+	app.Get("/labels", func(c *fiber.Ctx) error {
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+
+		labels, err := whatsapp.Labels(c.UserContext())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("fetch_labels_failed", err)})
+		}
+		return c.JSON(fiber.Map{"labels": labels})
+	})
+
+	app.Post("/labels", func(c *fiber.Ctx) error {
+		var request struct {
+			Name  string `json:"name"`
+			Color int32  `json:"color"` // index in WhatsApp's label palette, 0-19
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+		if strings.TrimSpace(request.Name) == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("label_name_required")})
+		}
+
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+
+		label, err := whatsapp.CreateLabel(c.UserContext(), strings.TrimSpace(request.Name), request.Color)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("create_label_failed", err)})
+		}
+		return c.JSON(label)
+	})
+
+	app.Post("/chat/label", func(c *fiber.Ctx) error {
+		var request struct {
+			Phone   string `json:"phone"`
+			LabelID string `json:"label_id"`
+			Action  string `json:"action"` // "add" (default) or "remove"
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+		if request.Phone == "" || request.LabelID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_label_required")})
+		}
+		if request.Action == "" {
+			request.Action = "add"
+		}
+		if request.Action != "add" && request.Action != "remove" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_label_action")})
+		}
+
+		waCli := whatsapp.GetWaCli()
+		if waCli == nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+		}
+		if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+		}
+
+		jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
+		}
+
+		labeled := request.Action == "add"
+		if err := whatsapp.LabelChat(c.UserContext(), jid, request.LabelID, labeled); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("label_chat_failed", err)})
+		}
+		if labeled {
+			return c.JSON(fiber.Map{"status": utils.T("chat_labeled", request.LabelID, jid.String())})
+		}
+		return c.JSON(fiber.Map{"status": utils.T("chat_unlabeled", request.LabelID, jid.String())})
+	})
+
+	// Re-submit webhooks that failed every retry, e.g. POST /webhook/replay?from=2025-01-01T10:00:00Z&to=2025-01-01T12:00:00Z
+	app.Post("/webhook/replay", func(c *fiber.Ctx) error {
+		from, err := parseRangeDate(c.Query("from"), false)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_from", err)})
+		}
+		to, err := parseRangeDate(c.Query("to"), true)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_to", err)})
+		}
+
+		summary, err := whatsapp.ReplayWebhooks(from, to)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("replay_webhooks_failed", err)})
+		}
+		return c.JSON(summary)
+	})
+
+	// Submit the webhook of a stored message again, e.g. POST /webhook/resend-message?chat=<jid>&id=<message id>
+	app.Post("/webhook/resend-message", func(c *fiber.Ctx) error {
+		if c.Query("chat") == "" || c.Query("id") == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("chat_and_id_required")})
+		}
+		jid, err := whatsapp.ParseJID(c.Query("chat"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_jid", err)})
+		}
+
+		summary, err := whatsapp.ResendMessageWebhook(c.UserContext(), jid, c.Query("id"))
+		switch {
+		case errors.Is(err, utils.ErrRecordNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("message_not_found", c.Query("id"), jid.String())})
+		case errors.Is(err, whatsapp.ErrNoWebhookForChat):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": utils.T("no_webhook_for_chat", jid.String())})
+		case err != nil:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("resend_webhook_failed", err)})
+		}
+		return c.JSON(summary)
+	})
+
+	// Rotate the webhook signing secret, e.g. POST /webhook/secret {"secret": "...", "grace_seconds": 3600}.
+	// An empty secret generates one. The response holds the secret, so this is
+	// only served behind basic auth.
+	app.Post("/webhook/secret", func(c *fiber.Ctx) error {
+		if len(config.AppBasicAuthCredential) == 0 {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": utils.T("webhook_secret_requires_auth")})
+		}
+		var request struct {
+			Secret       string `json:"secret"`
+			GraceSeconds int    `json:"grace_seconds"`
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+		grace := time.Duration(request.GraceSeconds) * time.Second
+		if err := whatsapp.ValidateWebhookSecretRotation(request.Secret, grace); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		rotation, err := whatsapp.RotateWebhookSecret(request.Secret, grace)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("rotate_webhook_secret_failed", err)})
+		}
+		return c.JSON(fiber.Map{"secret": rotation.Secret, "previous_valid_until": rotation.PreviousUntil, "rotated_at": rotation.RotatedAt})
+	})
+
+	// Latest warnings and errors, newest first, e.g. GET /logs/recent?limit=50.
+	// Log lines may hold phone numbers, so this is only served behind basic auth.
+	app.Get("/logs/recent", func(c *fiber.Ctx) error {
+		if len(config.AppBasicAuthCredential) == 0 {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": utils.T("logs_require_auth")})
+		}
+		limit := c.QueryInt("limit", 50)
+		if limit < 1 || limit > utils.RecentLogCapacity {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("limit_out_of_range", 1, utils.RecentLogCapacity)})
+		}
+		return c.JSON(fiber.Map{"entries": utils.RecentLogs.Recent(limit)})
+	})
+
+	// Establish encryption sessions before a campaign, e.g. POST /sessions/prewarm {"phones": ["5511..."]}
+	app.Post("/sessions/prewarm", func(c *fiber.Ctx) error {
+		var request struct {
+			Phones []string `json:"phones"`
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+		if len(request.Phones) == 0 || len(request.Phones) > prewarmMaxPhones {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("prewarm_phones_required", prewarmMaxPhones)})
+		}
+		if _, err := whatsapp.ConnectedClient(); err != nil {
+			return sendFailed(c, err, "client_not_connected")
+		}
+
+		results := make([]whatsapp.SessionReadiness, 0, len(request.Phones))
+		ready := 0
+		for _, phone := range request.Phones {
+			readiness := whatsapp.PrewarmSession(c.UserContext(), phone)
+			if readiness.Status == "ready" {
+				ready++
+			}
+			results = append(results, readiness)
+		}
+		return c.JSON(fiber.Map{"ready": ready, "results": results})
+	})
+
+	// Background goroutines and pending timers, e.g. GET /tasks
+	app.Get("/tasks", func(c *fiber.Ctx) error {
+		tasks := append(utils.DefaultTasks.List(), utils.DefaultScheduler.Tasks()...)
+		return c.JSON(fiber.Map{"tasks": tasks})
+	})
+
+	// Cancel a cancellable task or pending timer, e.g. DELETE /tasks/media-janitor-2
+	app.Delete("/tasks/:id", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		err := utils.DefaultTasks.Cancel(id)
+		if errors.Is(err, utils.ErrTaskNotFound) && utils.DefaultScheduler.Cancel(id) {
+			err = nil
+		}
+		switch {
+		case errors.Is(err, utils.ErrTaskNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("task_not_found", id)})
+		case errors.Is(err, utils.ErrTaskNotCancellable):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": utils.T("task_not_cancellable", id)})
+		}
+		return c.JSON(fiber.Map{"status": utils.T("task_cancelled", id)})
+	})
+
+	// Budget left under the outbound rate limit, so clients can pace themselves, e.g. GET /ratelimit
+	app.Get("/ratelimit", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"global": whatsapp.SendRateLimitStatus()})
+	})
+
+	// Disappearing timer applied to new chats, last set through this API
+	app.Get("/settings/default-disappearing", func(c *fiber.Ctx) error {
+		return c.JSON(whatsapp.GetDefaultDisappearing())
+	})
+
+	// Set the disappearing timer of new chats, e.g. {"seconds": 604800}, or {"seconds": 0} to turn it off
+	app.Post("/settings/default-disappearing", func(c *fiber.Ctx) error {
+		var request struct {
+			Seconds uint32 `json:"seconds"`
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+		}
+		if err := whatsapp.ValidateDefaultDisappearing(request.Seconds); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		setting, err := whatsapp.SetDefaultDisappearing(request.Seconds)
+		if err != nil {
+			return sendFailed(c, err, "set_default_disappearing_failed")
+		}
+		return c.JSON(setting)
+	})
+
+	// Counters in the Prometheus text format, e.g. GET /metrics
+	app.Get("/metrics", func(c *fiber.Ctx) error {
+		stats := whatsapp.GetRetryStats()
+		c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4")
+		return c.SendString(fmt.Sprintf(
+			"# HELP whatsapp_message_retries_total Retry receipts answered by resending the message.\n"+
+				"# TYPE whatsapp_message_retries_total counter\n"+
+				"whatsapp_message_retries_total %d\n"+
+				"# HELP whatsapp_message_retries_missed_total Retry receipts for messages no longer known.\n"+
+				"# TYPE whatsapp_message_retries_missed_total counter\n"+
+				"whatsapp_message_retries_missed_total %d\n"+
+				"# HELP whatsapp_undecryptable_messages_total Received messages that could not be decrypted.\n"+
+				"# TYPE whatsapp_undecryptable_messages_total counter\n"+
+				"whatsapp_undecryptable_messages_total %d\n",
+			stats.Resent, stats.Missed, stats.Undecryptable))
+	})
+
+	// Click-to-chat link with prefilled text, e.g. GET /util/wa-link?phone=+62 812-3456&text=Hello
+	app.Get("/util/wa-link", func(c *fiber.Ctx) error {
+		link, err := utils.WaMeLink(c.Query("phone"), c.Query("text"))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
+		}
+		return c.JSON(fiber.Map{"link": link})
+	})
+
+	// Serve media saved by the webhook, e.g. GET /files?path=statics/media/<file>
+	app.Get("/files", func(c *fiber.Ctx) error {
+		requested := c.Query("path")
+		if requested == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("path_required")})
+		}
+
+		filePath, err := resolveMediaPath(requested)
+		if err != nil {
+			logrus.Warnf("Rejected media path %q: %v", requested, err)
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": utils.T("path_outside_media")})
+		}
+
+		info, err := os.Stat(filePath)
+		if err != nil || info.IsDir() {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("file_not_found")})
+		}
+
+		return c.SendFile(filePath)
+	})
+
+	// Pairing QR code for headless installs, e.g. curl -u user:pass 'localhost:3000/login/qr?format=ascii'.
+	// format is png (default), or ascii/text for a terminal; invert=true suits
+	// terminals with a dark background.
+	app.Get("/login/qr", func(c *fiber.Ctx) error {
+		format := c.Query("format", "png")
+		if format != "png" && format != "ascii" && format != "text" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("unsupported_qr_format")})
+		}
+		// Requesting a QR code reconnects the client, so check first rather
+		// than drop a working session.
+		if waCli := whatsapp.GetWaCli(); waCli != nil && waCli.Store.ID != nil {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": utils.T("already_paired")})
+		}
+
+		response, err := appUsecase.Login(c.UserContext())
+		switch {
+		case errors.Is(err, pkgError.ErrAlreadyLoggedIn), errors.Is(err, pkgError.ErrSessionSaved):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": utils.T("already_paired")})
+		case err != nil:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("qr_failed", err)})
+		}
+
+		c.Set("X-QR-Duration", strconv.Itoa(int(response.Duration)))
+		if format == "png" {
+			png, err := qrcode.Encode(response.Code, qrcode.Medium, 512)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("qr_failed", err)})
+			}
+			c.Set(fiber.HeaderContentType, "image/png")
+			return c.Send(png)
+		}
+		qr, err := utils.TerminalQR(response.Code, c.QueryBool("invert"))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("qr_failed", err)})
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+		return c.SendString(qr)
+	})
+
+	rest.InitRestApp(app, appUsecase)
+	rest.InitRestSend(app, sendUsecase)
+	rest.InitRestUser(app, userUsecase)
+	rest.InitRestMessage(app, messageUsecase)
+	rest.InitRestGroup(app, groupUsecase)
+	rest.InitRestNewsletter(app, newsletterUsecase)
+	rest.InitRestCommunity(app, communityUsecase)
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.Render("views/index", fiber.Map{
+			"AppHost":        fmt.Sprintf("%s://%s", c.Protocol(), c.Hostname()),
+			"AppVersion":     config.AppVersion,
+			"BasicAuthToken": c.UserContext().Value(middleware.AuthorizationValue("BASIC_AUTH")),
+			"MaxFileSize":    humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize)),
+			"MaxVideoSize":   humanize.Bytes(uint64(config.WhatsappSettingMaxVideoSize)),
+		})
+	})
+
+	websocket.RegisterRoutes(app, appUsecase)
+	go websocket.RunHub()
 
 	go helpers.SetAutoConnectAfterBooting(appUsecase)
 	go helpers.SetAutoReconnectChecking(whatsapp.GetWaCli())
 	if config.WhatsappChatStorage {
 		go helpers.StartAutoFlushChatStorage()
 	}
+	helpers.StartMediaJanitor()
+
+	app.Hooks().OnShutdown(func() error {
+		utils.DefaultScheduler.Stop()
+		utils.DefaultTasks.CancelAll()
+		return nil
+	})
+
+	// Shut down gracefully on SIGINT/SIGTERM, so the hooks above run and
+	// in-flight requests finish.
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+		logrus.Info("Shutting down")
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := app.ShutdownWithContext(ctx); err != nil {
+			logrus.Errorf("Failed to shut down gracefully: %v", err)
+		}
+	}()
 
 	if err := app.Listen(":" + config.AppPort); err != nil {
 		log.Fatalln("Failed to start: ", err.Error())
 	}
 }
 
+// chatTextRequest is the body of POST /chat/send/text.
+//
+//	phone                 user phone or JID, or group JID (required)
+//	message               text to send (required unless template is set)
+//	template              text/template to render instead of message, e.g. "Hi {{name}}"
+//	variables             values for the template placeholders
+//	strict                reject templates using undefined variables (default true)
+//	reply_message_id      ID of the message to quote
+//	reply_participant     author of the quoted message, needed for group replies not in chat storage
+//	reply_chat            chat the quoted message belongs to when it is not the target chat
+//	mentions              phones or JIDs to mention; "@<phone>" in the text is detected as well
+//	link_preview          attach a preview of the first URL in the message
+//	disable_link_preview  render URLs as plain text, cannot be combined with link_preview
+//	ephemeral_seconds     disappear after 86400, 604800 or 7776000 seconds
+//	skip_signature        do not append the configured message signature
+//	skip_lookup           send to phone as given, without resolving it with IsOnWhatsApp
+//	auto_delete_after     revoke the message for everyone this many seconds after sending
+//	message_id            send under this ID instead of a generated one, 16 to 64 uppercase hex characters
+//	mention_all           mention every other group member, the account must be a group admin
+type chatTextRequest struct {
+	Phone              string            `json:"phone"`
+	Message            string            `json:"message"`
+	Template           string            `json:"template"`
+	Variables          map[string]string `json:"variables"`
+	Strict             *bool             `json:"strict"`
+	ReplyMessageID     string            `json:"reply_message_id"`
+	ReplyParticipant   string            `json:"reply_participant"`
+	ReplyChat          string            `json:"reply_chat"`
+	Mentions           []string          `json:"mentions"`
+	LinkPreview        bool              `json:"link_preview"`
+	EphemeralSeconds   uint32            `json:"ephemeral_seconds"`
+	SkipSignature      bool              `json:"skip_signature"`
+	EchoAdReferral     bool              `json:"echo_ad_referral"`
+	SkipLookup         bool              `json:"skip_lookup"` // Phone is already the registered JID
+	AutoDeleteAfter    int               `json:"auto_delete_after"`
+	MessageID          string            `json:"message_id"`
+	MentionAll         bool              `json:"mention_all"`
+	DisableLinkPreview bool              `json:"disable_link_preview"`
+}
+
+// shutdownTimeout bounds how long in-flight requests may take to finish once a
+// shutdown signal arrives.
+const shutdownTimeout = 30 * time.Second
+
+// prewarmMaxPhones caps one prewarm request, a large list holds the request open
+// for a long time.
+const prewarmMaxPhones = 256
+
+const (
+	defaultAckTimeout = 10 * time.Second
+	maxAckTimeout     = 60 * time.Second
+)
+
+// callCacheEntry is a call rejection remembered by /call-ended.
+type callCacheEntry struct {
+	Key        string    `json:"key"`
+	CallID     string    `json:"call_id"`
+	Phone      string    `json:"Phone"`
+	StoredAt   time.Time `json:"stored_at"`
+	AgeSeconds int       `json:"age_seconds"`
+}
+
+// callCacheEntries lists the call rejections remembered by /call-ended, oldest first.
+func callCacheEntries() []callCacheEntry {
+	entries := []callCacheEntry{}
+	callWebhookCache.Range(func(key, value any) bool {
+		storedAt, _ := value.(time.Time)
+		callID, phone, _ := strings.Cut(key.(string), ":")
+		entries = append(entries, callCacheEntry{
+			Key:        key.(string),
+			CallID:     callID,
+			Phone:      phone,
+			StoredAt:   storedAt,
+			AgeSeconds: int(time.Since(storedAt).Seconds()),
+		})
+		return true
+	})
+	slices.SortFunc(entries, func(a, b callCacheEntry) int { return a.StoredAt.Compare(b.StoredAt) })
+	return entries
+}
+
+// listCallCache lists the call rejections deduped by /call-ended, oldest first,
+// e.g. GET /calls/cache.
+func listCallCache(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"ttl_seconds": int(cacheTTL.Seconds()), "entries": callCacheEntries()})
+}
+
+// clearCallCache forgets deduped call rejections so a repeat call is rejected
+// again, e.g. DELETE /calls/cache, or DELETE /calls/cache?key=<call_id>:<Phone>
+// for one.
+func clearCallCache(c *fiber.Ctx) error {
+	if key := c.Query("key"); key != "" {
+		if _, ok := callWebhookCache.LoadAndDelete(key); !ok {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": utils.T("call_cache_key_not_found", key)})
+		}
+		utils.DefaultScheduler.Cancel("call-ended:" + key)
+		return c.JSON(fiber.Map{"cleared": 1})
+	}
+
+	cleared := 0
+	callWebhookCache.Range(func(key, _ any) bool {
+		callWebhookCache.Delete(key)
+		utils.DefaultScheduler.Cancel("call-ended:" + key.(string))
+		cleared++
+		return true
+	})
+	return c.JSON(fiber.Map{"cleared": cleared})
+}
+
+// markChatRead sends read (or played) receipts for messages of one chat, e.g.
+// POST /chat/mark-read {"Phone": "628123", "message_ids": ["3EB0..."]}. IDs
+// stored for another chat are refused rather than sent with the wrong chat.
+func markChatRead(c *fiber.Ctx) error {
+	var request struct {
+		Phone      string   `json:"Phone"`
+		MessageID  string   `json:"message_id"`
+		MessageIDs []string `json:"message_ids"`
+		Sender     string   `json:"sender"`
+		Played     bool     `json:"played"`
+	}
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+	}
+
+	messageIDs := request.MessageIDs
+	if request.MessageID != "" && !slices.Contains(messageIDs, request.MessageID) {
+		messageIDs = append(messageIDs, request.MessageID)
+	}
+	if request.Phone == "" || len(messageIDs) == 0 || slices.Contains(messageIDs, "") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_and_message_id_required")})
+	}
+
+	chatJID, err := whatsapp.ResolveRecipientJID(request.Phone)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
+	}
+
+	var senderJID types.JID
+	if request.Sender != "" {
+		senderJID, err = whatsapp.ParseJID(request.Sender)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_sender_jid", err)})
+		}
+	}
+
+	// In groups, stored messages reveal the sender of each id, since a read
+	// receipt names a single sender.
+	stored, err := utils.FindChatHistoryMessages(chatJID.String(), messageIDs)
+	if err != nil {
+		logrus.Warnf("Failed to look up messages to mark as read: %v", err)
+	}
+	var unknown []string
+	for _, id := range messageIDs {
+		if _, ok := stored[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		elsewhere, err := utils.FindChatHistoryMessages("", unknown)
+		if err != nil {
+			logrus.Warnf("Failed to look up messages to mark as read: %v", err)
+		}
+		for _, id := range unknown {
+			if message, ok := elsewhere[id]; ok {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("message_not_in_chat", id, message.ChatJID, chatJID.String())})
+			}
+		}
+	}
+	batches := make(map[types.JID][]types.MessageID)
+	var senders []types.JID
+	for _, id := range messageIDs {
+		sender := senderJID
+		if message, ok := stored[id]; ok && request.Sender == "" && message.SenderJID != "" {
+			if sender, err = types.ParseJID(message.SenderJID); err != nil {
+				sender = senderJID
+			}
+		}
+		if sender.IsEmpty() && chatJID.Server == types.GroupServer {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("sender_required_for_group")})
+		}
+		if _, ok := batches[sender]; !ok {
+			senders = append(senders, sender)
+		}
+		batches[sender] = append(batches[sender], types.MessageID(id))
+	}
+
+	waCli := whatsapp.GetWaCli()
+	if waCli == nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+	}
+
+	if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+	}
+
+	timestamp := time.Now()
+
+	var receiptTypeExtra []types.ReceiptType
+	if request.Played {
+		receiptTypeExtra = append(receiptTypeExtra, types.ReceiptTypePlayed)
+	} else {
+		receiptTypeExtra = append(receiptTypeExtra, types.ReceiptTypeRead)
+	}
+
+	marked := 0
+	for _, sender := range senders {
+		ids := batches[sender]
+		logrus.Debugf("Marking messages %v as read in chat %s with sender %s, played: %v", ids, chatJID.String(), sender.String(), request.Played)
+		err = waCli.MarkRead(ids, timestamp, chatJID, sender, receiptTypeExtra...)
+		if err != nil {
+			logrus.Errorf("Failed to mark messages %v as read in chat %s: %v", ids, chatJID.String(), err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("mark_read_failed", err), "count": marked})
+		}
+		marked += len(ids)
+	}
+	logrus.Infof("%d messages marked as read in chat %s", marked, chatJID.String())
+
+	if len(messageIDs) == 1 {
+		return c.JSON(fiber.Map{"status": utils.T("message_marked_read", messageIDs[0]), "count": marked})
+	}
+	return c.JSON(fiber.Map{"status": utils.T("messages_marked_read", marked), "count": marked})
+}
+
+// sendResponse answers a send request. With ?wait_for_ack=true it first waits
+// up to ?ack_timeout seconds (default 10, at most 60) for the recipient's ack
+// and replies 200 with it, or 202 when none arrived in time.
+func sendResponse(c *fiber.Ctx, body fiber.Map, messageID string) error {
+	if !c.QueryBool("wait_for_ack") {
+		return c.JSON(body)
+	}
+
+	timeout := defaultAckTimeout
+	if seconds := c.QueryInt("ack_timeout"); seconds > 0 {
+		timeout = min(time.Duration(seconds)*time.Second, maxAckTimeout)
+	}
+	ack, acked := whatsapp.WaitForAck(c.UserContext(), messageID, timeout)
+	body["ack"] = ack
+	if !acked {
+		return c.Status(fiber.StatusAccepted).JSON(body)
+	}
+	return c.JSON(body)
+}
+
+// sendRequestPrompt sends a location or phone number request, e.g.
+// {"Phone": "628123", "message": "Where should we deliver?"}.
+func sendRequestPrompt(c *fiber.Ctx, kind string) error {
+	var request struct {
+		Phone   string `json:"Phone"`
+		Message string `json:"message"`
+	}
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_request_body")})
+	}
+	if request.Phone == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("phone_required")})
+	}
+
+	waCli := whatsapp.GetWaCli()
+	if waCli == nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_initialized")})
+	}
+	if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T("client_not_connected")})
+	}
+	jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": utils.T("invalid_phone", err)})
+	}
+
+	var resp whatsmeow.SendResponse
+	status := "request_phone_sent"
+	if kind == "location" {
+		if request.Message == "" {
+			request.Message = utils.T("request_location_default")
+		}
+		resp, err = whatsapp.RequestLocation(c.UserContext(), jid, request.Message)
+		status = "request_location_sent"
+	} else {
+		resp, err = whatsapp.RequestPhoneNumber(c.UserContext(), jid)
+	}
+	if err != nil {
+		return sendFailed(c, err, "send_message_failed")
+	}
+	return sendResponse(c, fiber.Map{"status": utils.T(status), "message_id": resp.ID}, resp.ID)
+}
+
+// sendFailed answers a failed send. Recipients blocked by the recipient policy
+// get 403 with the RECIPIENT_NOT_ALLOWED code, reused message IDs get 409, sends
+// interrupted by a reconnect get 503, anything else is a 500 with the message of
+// the given catalog key.
+func sendFailed(c *fiber.Ctx, err error, key string) error {
+	var denied pkgError.RecipientNotAllowed
+	if errors.As(err, &denied) {
+		return errorResponse(c, denied.StatusCode(), err)
+	}
+	var invalid pkgError.InvalidJID
+	if errors.As(err, &invalid) {
+		return errorResponse(c, invalid.StatusCode(), err)
+	}
+	if errors.Is(err, whatsapp.ErrDuplicateMessageID) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, whatsapp.ErrReconnecting) {
+		c.Set(fiber.HeaderRetryAfter, "5")
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": utils.T("client_reconnecting")})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": utils.T(key, err)})
+}
+
+// errorResponse answers with err, adding its code when it is a recipient policy denial.
+func errorResponse(c *fiber.Ctx, status int, err error) error {
+	body := fiber.Map{"error": err.Error()}
+	var denied pkgError.RecipientNotAllowed
+	if errors.As(err, &denied) {
+		body["code"] = denied.ErrCode()
+	}
+	return c.Status(status).JSON(body)
+}
+
+// textPreview shortens text to at most limit runes, marking the cut with an ellipsis.
+func textPreview(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// parseRangeDate accepts RFC3339 or YYYY-MM-DD. A bare date used as the end of
+// the range includes that whole day.
+func parseRangeDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected RFC3339 or YYYY-MM-DD, got %q", value)
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+	return parsed, nil
+}
+
+// chatExportMessages returns the stored messages of a chat within the range,
+// oldest first. Zero bounds are open.
+func chatExportMessages(jid types.JID, from, to time.Time) ([]utils.ChatHistoryMessage, error) {
+	stored, err := utils.GetChatHistory(jid.String(), 0)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]utils.ChatHistoryMessage, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		timestamp := stored[i].Timestamp
+		if (!from.IsZero() && timestamp.Before(from)) || (!to.IsZero() && timestamp.After(to)) {
+			continue
+		}
+		messages = append(messages, stored[i])
+	}
+	return messages, nil
+}
+
+// mediaManifestEntry describes one attachment of a media archive. File is empty
+// when the media could not be included, with Error saying why.
+type mediaManifestEntry struct {
+	MessageID string    `json:"message_id"`
+	Timestamp time.Time `json:"timestamp"`
+	SenderJID string    `json:"sender_jid,omitempty"`
+	FromMe    bool      `json:"from_me"`
+	MimeType  string    `json:"mime_type"`
+	File      string    `json:"file,omitempty"`
+	Size      int       `json:"size,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// writeChatMediaArchive writes the attachments of messages into a ZIP, followed
+// by a manifest.json mapping message IDs to file names. Once maxSize bytes of
+// media (0 for no cap) would be exceeded, the remaining attachments are listed
+// in the manifest as skipped. A file name already in the archive, as when a
+// message was stored twice, gets a numbered suffix.
+func writeChatMediaArchive(w *bufio.Writer, messages []utils.ChatHistoryMessage, maxSize int64, download func(id string) (whatsapp.StoredMedia, error)) error {
+	defer w.Flush()
+
+	archive := zip.NewWriter(w)
+	manifest := make([]mediaManifestEntry, 0)
+	files := make(map[string]bool)
+	var total int64
+	for _, message := range messages {
+		if message.MediaType == "" {
+			continue
+		}
+		entry := mediaManifestEntry{
+			MessageID: message.MessageID,
+			Timestamp: message.Timestamp.UTC(),
+			SenderJID: message.SenderJID,
+			FromMe:    message.FromMe,
+			MimeType:  message.MediaType,
+		}
+		if maxSize > 0 && total >= maxSize {
+			entry.Error = "skipped: max_size reached"
+			manifest = append(manifest, entry)
+			continue
+		}
+
+		media, err := download(message.MessageID)
+		if err != nil {
+			entry.Error = err.Error()
+			manifest = append(manifest, entry)
+			continue
+		}
+		if maxSize > 0 && total+int64(len(media.Data)) > maxSize {
+			total = maxSize
+			entry.Error = "skipped: max_size reached"
+			manifest = append(manifest, entry)
+			continue
+		}
+
+		fileName := message.MessageID + utils.MediaExtension(media.MimeType)
+		if media.FileName != "" {
+			fileName = message.MessageID + "-" + filepath.Base(media.FileName)
+		}
+		extension := filepath.Ext(fileName)
+		base := strings.TrimSuffix(fileName, extension)
+		for n := 2; files[fileName]; n++ {
+			fileName = fmt.Sprintf("%s-%d%s", base, n, extension)
+		}
+		files[fileName] = true
+		entry.File = "media/" + fileName
+		entry.Size = len(media.Data)
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: entry.File, Method: zip.Deflate, Modified: message.Timestamp})
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(media.Data); err != nil {
+			return err
+		}
+		total += int64(len(media.Data))
+		manifest = append(manifest, entry)
+	}
+
+	file, err := archive.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// writeChatExport writes the messages as a JSON array or as CSV with a header row.
+func writeChatExport(w *bufio.Writer, format string, messages []utils.ChatHistoryMessage) error {
+	defer w.Flush()
+
+	if format == "csv" {
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{"message_id", "timestamp", "sender_jid", "from_me", "type", "text", "media_type", "media_path"})
+		for _, message := range messages {
+			_ = writer.Write([]string{
+				message.MessageID,
+				message.Timestamp.UTC().Format(time.RFC3339),
+				message.SenderJID,
+				fmt.Sprint(message.FromMe),
+				message.Type,
+				message.Content,
+				message.MediaType,
+				message.MediaPath,
+			})
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+
+	encoder := json.NewEncoder(w)
+	if _, err := w.WriteString("["); err != nil {
+		return err
+	}
+	for i, message := range messages {
+		if i > 0 {
+			if _, err := w.WriteString(","); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(message); err != nil {
+			return err
+		}
+	}
+	_, err := w.WriteString("]")
+	return err
+}
+
+var firstURLRegex = regexp.MustCompile(`https?://[^\s]+`)
+
+// chatTextSent is a sent text message and how many members mention_all
+// mentioned.
+type chatTextSent struct {
+	whatsmeow.SendResponse
+	Mentioned int
+}
+
+// sendChatText builds and sends a text message. On failure it returns the HTTP
+// status the handler should answer with.
+func sendChatText(ctx context.Context, request chatTextRequest) (chatTextSent, int, error) {
+	var resp chatTextSent
+	if request.Phone == "" {
+		return resp, fiber.StatusBadRequest, errors.New(utils.T("phone_required"))
+	}
+	if request.Template != "" {
+		strict := request.Strict == nil || *request.Strict
+		rendered, err := utils.RenderTemplate(request.Template, request.Variables, strict)
+		if err != nil {
+			return resp, fiber.StatusBadRequest, err
+		}
+		request.Message = rendered
+	}
+	if request.Message == "" {
+		return resp, fiber.StatusBadRequest, errors.New(utils.T("message_required"))
+	}
+	if request.LinkPreview && request.DisableLinkPreview {
+		return resp, fiber.StatusBadRequest, errors.New(utils.T("link_preview_conflict"))
+	}
+	autoDeleteAfter := time.Duration(request.AutoDeleteAfter) * time.Second
+	if autoDeleteAfter < 0 || autoDeleteAfter > whatsapp.MaxAutoDeleteDelay {
+		return resp, fiber.StatusBadRequest, errors.New(utils.T("auto_delete_after_invalid", int(whatsapp.MaxAutoDeleteDelay.Seconds())))
+	}
+
+	waCli := whatsapp.GetWaCli()
+	if waCli == nil {
+		return resp, fiber.StatusInternalServerError, errors.New(utils.T("client_not_initialized"))
+	}
+	if !waCli.IsConnected() || !waCli.IsLoggedIn() {
+		return resp, fiber.StatusInternalServerError, errors.New(utils.T("client_not_connected"))
+	}
+
+	jid, err := whatsapp.ResolveRecipientJID(request.Phone)
+	if err != nil {
+		return resp, fiber.StatusBadRequest, errors.New(utils.T("invalid_phone", err))
+	}
+	// Resolve the number once, so the auto-delete timer and the logs name the
+	// chat the message lands in.
+	if request.SkipLookup {
+		ctx = whatsapp.WithoutRecipientLookup(ctx)
+	}
+	if jid, err = whatsapp.ResolveRecipient(ctx, jid); err != nil {
+		var invalid pkgError.InvalidJID
+		if errors.As(err, &invalid) {
+			return resp, invalid.StatusCode(), err
+		}
+		return resp, fiber.StatusInternalServerError, errors.New(utils.T("send_message_failed", err))
+	}
+	ctx = whatsapp.WithoutRecipientLookup(ctx)
+
+	if request.EphemeralSeconds > 0 {
+		if err := whatsapp.ValidateEphemeral(waCli, jid, request.EphemeralSeconds); err != nil {
+			return resp, fiber.StatusBadRequest, err
+		}
+		ctx = whatsapp.WithEphemeral(ctx, request.EphemeralSeconds)
+	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return resp, fiber.StatusBadRequest, err
+	}
+
+	message := request.Message
+	var mentionAll []string
+	if request.MentionAll {
+		var tokens string
+		if mentionAll, tokens, err = whatsapp.MentionAll(jid); err != nil {
+			var invalid pkgError.ValidationError
+			if errors.As(err, &invalid) {
+				return resp, invalid.StatusCode(), err
+			}
+			return resp, fiber.StatusInternalServerError, errors.New(utils.T("mention_all_failed", err))
+		}
+		if tokens != "" {
+			message += "\n\n" + tokens
+		}
+	}
+
+	text := utils.AppendSignature(message, request.SkipSignature)
+	msg := &waProto.Message{
+		ExtendedTextMessage: &waProto.ExtendedTextMessage{
+			Text:        proto.String(text),
+			ContextInfo: &waProto.ContextInfo{},
+		},
+	}
+	contextInfo := msg.ExtendedTextMessage.ContextInfo
+
+	mentions := append(request.Mentions, utils.ContainsMention(request.Message)...)
+	mentions = append(mentions, mentionAll...)
+	for _, mention := range mentions {
+		mentionJID, err := whatsapp.ParseJID(mention)
+		if err != nil {
+			return resp, fiber.StatusBadRequest, errors.New(utils.T("invalid_mention", mention, err))
+		}
+		if !slices.Contains(contextInfo.MentionedJID, mentionJID.String()) {
+			contextInfo.MentionedJID = append(contextInfo.MentionedJID, mentionJID.String())
+		}
+	}
+
+	if request.ReplyChat != "" {
+		if request.ReplyMessageID == "" {
+			return resp, fiber.StatusBadRequest, errors.New(utils.T("reply_chat_requires_message_id"))
+		}
+		replyChat, err := whatsapp.ResolveRecipientJID(request.ReplyChat)
+		if err != nil {
+			return resp, fiber.StatusBadRequest, errors.New(utils.T("invalid_reply_chat", err))
+		}
+		quote, err := whatsapp.CrossChatQuote(replyChat, request.ReplyMessageID)
+		if errors.Is(err, utils.ErrRecordNotFound) {
+			return resp, fiber.StatusNotFound, errors.New(utils.T("reply_message_not_found", request.ReplyMessageID, replyChat.String()))
+		} else if err != nil {
+			return resp, fiber.StatusBadRequest, errors.New(utils.T("invalid_reply_participant", err))
+		}
+		contextInfo.StanzaID = quote.StanzaID
+		contextInfo.Participant = quote.Participant
+		contextInfo.RemoteJID = quote.RemoteJID
+		contextInfo.QuotedMessage = quote.QuotedMessage
+	} else if request.ReplyMessageID != "" {
+		participant := request.ReplyParticipant
+		quoted := ""
+		if record, err := utils.FindRecordFromStorage(request.ReplyMessageID); err == nil {
+			participant = record.JID
+			quoted = record.MessageContent
+		}
+		if participant == "" {
+			if jid.Server == types.GroupServer {
+				return resp, fiber.StatusBadRequest, errors.New(utils.T("reply_participant_required"))
+			}
+			participant = jid.String()
+		}
+		participantJID, err := whatsapp.ParseJID(participant)
+		if err != nil {
+			return resp, fiber.StatusBadRequest, errors.New(utils.T("invalid_reply_participant", err))
+		}
+		contextInfo.StanzaID = proto.String(request.ReplyMessageID)
+		contextInfo.Participant = proto.String(participantJID.String())
+		contextInfo.QuotedMessage = &waProto.Message{Conversation: proto.String(quoted)}
+	}
+
+	if request.EchoAdReferral && !whatsapp.ApplyAdReferral(jid, contextInfo) {
+		logrus.Warnf("No ad referral known for %s, sending without attribution", jid.String())
+	}
+
+	if request.DisableLinkPreview {
+		// An explicit NONE preview with no matched text makes clients render URLs as plain text.
+		msg.ExtendedTextMessage.PreviewType = waProto.ExtendedTextMessage_NONE.Enum()
+		msg.ExtendedTextMessage.MatchedText = proto.String("")
+	}
+
+	if request.LinkPreview {
+		if link := firstURLRegex.FindString(request.Message); link != "" {
+			metadata, err := utils.GetMetaDataFromURL(link)
+			if err != nil {
+				logrus.Warnf("Failed to fetch link preview for %s: %v", link, err)
+			} else {
+				msg.ExtendedTextMessage.MatchedText = proto.String(link)
+				msg.ExtendedTextMessage.Title = proto.String(metadata.Title)
+				msg.ExtendedTextMessage.Description = proto.String(metadata.Description)
+				msg.ExtendedTextMessage.JPEGThumbnail = metadata.ImageThumb
+			}
+		}
+	}
+
+	resp.SendResponse, err = whatsapp.SendMessage(ctx, jid, msg)
+	if err != nil {
+		logrus.Errorf("Failed to send text message to %s: %v", jid.String(), err)
+		var denied pkgError.RecipientNotAllowed
+		if errors.As(err, &denied) {
+			return resp, denied.StatusCode(), err
+		}
+		var invalid pkgError.InvalidJID
+		if errors.As(err, &invalid) {
+			return resp, invalid.StatusCode(), err
+		}
+		if errors.Is(err, whatsapp.ErrDuplicateMessageID) {
+			return resp, fiber.StatusConflict, errors.New(utils.T("duplicate_message_id", request.MessageID))
+		}
+		return resp, fiber.StatusInternalServerError, errors.New(utils.T("send_message_failed", err))
+	}
+	logrus.Infof("Text message sent successfully to %s", jid.String())
+	if autoDeleteAfter > 0 {
+		whatsapp.ScheduleAutoDelete(jid, resp.ID, autoDeleteAfter)
+	}
+	resp.Mentioned = len(mentionAll)
+	return resp, fiber.StatusOK, nil
+}
+
+// textSentBody is the response to a sent text, naming its auto-delete timer,
+// which DELETE /tasks/:id cancels, when one was requested, and how many members
+// mention_all mentioned.
+func textSentBody(status string, resp chatTextSent, request chatTextRequest) fiber.Map {
+	body := fiber.Map{"status": status, "message_id": resp.ID}
+	if resp.Mentioned > 0 {
+		body["mentioned"] = resp.Mentioned
+	}
+	if request.AutoDeleteAfter > 0 {
+		body["auto_delete_task"] = whatsapp.AutoDeleteKey(resp.ID)
+		body["auto_delete_at"] = resp.Timestamp.Add(time.Duration(request.AutoDeleteAfter) * time.Second).Format(time.RFC3339)
+	}
+	return body
+}
+
+// resolveMediaPath maps a path returned by the webhook (with or without the
+// media directory prefix) to a file inside config.PathMedia.
+func resolveMediaPath(requested string) (string, error) {
+	candidate := filepath.Clean(filepath.FromSlash(requested))
+	if filepath.IsAbs(candidate) {
+		base, err := filepath.Abs(config.PathMedia)
+		if err != nil {
+			return "", err
+		}
+		candidate, err = filepath.Rel(base, candidate)
+		if err != nil {
+			return "", err
+		}
+	} else if rel, err := filepath.Rel(filepath.Clean(config.PathMedia), candidate); err == nil && !strings.HasPrefix(rel, "..") {
+		candidate = rel
+	}
+	return utils.SafeJoin(config.PathMedia, candidate)
+}
+
 func determineMimeType(filename string) string {
 	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
 	switch ext {
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestWriteChatMediaArchive(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	messages := []utils.ChatHistoryMessage{
+		{MessageID: "IMG1", MediaType: "image/jpeg", Timestamp: now},
+		{MessageID: "TEXT1", Timestamp: now},
+		{MessageID: "GONE1", MediaType: "video/mp4", Timestamp: now},
+		// The same message stored twice must not produce two files of one name.
+		{MessageID: "IMG1", MediaType: "image/jpeg", Timestamp: now},
+	}
+	download := func(id string) (whatsapp.StoredMedia, error) {
+		if id == "GONE1" {
+			return whatsapp.StoredMedia{}, errors.New("media expired")
+		}
+		return whatsapp.StoredMedia{Data: []byte("jpeg " + id), MimeType: "image/jpeg"}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := writeChatMediaArchive(bufio.NewWriter(&buf), messages, 0, download); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		if _, ok := files[file.Name]; ok {
+			t.Errorf("archive holds %s twice", file.Name)
+		}
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+		files[file.Name] = string(data)
+	}
+
+	var manifest []mediaManifestEntry
+	if err := json.Unmarshal([]byte(files["manifest.json"]), &manifest); err != nil {
+		t.Fatalf("manifest.json: %v", err)
+	}
+	if len(manifest) != 3 {
+		t.Fatalf("manifest = %+v, want the three media messages", manifest)
+	}
+	if manifest[0].File == manifest[2].File || files[manifest[2].File] != "jpeg IMG1" {
+		t.Errorf("duplicate files = %q and %q, want distinct names holding the media", manifest[0].File, manifest[2].File)
+	}
+	if manifest[1].File != "" || manifest[1].Error != "media expired" {
+		t.Errorf("failed download = %+v, want it listed with its error and no file", manifest[1])
+	}
+	if len(archive.File) != 3 {
+		t.Errorf("archive has %d files, want two media files and the manifest", len(archive.File))
+	}
+}
+
+func TestMarkChatReadRefusesMessagesOfAnotherChat(t *testing.T) {
+	origStorage, origHistory := config.WhatsappChatStorage, config.PathChatHistory
+	defer func() { config.WhatsappChatStorage, config.PathChatHistory = origStorage, origHistory }()
+	config.WhatsappChatStorage = true
+	config.PathChatHistory = filepath.Join(t.TempDir(), "chat_history.csv")
+
+	if _, err := utils.RecordChatHistory([]utils.ChatHistoryMessage{
+		{ChatJID: "628111@s.whatsapp.net", MessageID: "M1", SenderJID: "628111@s.whatsapp.net", Content: "hi"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Post("/chat/mark-read", markChatRead)
+	req := httptest.NewRequest("POST", "/chat/mark-read", strings.NewReader(`{"Phone": "628222@s.whatsapp.net", "message_ids": ["M1"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("status = %d (%v), want 400", resp.StatusCode, body)
+	}
+	if want := utils.T("message_not_in_chat", "M1", "628111@s.whatsapp.net", "628222@s.whatsapp.net"); body["error"] != want {
+		t.Errorf("error = %q, want %q", body["error"], want)
+	}
+}
+
+func TestCallCacheEntries(t *testing.T) {
+	callWebhookCache.Clear()
+	t.Cleanup(callWebhookCache.Clear)
+
+	now := time.Now()
+	callWebhookCache.Store("CALL2:628222", now)
+	callWebhookCache.Store("CALL1:628111", now.Add(-time.Minute))
+
+	entries := callCacheEntries()
+	if len(entries) != 2 {
+		t.Fatalf("callCacheEntries() = %+v, want 2 entries", entries)
+	}
+	if entries[0].Key != "CALL1:628111" || entries[0].CallID != "CALL1" || entries[0].Phone != "628111" {
+		t.Errorf("first entry = %+v, want the oldest rejection split into call and phone", entries[0])
+	}
+	if entries[0].AgeSeconds < 60 || entries[1].Key != "CALL2:628222" {
+		t.Errorf("entries = %+v, want oldest first with their age", entries)
+	}
+}
+
+func TestCallCacheRoutes(t *testing.T) {
+	callWebhookCache.Clear()
+	t.Cleanup(callWebhookCache.Clear)
+	callWebhookCache.Store("CALL1:628111", time.Now())
+	callWebhookCache.Store("CALL2:628222", time.Now())
+	callWebhookCache.Store("CALL3:628333", time.Now())
+
+	app := fiber.New()
+	app.Get("/calls/cache", listCallCache)
+	app.Delete("/calls/cache", clearCallCache)
+	call := func(method, target string) (int, map[string]any) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(method, target, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, body
+	}
+
+	status, body := call("GET", "/calls/cache")
+	if entries, _ := body["entries"].([]any); status != fiber.StatusOK || len(entries) != 3 {
+		t.Fatalf("GET /calls/cache = %d %v, want 3 entries", status, body)
+	}
+
+	status, body = call("DELETE", "/calls/cache?key=CALL1:628111")
+	if status != fiber.StatusOK || body["cleared"] != float64(1) {
+		t.Errorf("DELETE one key = %d %v, want 1 cleared", status, body)
+	}
+	if _, ok := callWebhookCache.Load("CALL1:628111"); ok {
+		t.Error("the cleared key is still cached")
+	}
+	if status, _ = call("DELETE", "/calls/cache?key=CALL1:628111"); status != fiber.StatusNotFound {
+		t.Errorf("DELETE an unknown key = %d, want 404", status)
+	}
+
+	status, body = call("DELETE", "/calls/cache")
+	if status != fiber.StatusOK || body["cleared"] != float64(2) {
+		t.Errorf("DELETE all = %d %v, want 2 cleared", status, body)
+	}
+	if entries := callCacheEntries(); len(entries) != 0 {
+		t.Errorf("callCacheEntries() after clearing = %+v, want none", entries)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainCommunity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/community"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
@@ -17,6 +18,8 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/usecase"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.mau.fi/whatsmeow"
@@ -41,6 +44,7 @@ var (
 	messageUsecase    domainMessage.IMessageUsecase
 	groupUsecase      domainGroup.IGroupUsecase
 	newsletterUsecase domainNewsletter.INewsletterUsecase
+	communityUsecase  domainCommunity.ICommunityUsecase
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -55,6 +59,7 @@ func init() {
 	utils.LoadConfig(".")
 
 	time.Local = time.UTC
+	logrus.AddHook(utils.RecentLogs)
 
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
@@ -84,6 +89,36 @@ func initEnvConfig() {
 	if envChatFlushInterval := viper.GetInt("APP_CHAT_FLUSH_INTERVAL"); envChatFlushInterval > 0 {
 		config.AppChatFlushIntervalDays = envChatFlushInterval
 	}
+	if envInboundMaxAge := viper.GetInt("MEDIA_INBOUND_MAX_AGE_MINUTES"); envInboundMaxAge > 0 {
+		config.MediaInboundMaxAgeMinutes = envInboundMaxAge
+	}
+	if envInboundMaxCount := viper.GetInt("MEDIA_INBOUND_MAX_COUNT"); envInboundMaxCount > 0 {
+		config.MediaInboundMaxCount = envInboundMaxCount
+	}
+	if envOutboundMaxAge := viper.GetInt("MEDIA_OUTBOUND_MAX_AGE_MINUTES"); envOutboundMaxAge > 0 {
+		config.MediaOutboundMaxAgeMinutes = envOutboundMaxAge
+	}
+	if envOutboundMaxCount := viper.GetInt("MEDIA_OUTBOUND_MAX_COUNT"); envOutboundMaxCount > 0 {
+		config.MediaOutboundMaxCount = envOutboundMaxCount
+	}
+	if envTrustedProxies := viper.GetString("APP_TRUSTED_PROXIES"); envTrustedProxies != "" {
+		config.AppTrustedProxies = strings.Split(envTrustedProxies, ",")
+	}
+	if envProxyHeader := viper.GetString("APP_PROXY_HEADER"); envProxyHeader != "" {
+		config.AppProxyHeader = envProxyHeader
+	}
+	if envAccessLog := viper.GetBool("APP_ACCESS_LOG"); envAccessLog {
+		config.AppAccessLog = envAccessLog
+	}
+	if envAccessLogFormat := viper.GetString("APP_ACCESS_LOG_FORMAT"); envAccessLogFormat != "" {
+		config.AppAccessLogFormat = envAccessLogFormat
+	}
+	if viper.IsSet("APP_ACCESS_LOG_EXCLUDE") {
+		config.AppAccessLogExclude = strings.Split(viper.GetString("APP_ACCESS_LOG_EXCLUDE"), ",")
+	}
+	if envLanguage := viper.GetString("APP_LANGUAGE"); envLanguage != "" {
+		config.AppLanguage = envLanguage
+	}
 
 	// Database settings
 	if envDBURI := viper.GetString("DB_URI"); envDBURI != "" {
@@ -98,15 +133,126 @@ func initEnvConfig() {
 		webhook := strings.Split(envWebhook, ",")
 		config.WhatsappWebhook = webhook
 	}
+	if envWebhookOutbound := viper.GetString("WHATSAPP_WEBHOOK_OUTBOUND"); envWebhookOutbound != "" {
+		config.WhatsappWebhookOutbound = strings.Split(envWebhookOutbound, ",")
+	}
+	if envWebhookRoutes := viper.GetString("WHATSAPP_WEBHOOK_ROUTES"); envWebhookRoutes != "" {
+		config.WhatsappWebhookRoutes = strings.Split(envWebhookRoutes, ",")
+	}
+	if envWebhookTimeout := viper.GetInt("WHATSAPP_WEBHOOK_TIMEOUT"); envWebhookTimeout > 0 {
+		config.WhatsappWebhookTimeoutSeconds = envWebhookTimeout
+	}
+	if envWebhookConns := viper.GetInt("WHATSAPP_WEBHOOK_MAX_CONNS_PER_HOST"); envWebhookConns > 0 {
+		config.WhatsappWebhookMaxConnsPerHost = envWebhookConns
+	}
+	if envHistorySync := viper.GetBool("WHATSAPP_WEBHOOK_HISTORY_SYNC"); envHistorySync {
+		config.WhatsappWebhookHistorySync = envHistorySync
+	}
+	if envIncludeRaw := viper.GetBool("WHATSAPP_WEBHOOK_INCLUDE_RAW"); envIncludeRaw {
+		config.WhatsappWebhookIncludeRaw = envIncludeRaw
+	}
+	if viper.IsSet("WHATSAPP_WEBHOOK_EDITED_MESSAGES") {
+		config.WhatsappWebhookEditedMessages = viper.GetBool("WHATSAPP_WEBHOOK_EDITED_MESSAGES")
+	}
+	if envPayloadVersion := viper.GetInt("WHATSAPP_WEBHOOK_PAYLOAD_VERSION"); envPayloadVersion > 0 {
+		config.WhatsappWebhookPayloadVersion = envPayloadVersion
+	}
+	if envMaxPayload := viper.GetInt("WHATSAPP_WEBHOOK_MAX_PAYLOAD_SIZE"); envMaxPayload > 0 {
+		config.WhatsappWebhookMaxPayloadSize = envMaxPayload
+	}
 	if envWebhookSecret := viper.GetString("WHATSAPP_WEBHOOK_SECRET"); envWebhookSecret != "" {
 		config.WhatsappWebhookSecret = envWebhookSecret
 	}
+	if envWebhookFormat := viper.GetString("WHATSAPP_WEBHOOK_FORMAT"); envWebhookFormat != "" {
+		config.WhatsappWebhookFormat = envWebhookFormat
+	}
 	if envAccountValidation := viper.GetBool("WHATSAPP_ACCOUNT_VALIDATION"); envAccountValidation {
 		config.WhatsappAccountValidation = envAccountValidation
 	}
+	if viper.IsSet("WHATSAPP_RECIPIENT_LOOKUP") {
+		config.WhatsappRecipientLookup = viper.GetBool("WHATSAPP_RECIPIENT_LOOKUP")
+	}
 	if envChatStorage := viper.GetBool("WHATSAPP_CHAT_STORAGE"); !envChatStorage {
 		config.WhatsappChatStorage = envChatStorage
 	}
+	if envSignature := viper.GetString("WHATSAPP_MESSAGE_SIGNATURE"); envSignature != "" {
+		config.WhatsappMessageSignature = envSignature
+	}
+	if envMaxInboundMedia := viper.GetInt64("WHATSAPP_MAX_INBOUND_MEDIA_SIZE"); envMaxInboundMedia > 0 {
+		config.WhatsappMaxInboundMediaSize = envMaxInboundMedia
+	}
+	if viper.IsSet("WHATSAPP_MEDIA_DOWNLOAD_TIMEOUT_SECONDS") {
+		config.WhatsappMediaDownloadTimeoutSeconds = viper.GetInt("WHATSAPP_MEDIA_DOWNLOAD_TIMEOUT_SECONDS")
+	}
+	if envInlineMedia := viper.GetInt64("WHATSAPP_WEBHOOK_INLINE_MEDIA_MAX_BYTES"); envInlineMedia > 0 {
+		config.WhatsappWebhookInlineMediaMaxBytes = envInlineMedia
+	}
+	if envMinDelay := viper.GetInt("WHATSAPP_SEND_MIN_DELAY_MS"); envMinDelay > 0 {
+		config.WhatsappSendMinDelayMs = envMinDelay
+	}
+	if envMaxDelay := viper.GetInt("WHATSAPP_SEND_MAX_DELAY_MS"); envMaxDelay > 0 {
+		config.WhatsappSendMaxDelayMs = envMaxDelay
+	}
+	if envPreSendTyping := viper.GetBool("WHATSAPP_PRE_SEND_TYPING"); envPreSendTyping {
+		config.WhatsappPreSendTyping = envPreSendTyping
+	}
+	if envRateLimit := viper.GetInt("WHATSAPP_SEND_RATE_LIMIT"); envRateLimit > 0 {
+		config.WhatsappSendRateLimit = envRateLimit
+	}
+	if envAutoMarkRead := viper.GetBool("WHATSAPP_AUTO_MARK_READ"); envAutoMarkRead {
+		config.WhatsappAutoMarkRead = envAutoMarkRead
+	}
+	if envAutoMarkReadExclude := viper.GetString("WHATSAPP_AUTO_MARK_READ_EXCLUDE"); envAutoMarkReadExclude != "" {
+		config.WhatsappAutoMarkReadExclude = strings.Split(envAutoMarkReadExclude, ",")
+	}
+	if envRecipientAllow := viper.GetString("WHATSAPP_RECIPIENT_ALLOW"); envRecipientAllow != "" {
+		config.WhatsappRecipientAllow = strings.Split(envRecipientAllow, ",")
+	}
+	if envRecipientDeny := viper.GetString("WHATSAPP_RECIPIENT_DENY"); envRecipientDeny != "" {
+		config.WhatsappRecipientDeny = strings.Split(envRecipientDeny, ",")
+	}
+	if envWebhookHeaders := viper.GetString("WHATSAPP_WEBHOOK_HEADERS"); envWebhookHeaders != "" {
+		config.WhatsappWebhookHeaders = strings.Split(envWebhookHeaders, ",")
+	}
+	if envWebhookIgnore := viper.GetString("WHATSAPP_WEBHOOK_IGNORE"); envWebhookIgnore != "" {
+		config.WhatsappWebhookIgnore = strings.Split(envWebhookIgnore, ",")
+	}
+	if envPresence := viper.GetString("WHATSAPP_PRESENCE_ON_CONNECT"); envPresence != "" {
+		config.WhatsappPresenceOnConnect = envPresence
+	}
+	if viper.IsSet("WHATSAPP_PRESENCE_DEBOUNCE_MS") {
+		config.WhatsappPresenceDebounceMs = viper.GetInt("WHATSAPP_PRESENCE_DEBOUNCE_MS")
+	}
+	if envRawMessage := viper.GetBool("WHATSAPP_RAW_MESSAGE"); envRawMessage {
+		config.WhatsappRawMessage = envRawMessage
+	}
+	if viper.IsSet("WHATSAPP_DOCUMENT_THUMBNAIL") {
+		config.WhatsappDocumentThumbnail = viper.GetBool("WHATSAPP_DOCUMENT_THUMBNAIL")
+	}
+	if viper.IsSet("WHATSAPP_DOWNLOAD_IMAGE") {
+		config.WhatsappDownloadImage = viper.GetBool("WHATSAPP_DOWNLOAD_IMAGE")
+	}
+	if viper.IsSet("WHATSAPP_DOWNLOAD_VIDEO") {
+		config.WhatsappDownloadVideo = viper.GetBool("WHATSAPP_DOWNLOAD_VIDEO")
+	}
+	if viper.IsSet("WHATSAPP_DOWNLOAD_AUDIO") {
+		config.WhatsappDownloadAudio = viper.GetBool("WHATSAPP_DOWNLOAD_AUDIO")
+	}
+	if viper.IsSet("WHATSAPP_DOWNLOAD_DOCUMENT") {
+		config.WhatsappDownloadDocument = viper.GetBool("WHATSAPP_DOWNLOAD_DOCUMENT")
+	}
+	if viper.IsSet("WHATSAPP_DOWNLOAD_STICKER") {
+		config.WhatsappDownloadSticker = viper.GetBool("WHATSAPP_DOWNLOAD_STICKER")
+	}
+	if viper.IsSet("WHATSAPP_TRANSCODE_MEDIA") {
+		config.WhatsappTranscodeMedia = viper.GetBool("WHATSAPP_TRANSCODE_MEDIA")
+	}
+	if viper.IsSet("WHATSAPP_TRANSCODE_VIDEO_MAX_BYTES") {
+		config.WhatsappTranscodeVideoMaxBytes = viper.GetInt64("WHATSAPP_TRANSCODE_VIDEO_MAX_BYTES")
+	}
+	if viper.IsSet("WHATSAPP_CONTACT_EXPORT") {
+		config.WhatsappContactExport = viper.GetBool("WHATSAPP_CONTACT_EXPORT")
+	}
 }
 
 func initFlags() {
@@ -142,6 +288,66 @@ func initFlags() {
 		config.AppChatFlushIntervalDays,
 		`the interval to flush the chat storage --chat-flush-interval <number> | example: --chat-flush-interval=7`,
 	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.MediaInboundMaxAgeMinutes,
+		"media-inbound-max-age", "",
+		config.MediaInboundMaxAgeMinutes,
+		`minutes downloaded inbound media is kept, 0 keeps it --media-inbound-max-age <number> | example: --media-inbound-max-age=1440`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.MediaInboundMaxCount,
+		"media-inbound-max-count", "",
+		config.MediaInboundMaxCount,
+		`most downloaded inbound media files kept, oldest removed first, 0 is unlimited --media-inbound-max-count <number> | example: --media-inbound-max-count=5000`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.MediaOutboundMaxAgeMinutes,
+		"media-outbound-max-age", "",
+		config.MediaOutboundMaxAgeMinutes,
+		`minutes outbound temp media files are kept, 0 keeps them --media-outbound-max-age <number> | example: --media-outbound-max-age=10`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.MediaOutboundMaxCount,
+		"media-outbound-max-count", "",
+		config.MediaOutboundMaxCount,
+		`most outbound temp media files kept, oldest removed first, 0 is unlimited --media-outbound-max-count <number> | example: --media-outbound-max-count=100`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.AppTrustedProxies,
+		"trusted-proxies", "",
+		config.AppTrustedProxies,
+		`reverse proxies whose X-Forwarded-* headers are trusted --trusted-proxies <ip/cidr> | example: --trusted-proxies="10.0.0.0/8,127.0.0.1"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.AppProxyHeader,
+		"proxy-header", "",
+		config.AppProxyHeader,
+		`header carrying the client IP when the request comes from a trusted proxy --proxy-header <string> | example: --proxy-header="X-Real-IP"`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.AppAccessLog,
+		"access-log", "",
+		config.AppAccessLog,
+		`log every HTTP request without enabling debug logs --access-log <true/false> | example: --access-log=true`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.AppAccessLogFormat,
+		"access-log-format", "",
+		config.AppAccessLogFormat,
+		`access log line format using fiber logger tags --access-log-format <string> | example: --access-log-format="${ip} ${status} ${method} ${path} ${latency}\n"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.AppAccessLogExclude,
+		"access-log-exclude", "",
+		config.AppAccessLogExclude,
+		`paths left out of the access log --access-log-exclude <paths> | example: --access-log-exclude="/health,/metrics"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.AppLanguage,
+		"language", "",
+		config.AppLanguage,
+		`language of response messages --language <en/pt> | example: --language=pt`,
+	)
 
 	// Database flags
 	rootCmd.PersistentFlags().StringVarP(
@@ -164,30 +370,253 @@ func initFlags() {
 		config.WhatsappWebhook,
 		`forward event to webhook --webhook <string> | example: --webhook="https://yourcallback.com/callback"`,
 	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappWebhookOutbound,
+		"webhook-outbound", "",
+		config.WhatsappWebhookOutbound,
+		`also post every sent message to this webhook as a "message_sent" event --webhook-outbound <string> | example: --webhook-outbound="https://yourcallback.com/audit"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappWebhookRoutes,
+		"webhook-route", "",
+		config.WhatsappWebhookRoutes,
+		`route messages of matching chats to specific webhooks instead of --webhook --webhook-route <pattern=url> | example: --webhook-route="*@g.us=https://yourcallback.com/groups"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappWebhookHeaders,
+		"webhook-header", "",
+		config.WhatsappWebhookHeaders,
+		`extra header for webhook requests, prefix with "<url>|" to send it to one webhook only --webhook-header <header> | example: --webhook-header="Authorization: Bearer token"`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookMaxPayloadSize,
+		"webhook-max-payload-size", "",
+		config.WhatsappWebhookMaxPayloadSize,
+		`shrink webhook bodies above this many bytes by dropping inline media and truncating text --webhook-max-payload-size <number> | example: --webhook-max-payload-size=1048576`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookHistorySync,
+		"webhook-history-sync", "",
+		config.WhatsappWebhookHistorySync,
+		`send a history_sync summary webhook when past conversations are backfilled --webhook-history-sync <true/false> | example: --webhook-history-sync=true`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookIncludeRaw,
+		"webhook-include-raw", "",
+		config.WhatsappWebhookIncludeRaw,
+		`include the full raw message proto and info under "raw" in webhooks, may contain sensitive data --webhook-include-raw <true/false> | example: --webhook-include-raw=true`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookEditedMessages,
+		"webhook-edited-messages", "",
+		config.WhatsappWebhookEditedMessages,
+		`forward edits as "edit_message" events with the old and new text --webhook-edited-messages <true/false> | example: --webhook-edited-messages=false`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookPayloadVersion,
+		"webhook-payload-version", "",
+		config.WhatsappWebhookPayloadVersion,
+		`webhook body layout to send, 1 (original) or 2 (normalized snake_case keys) --webhook-payload-version <number> | example: --webhook-payload-version=2`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookTimeoutSeconds,
+		"webhook-timeout", "",
+		config.WhatsappWebhookTimeoutSeconds,
+		`timeout in seconds for each webhook request --webhook-timeout <number> | example: --webhook-timeout=10`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookMaxConnsPerHost,
+		"webhook-max-conns", "",
+		config.WhatsappWebhookMaxConnsPerHost,
+		`maximum concurrent connections to a single webhook host --webhook-max-conns <number> | example: --webhook-max-conns=20`,
+	)
 	rootCmd.PersistentFlags().StringVarP(
 		&config.WhatsappWebhookSecret,
 		"webhook-secret", "",
 		config.WhatsappWebhookSecret,
 		`secure webhook request --webhook-secret <string> | example: --webhook-secret="super-secret-key"`,
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookFormat,
+		"webhook-format", "",
+		config.WhatsappWebhookFormat,
+		`webhook body encoding, json or form (application/x-www-form-urlencoded) --webhook-format <string> | example: --webhook-format="form"`,
+	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.WhatsappAccountValidation,
 		"account-validation", "",
 		config.WhatsappAccountValidation,
 		`enable or disable account validation --account-validation <true/false> | example: --account-validation=true`,
 	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappRecipientLookup,
+		"recipient-lookup", "",
+		config.WhatsappRecipientLookup,
+		`resolve phone numbers to their registered JID before sending, disable when clients only send known-good JIDs --recipient-lookup <true/false> | example: --recipient-lookup=false`,
+	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.WhatsappChatStorage,
 		"chat-storage", "",
 		config.WhatsappChatStorage,
 		`enable or disable chat storage --chat-storage <true/false>. If you disable this, reply feature maybe not working properly | example: --chat-storage=true`,
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMessageSignature,
+		"message-signature", "",
+		config.WhatsappMessageSignature,
+		`signature appended to every outbound text and caption --message-signature <string> | example: --message-signature="— Sent via ACME Support"`,
+	)
+	rootCmd.PersistentFlags().Int64VarP(
+		&config.WhatsappMaxInboundMediaSize,
+		"max-inbound-media-size", "",
+		config.WhatsappMaxInboundMediaSize,
+		`skip downloading inbound media larger than this many bytes --max-inbound-media-size <number> | example: --max-inbound-media-size=100000000`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappMediaDownloadTimeoutSeconds,
+		"media-download-timeout-seconds", "",
+		config.WhatsappMediaDownloadTimeoutSeconds,
+		`abandon inbound media downloads for webhooks after this many seconds, 0 disables the deadline --media-download-timeout-seconds <number> | example: --media-download-timeout-seconds=30`,
+	)
+	rootCmd.PersistentFlags().Int64VarP(
+		&config.WhatsappWebhookInlineMediaMaxBytes,
+		"webhook-inline-media-max-bytes", "",
+		config.WhatsappWebhookInlineMediaMaxBytes,
+		`inline inbound media up to this many bytes in webhooks as a base64 data URI, 0 disables --webhook-inline-media-max-bytes <number> | example: --webhook-inline-media-max-bytes=65536`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendMinDelayMs,
+		"send-min-delay-ms", "",
+		config.WhatsappSendMinDelayMs,
+		`minimum delay before each send in milliseconds --send-min-delay-ms <number> | example: --send-min-delay-ms=500`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendMaxDelayMs,
+		"send-max-delay-ms", "",
+		config.WhatsappSendMaxDelayMs,
+		`maximum delay before each send in milliseconds, longer messages wait longer --send-max-delay-ms <number> | example: --send-max-delay-ms=3000`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappPreSendTyping,
+		"pre-send-typing", "",
+		config.WhatsappPreSendTyping,
+		`show typing indicator while waiting the send delay --pre-send-typing <true/false> | example: --pre-send-typing=true`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendRateLimit,
+		"send-rate-limit", "",
+		config.WhatsappSendRateLimit,
+		`maximum outbound messages per minute, 0 for unlimited --send-rate-limit <number> | example: --send-rate-limit=30`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappContactExport,
+		"contact-export", "",
+		config.WhatsappContactExport,
+		`enable or disable exporting every visible contact and group participant --contact-export <true/false> | example: --contact-export=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappDocumentThumbnail,
+		"document-thumbnail", "",
+		config.WhatsappDocumentThumbnail,
+		`render the first page of PDFs as document thumbnail, requires pdftoppm --document-thumbnail <true/false> | example: --document-thumbnail=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappDownloadImage,
+		"download-image", "",
+		config.WhatsappDownloadImage,
+		`download inbound images for webhooks, otherwise only their size and mime type are sent --download-image <true/false> | example: --download-image=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappDownloadVideo,
+		"download-video", "",
+		config.WhatsappDownloadVideo,
+		`download inbound videos for webhooks, otherwise only their size and mime type are sent --download-video <true/false> | example: --download-video=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappDownloadAudio,
+		"download-audio", "",
+		config.WhatsappDownloadAudio,
+		`download inbound audios for webhooks, otherwise only their size and mime type are sent --download-audio <true/false> | example: --download-audio=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappDownloadDocument,
+		"download-document", "",
+		config.WhatsappDownloadDocument,
+		`download inbound documents for webhooks, otherwise only their size and mime type are sent --download-document <true/false> | example: --download-document=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappDownloadSticker,
+		"download-sticker", "",
+		config.WhatsappDownloadSticker,
+		`download inbound stickers for webhooks, otherwise only their size and mime type are sent --download-sticker <true/false> | example: --download-sticker=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappTranscodeMedia,
+		"transcode-media", "",
+		config.WhatsappTranscodeMedia,
+		`convert outbound audio to OGG/Opus and video to H.264/AAC MP4, requires ffmpeg --transcode-media <true/false> | example: --transcode-media=true`,
+	)
+	rootCmd.PersistentFlags().Int64VarP(
+		&config.WhatsappTranscodeVideoMaxBytes,
+		"transcode-video-max-bytes", "",
+		config.WhatsappTranscodeVideoMaxBytes,
+		`target size in bytes of transcoded videos, 0 disables the cap --transcode-video-max-bytes <number> | example: --transcode-video-max-bytes=16000000`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappAutoMarkRead,
+		"auto-mark-read", "",
+		config.WhatsappAutoMarkRead,
+		`mark every inbound message as read --auto-mark-read <true/false> | example: --auto-mark-read=true`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappAutoMarkReadExclude,
+		"auto-mark-read-exclude", "",
+		config.WhatsappAutoMarkReadExclude,
+		`chats that are never auto-marked as read --auto-mark-read-exclude <pattern> | example: --auto-mark-read-exclude="*@g.us"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappRecipientAllow,
+		"recipient-allow", "",
+		config.WhatsappRecipientAllow,
+		`only these recipients can be messaged, all are allowed when empty --recipient-allow <pattern> | example: --recipient-allow="62811*@s.whatsapp.net"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappRecipientDeny,
+		"recipient-deny", "",
+		config.WhatsappRecipientDeny,
+		`recipients that are never messaged, takes precedence over the allow list --recipient-deny <pattern> | example: --recipient-deny="*@g.us"`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappWebhookIgnore,
+		"webhook-ignore", "",
+		config.WhatsappWebhookIgnore,
+		`senders or chats whose messages are stored but not forwarded to webhooks --webhook-ignore <pattern> | example: --webhook-ignore="6281234567890@s.whatsapp.net,*@broadcast"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappPresenceOnConnect,
+		"presence-on-connect", "",
+		config.WhatsappPresenceOnConnect,
+		`presence sent after every connect --presence-on-connect <available/unavailable/none> | example: --presence-on-connect=none`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappPresenceDebounceMs,
+		"presence-debounce-ms", "",
+		config.WhatsappPresenceDebounceMs,
+		`repeated typing/recording updates to a chat within this window are coalesced, 0 disables it --presence-debounce-ms <number> | example: --presence-debounce-ms=3000`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappRawMessage,
+		"raw-message", "",
+		config.WhatsappRawMessage,
+		`allow sending raw waE2E.Message JSON through /chat/send/raw, for experts only --raw-message <true/false> | example: --raw-message=true`,
+	)
 }
 
 func initApp() {
 	if config.AppDebug {
 		config.WhatsappLogLevel = "DEBUG"
 	}
+	validations.LocalizeErrors()
 
 	ctx := context.Background()
 	whatsappDB = whatsapp.InitWaDB(ctx)
@@ -200,6 +629,7 @@ func initApp() {
 	messageUsecase = usecase.NewMessageService(whatsappCli)
 	groupUsecase = usecase.NewGroupService(whatsappCli)
 	newsletterUsecase = usecase.NewNewsletterService(whatsappCli)
+	communityUsecase = usecase.NewCommunityService(whatsappCli)
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -6,34 +6,96 @@ import (
 
 var (
 	AppVersion               = "v6.0.0"
+	AppBuildCommit           string // set with -ldflags "-X .../config.AppBuildCommit=<sha>"
+	AppBuildTime             string // set with -ldflags "-X .../config.AppBuildTime=<RFC3339>"
 	AppPort                  = "3000"
 	AppDebug                 = false
 	AppOs                    = "AldinoKemal"
 	AppPlatform              = waCompanionReg.DeviceProps_PlatformType(1)
+	AppLanguage              = "en" // language of response messages, "en" or "pt"
 	AppBasicAuthCredential   []string
+	AppTrustedProxies        []string // IPs or CIDRs allowed to set X-Forwarded-* headers
+	AppProxyHeader           = "X-Forwarded-For"
 	AppChatFlushIntervalDays = 7 // Number of days before flushing chat.csv
+	AppAccessLog             = false
+	AppAccessLogFormat       = "" // fiber logger format, empty uses its default
+	AppAccessLogExclude      = []string{"/health", "/metrics"}
 
 	McpPort = "8080"
 	McpHost = "localhost"
 
-	PathQrCode      = "statics/qrcode"
-	PathSendItems   = "statics/senditems"
-	PathMedia       = "statics/media"
-	PathStorages    = "storages"
-	PathChatStorage = "storages/chat.csv"
+	PathQrCode        = "statics/qrcode"
+	PathSendItems     = "statics/senditems"
+	PathMedia         = "statics/media"
+	PathStorages      = "storages"
+	PathChatStorage   = "storages/chat.csv"
+	PathChatReceipts  = "storages/chat_receipts.csv"
+	PathChatHistory   = "storages/chat_history.csv"
+	PathReactions     = "storages/chat_reactions.csv"
+	PathMediaKeys     = "storages/chat_media.csv"
+	PathPollVotes     = "storages/chat_poll_votes.csv"
+	PathPolls         = "storages/chat_polls.csv"
+	PathDeadLetters   = "storages/webhook_dead_letters.jsonl"
+	PathWebhookSecret = "storages/webhook_secret.json"
 
 	DBURI = "file:storages/whatsapp.db?_foreign_keys=on"
 
-	WhatsappAutoReplyMessage       string
-	WhatsappWebhook                []string
-	WhatsappWebhookSecret                = "secret"
-	WhatsappLogLevel                     = "ERROR"
-	WhatsappSettingMaxImageSize    int64 = 20000000  // 20MB
-	WhatsappSettingMaxFileSize     int64 = 50000000  // 50MB
-	WhatsappSettingMaxVideoSize    int64 = 100000000 // 100MB
-	WhatsappSettingMaxDownloadSize int64 = 500000000 // 500MB
-	WhatsappTypeUser                     = "@s.whatsapp.net"
-	WhatsappTypeGroup                    = "@g.us"
-	WhatsappAccountValidation            = true
-	WhatsappChatStorage                  = true
+	// Retention of files under PathMedia, 0 disables a limit. Inbound media is
+	// what webhooks download, outbound are the temp_ copies of sent media.
+	MediaInboundMaxAgeMinutes  int
+	MediaInboundMaxCount       int
+	MediaOutboundMaxAgeMinutes int
+	MediaOutboundMaxCount      int
+
+	WhatsappAutoReplyMessage            string
+	WhatsappWebhook                     []string
+	WhatsappWebhookOutbound             []string // every sent message is also posted here as a "message_sent" event
+	WhatsappWebhookRoutes               []string // "<jid pattern>=<url>" entries, e.g. "*@g.us=https://example.com/groups"
+	WhatsappWebhookHeaders              []string // "Name: value" sent to every webhook, or "<url>|Name: value" for one
+	WhatsappWebhookTimeoutSeconds                = 10
+	WhatsappWebhookMaxConnsPerHost               = 20
+	WhatsappWebhookMaxPayloadSize                = 0 // bytes, 0 means unlimited
+	WhatsappWebhookSecret                        = "secret"
+	WhatsappWebhookFormat                        = "json"
+	WhatsappWebhookEditedMessages                = true // send edits as "edit_message" events with the old and new text
+	WhatsappWebhookPayloadVersion                = 1    // webhook body layout, 1 is the original shape and 2 the normalized one
+	WhatsappLogLevel                             = "ERROR"
+	WhatsappPresenceOnConnect                    = "available"
+	WhatsappPresenceDebounceMs                   = 3000
+	WhatsappSettingMaxImageSize         int64    = 20000000  // 20MB
+	WhatsappSettingMaxFileSize          int64    = 50000000  // 50MB
+	WhatsappSettingMaxVideoSize         int64    = 100000000 // 100MB
+	WhatsappSettingMaxDownloadSize      int64    = 500000000 // 500MB
+	WhatsappMaxInboundMediaSize         int64    = 100000000 // 100MB, checked against the declared size before download
+	WhatsappMediaDownloadTimeoutSeconds          = 60        // inbound media downloads for webhooks are abandoned after this, 0 disables the deadline
+	WhatsappWebhookInlineMediaMaxBytes  int64                // inbound media up to this size is also inlined in webhooks as a data URI, 0 disables
+	// Inbound media of a disabled type is not downloaded for webhooks, which only
+	// carry its size and mime type.
+	WhatsappDownloadImage          = true
+	WhatsappDownloadVideo          = true
+	WhatsappDownloadAudio          = true
+	WhatsappDownloadDocument       = true
+	WhatsappDownloadSticker        = true
+	WhatsappTypeUser               = "@s.whatsapp.net"
+	WhatsappTypeGroup              = "@g.us"
+	WhatsappAccountValidation      = true
+	WhatsappRecipientLookup        = true // resolve bare numbers with IsOnWhatsApp before sending
+	WhatsappChatStorage            = true
+	WhatsappMessageSignature       string // appended to outbound text and captions
+	WhatsappSendMinDelayMs         int    // humanization delay before each send, 0 disables it
+	WhatsappSendMaxDelayMs         int
+	WhatsappPreSendTyping          bool                // send a composing presence while waiting the delay
+	WhatsappContactExport          = true              // allow GET /contacts/export
+	WhatsappSendRateLimit          int                 // max outbound messages per minute, 0 means unlimited
+	WhatsappWebhookHistorySync     bool                // send a "history_sync" summary webhook after each backfill chunk
+	WhatsappRawMessage             bool                // allow POST /chat/send/raw, an expert feature
+	WhatsappDocumentThumbnail      = true              // render a first-page preview for PDFs when pdftoppm is installed
+	WhatsappTranscodeMedia         bool                // convert outbound audio to OGG/Opus and video to H.264/AAC MP4 when ffmpeg is installed
+	WhatsappTranscodeVideoMaxBytes int64    = 16000000 // target size of transcoded videos, 0 keeps the encoder's quality-based size
+	WhatsappAutoMarkRead           bool                // mark every inbound message as read
+	WhatsappAutoMarkReadExclude    []string            // chat JID patterns never auto-marked, e.g. "*@g.us"
+	WhatsappWebhookIncludeRaw      bool                // add the full message proto and info under "raw", large and may hold sensitive data
+	WhatsappRecipientAllow         []string            // when set, only recipients matching these JID patterns can be messaged
+	WhatsappRecipientDeny          []string            // recipients matching these JID patterns are never messaged, checked first
+	WhatsappWebhookIgnore          []string            // senders or chats matching these JID patterns are not forwarded to webhooks
 )
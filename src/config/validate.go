@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Validate checks the settings read from flags and the environment, so a bad
+// value stops the server at boot instead of failing quietly at runtime, such
+// as a webhook that never delivers. All problems are reported together.
+func Validate() error {
+	var problems []error
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if port, err := strconv.Atoi(AppPort); err != nil || port < 1 || port > 65535 {
+		add("port %q must be a number between 1 and 65535", AppPort)
+	}
+	for _, credential := range AppBasicAuthCredential {
+		user, secret, ok := strings.Cut(credential, ":")
+		if !ok || user == "" || secret == "" || strings.Contains(secret, ":") {
+			add("basic auth %q must have the format <user>:<secret>", credential)
+		}
+	}
+
+	webhookSet := len(WhatsappWebhook) > 0
+	for _, webhook := range WhatsappWebhook {
+		if err := validateWebhookURL(webhook); err != nil {
+			add("webhook %q: %v", webhook, err)
+		}
+	}
+	for _, webhook := range WhatsappWebhookOutbound {
+		webhookSet = true
+		if err := validateWebhookURL(webhook); err != nil {
+			add("outbound webhook %q: %v", webhook, err)
+		}
+	}
+	for _, route := range WhatsappWebhookRoutes {
+		pattern, target, ok := strings.Cut(strings.TrimSpace(route), "=")
+		if !ok || pattern == "" || target == "" {
+			add("webhook route %q must have the format <pattern>=<url>", route)
+			continue
+		}
+		webhookSet = true
+		if _, err := path.Match(pattern, ""); err != nil {
+			add("webhook route %q: invalid pattern: %v", route, err)
+		}
+		if err := validateWebhookURL(target); err != nil {
+			add("webhook route %q: %v", route, err)
+		}
+	}
+	for _, entry := range WhatsappWebhookHeaders {
+		header := strings.TrimSpace(entry)
+		if target, rest, ok := strings.Cut(header, "|"); ok {
+			if err := validateWebhookURL(strings.TrimSpace(target)); err != nil {
+				add("webhook header %q: %v", entry, err)
+			}
+			header = rest
+		}
+		if name, _, ok := strings.Cut(header, ":"); !ok || strings.TrimSpace(name) == "" {
+			add("webhook header %q must have the format [<url>|]<name>: <value>", entry)
+		}
+	}
+	if webhookSet && WhatsappWebhookSecret == "" {
+		add("webhook secret must be set when a webhook is configured")
+	}
+	if WhatsappWebhookFormat != "json" && WhatsappWebhookFormat != "form" {
+		add("webhook format %q must be json or form", WhatsappWebhookFormat)
+	}
+	if WhatsappWebhookPayloadVersion != 1 && WhatsappWebhookPayloadVersion != 2 {
+		add("webhook payload version %d must be 1 or 2", WhatsappWebhookPayloadVersion)
+	}
+	if WhatsappWebhookTimeoutSeconds <= 0 {
+		add("webhook timeout must be positive, got %d", WhatsappWebhookTimeoutSeconds)
+	}
+	if WhatsappWebhookMaxConnsPerHost <= 0 {
+		add("webhook max connections per host must be positive, got %d", WhatsappWebhookMaxConnsPerHost)
+	}
+
+	for _, limit := range []struct {
+		name  string
+		size  int64
+		unset bool // zero disables the limit
+	}{
+		{"max image size", WhatsappSettingMaxImageSize, false},
+		{"max file size", WhatsappSettingMaxFileSize, false},
+		{"max video size", WhatsappSettingMaxVideoSize, false},
+		{"max download size", WhatsappSettingMaxDownloadSize, false},
+		{"max inbound media size", WhatsappMaxInboundMediaSize, true},
+		{"webhook max payload size", int64(WhatsappWebhookMaxPayloadSize), true},
+		{"webhook inline media max bytes", WhatsappWebhookInlineMediaMaxBytes, true},
+		{"transcode video max bytes", WhatsappTranscodeVideoMaxBytes, true},
+	} {
+		if limit.size < 0 || (limit.size == 0 && !limit.unset) {
+			add("%s must be positive, got %d", limit.name, limit.size)
+		}
+	}
+	if WhatsappMediaDownloadTimeoutSeconds < 0 {
+		add("media download timeout must be zero or positive, got %d", WhatsappMediaDownloadTimeoutSeconds)
+	}
+	if WhatsappSendMinDelayMs < 0 || WhatsappSendMaxDelayMs < 0 {
+		add("send delays must be zero or positive, got %d and %d ms", WhatsappSendMinDelayMs, WhatsappSendMaxDelayMs)
+	}
+	if WhatsappSendRateLimit < 0 {
+		add("send rate limit must be zero or positive, got %d", WhatsappSendRateLimit)
+	}
+
+	return errors.Join(problems...)
+}
+
+// validateWebhookURL accepts absolute http and https URLs.
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return errors.New("must be an http or https URL")
+	}
+	if parsed.Host == "" {
+		return errors.New("has no host")
+	}
+	return nil
+}
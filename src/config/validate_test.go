@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	if err := Validate(); err != nil {
+		t.Fatalf("Validate() with the defaults = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name  string
+		apply func()
+		want  string
+	}{
+		{"port out of range", func() { AppPort = "70000" }, "port"},
+		{"port not a number", func() { AppPort = "http" }, "port"},
+		{"malformed basic auth", func() { AppBasicAuthCredential = []string{"admin"} }, "basic auth"},
+		{"webhook without scheme", func() { WhatsappWebhook = []string{"example.com/hook"} }, "http or https"},
+		{"webhook without secret", func() {
+			WhatsappWebhook = []string{"https://example.com/hook"}
+			WhatsappWebhookSecret = ""
+		}, "webhook secret"},
+		{"route without url", func() { WhatsappWebhookRoutes = []string{"message"} }, "webhook route"},
+		{"route with bad pattern", func() { WhatsappWebhookRoutes = []string{"[=https://example.com"} }, "invalid pattern"},
+		{"header without name", func() { WhatsappWebhookHeaders = []string{"no header"} }, "webhook header"},
+		{"unknown payload version", func() { WhatsappWebhookPayloadVersion = 3 }, "payload version"},
+		{"zero timeout", func() { WhatsappWebhookTimeoutSeconds = 0 }, "webhook timeout"},
+		{"zero image size", func() { WhatsappSettingMaxImageSize = 0 }, "max image size"},
+		{"negative inbound size", func() { WhatsappMaxInboundMediaSize = -1 }, "max inbound media size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := saveSettings()
+			defer restore()
+
+			tt.apply()
+			err := Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want an error mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	restore := saveSettings()
+	defer restore()
+
+	AppPort = "0"
+	WhatsappWebhookFormat = "xml"
+	err := Validate()
+	if err == nil || !strings.Contains(err.Error(), "port") || !strings.Contains(err.Error(), "format") {
+		t.Errorf("Validate() = %v, want both the port and the format reported", err)
+	}
+}
+
+// saveSettings snapshots the settings the tests change and returns a function
+// restoring them.
+func saveSettings() func() {
+	port, auth := AppPort, AppBasicAuthCredential
+	webhooks, routes, headers := WhatsappWebhook, WhatsappWebhookRoutes, WhatsappWebhookHeaders
+	secret, format, version := WhatsappWebhookSecret, WhatsappWebhookFormat, WhatsappWebhookPayloadVersion
+	timeout, imageSize, inboundSize := WhatsappWebhookTimeoutSeconds, WhatsappSettingMaxImageSize, WhatsappMaxInboundMediaSize
+	return func() {
+		AppPort, AppBasicAuthCredential = port, auth
+		WhatsappWebhook, WhatsappWebhookRoutes, WhatsappWebhookHeaders = webhooks, routes, headers
+		WhatsappWebhookSecret, WhatsappWebhookFormat, WhatsappWebhookPayloadVersion = secret, format, version
+		WhatsappWebhookTimeoutSeconds, WhatsappSettingMaxImageSize, WhatsappMaxInboundMediaSize = timeout, imageSize, inboundSize
+	}
+}
@@ -12,6 +12,9 @@ type IAppUsecase interface {
 	Reconnect(ctx context.Context) (err error)
 	FirstDevice(ctx context.Context) (response DevicesResponse, err error)
 	FetchDevices(ctx context.Context) (response []DevicesResponse, err error)
+	LinkedDevices(ctx context.Context) (response []LinkedDeviceResponse, err error)
+	LogoutDevice(ctx context.Context, request LogoutDeviceRequest) (response []LinkedDeviceResponse, err error)
+	Info(ctx context.Context) (response InfoResponse, err error)
 }
 
 type DevicesResponse struct {
@@ -19,8 +22,37 @@ type DevicesResponse struct {
 	Device string `json:"device"`
 }
 
+// LinkedDeviceResponse is a device linked to the logged in account, as seen by
+// WhatsApp. Device 0 is the primary phone.
+type LinkedDeviceResponse struct {
+	Device   string `json:"device"`
+	DeviceID uint16 `json:"device_id"`
+	Primary  bool   `json:"primary"`
+	Current  bool   `json:"current"`
+}
+
+// LogoutDeviceRequest names the device to log out, either by its numeric id or
+// by its full device JID.
+type LogoutDeviceRequest struct {
+	DeviceID string `json:"device_id" form:"device_id"`
+}
+
 type LoginResponse struct {
 	ImagePath string        `json:"image_path"`
 	Duration  time.Duration `json:"duration"`
 	Code      string        `json:"code"`
 }
+
+type InfoResponse struct {
+	Version       string          `json:"version"`
+	BuildCommit   string          `json:"build_commit"`
+	BuildTime     string          `json:"build_time"`
+	GoVersion     string          `json:"go_version"`
+	OS            string          `json:"os"`
+	Arch          string          `json:"arch"`
+	StartedAt     time.Time       `json:"started_at"`
+	UptimeSeconds int64           `json:"uptime_seconds"`
+	Webhooks      []string        `json:"webhooks"`
+	WebhookRoutes []string        `json:"webhook_routes"`
+	Features      map[string]bool `json:"features"`
+}
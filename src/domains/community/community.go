@@ -0,0 +1,24 @@
+package community
+
+import "context"
+
+type ICommunityUsecase interface {
+	ListGroups(ctx context.Context, request ListGroupsRequest) (result []LinkedGroup, err error)
+	LinkGroup(ctx context.Context, request LinkGroupRequest) (err error)
+	UnlinkGroup(ctx context.Context, request LinkGroupRequest) (err error)
+}
+
+type ListGroupsRequest struct {
+	CommunityID string `json:"community_id" params:"jid"`
+}
+
+type LinkedGroup struct {
+	JID               string `json:"jid"`
+	Name              string `json:"name"`
+	IsDefaultSubGroup bool   `json:"is_default_sub_group"`
+}
+
+type LinkGroupRequest struct {
+	CommunityID string `json:"community_id" form:"community_id"`
+	GroupID     string `json:"group_id" form:"group_id"`
+}
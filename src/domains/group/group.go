@@ -2,6 +2,7 @@ package group
 
 import (
 	"context"
+	"mime/multipart"
 	"time"
 
 	"go.mau.fi/whatsmeow"
@@ -9,21 +10,48 @@ import (
 
 type IGroupUsecase interface {
 	JoinGroupWithLink(ctx context.Context, request JoinGroupWithLinkRequest) (groupID string, err error)
+	GetGroupInfoFromLink(ctx context.Context, request GetGroupInfoFromLinkRequest) (response GetGroupInfoFromLinkResponse, err error)
 	LeaveGroup(ctx context.Context, request LeaveGroupRequest) (err error)
 	CreateGroup(ctx context.Context, request CreateGroupRequest) (groupID string, err error)
 	ManageParticipant(ctx context.Context, request ParticipantRequest) (result []ParticipantStatus, err error)
 	GetGroupRequestParticipants(ctx context.Context, request GetGroupRequestParticipantsRequest) (result []GetGroupRequestParticipantsResponse, err error)
 	ManageGroupRequestParticipants(ctx context.Context, request GroupRequestParticipantsRequest) (result []ParticipantStatus, err error)
+	Broadcast(ctx context.Context, request BroadcastRequest) (result []BroadcastStatus, err error)
+	RefreshGroup(ctx context.Context, request RefreshGroupRequest) (response RefreshGroupResponse, err error)
 }
 
 type JoinGroupWithLinkRequest struct {
 	Link string `json:"link" form:"link"`
 }
 
+type GetGroupInfoFromLinkRequest struct {
+	Link string `json:"link" query:"link"`
+}
+
+type GetGroupInfoFromLinkResponse struct {
+	GroupID          string    `json:"group_id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	ParticipantCount int       `json:"participant_count"`
+	CreatedAt        time.Time `json:"created_at"`
+	IsAnnounce       bool      `json:"is_announce"`
+	IsLocked         bool      `json:"is_locked"`
+	ApprovalRequired bool      `json:"approval_required"`
+}
+
 type LeaveGroupRequest struct {
 	GroupID string `json:"group_id" form:"group_id"`
 }
 
+type RefreshGroupRequest struct {
+	GroupID string `json:"group_id" params:"jid"`
+}
+
+type RefreshGroupResponse struct {
+	GroupID string `json:"group_id"`
+	Name    string `json:"name"`
+}
+
 type CreateGroupRequest struct {
 	Title        string   `json:"title" form:"title"`
 	Participants []string `json:"participants" form:"participants"`
@@ -55,3 +83,24 @@ type GroupRequestParticipantsRequest struct {
 	Participants []string                           `json:"participants" form:"participants"`
 	Action       whatsmeow.ParticipantRequestChange `json:"action" form:"action"`
 }
+
+const (
+	BroadcastFilterAll   = "all"
+	BroadcastFilterAdmin = "admin"
+	BroadcastFilterList  = "list"
+)
+
+type BroadcastRequest struct {
+	Message  string                `json:"message" form:"message"`
+	Media    *multipart.FileHeader `json:"media" form:"media"`
+	Filter   string                `json:"filter" form:"filter"`
+	GroupIDs []string              `json:"group_ids" form:"group_ids"`
+}
+
+type BroadcastStatus struct {
+	GroupID   string `json:"group_id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"` // sent, failed or skipped
+	MessageID string `json:"message_id,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
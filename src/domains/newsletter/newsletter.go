@@ -1,11 +1,47 @@
 package newsletter
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type INewsletterUsecase interface {
 	Unfollow(ctx context.Context, request UnfollowRequest) (err error)
+	Messages(ctx context.Context, request MessagesRequest) (response MessagesResponse, err error)
+	React(ctx context.Context, request ReactRequest) (err error)
 }
 
 type UnfollowRequest struct {
 	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
 }
+
+type MessagesRequest struct {
+	NewsletterID string `json:"newsletter_id" params:"jid"`
+	Count        int    `json:"count" query:"count"`
+	Before       int    `json:"before" query:"before"`
+}
+
+// MessagesResponse lists channel messages with their engagement. ReactionMode is
+// the channel setting deciding which reactions followers may send.
+type MessagesResponse struct {
+	NewsletterID string         `json:"newsletter_id"`
+	ReactionMode string         `json:"reaction_mode"`
+	Messages     []MessageStats `json:"messages"`
+}
+
+type MessageStats struct {
+	ServerID  int            `json:"server_id"`
+	MessageID string         `json:"message_id"`
+	Type      string         `json:"type"`
+	Timestamp time.Time      `json:"timestamp"`
+	Views     int            `json:"views"`
+	Reactions map[string]int `json:"reactions"`
+}
+
+// ReactRequest reacts to a channel message, identified by its server ID. An empty
+// reaction removes the previous one.
+type ReactRequest struct {
+	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
+	ServerID     int    `json:"server_id" form:"server_id"`
+	Reaction     string `json:"reaction" form:"reaction"`
+}
@@ -6,4 +6,5 @@ type AudioRequest struct {
 	Phone       string                `json:"phone" form:"phone"`
 	Audio       *multipart.FileHeader `json:"audio" form:"audio"`
 	IsForwarded bool                  `json:"is_forwarded" form:"is_forwarded"`
+	MessageID   string                `json:"message_id" form:"message_id"` // sent under this ID instead of a generated one
 }
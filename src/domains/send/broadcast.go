@@ -0,0 +1,17 @@
+package send
+
+// BroadcastMaxRecipients mirrors the size limit of a WhatsApp broadcast list.
+const BroadcastMaxRecipients = 256
+
+type BroadcastRequest struct {
+	Phones        []string `json:"phones" form:"phones"`
+	Message       string   `json:"message" form:"message"`
+	SkipSignature bool     `json:"skip_signature" form:"skip_signature"`
+}
+
+type BroadcastStatus struct {
+	Phone     string `json:"phone"`
+	Status    string `json:"status"` // sent, failed or skipped
+	MessageID string `json:"message_id,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
@@ -5,4 +5,5 @@ type ContactRequest struct {
 	ContactName  string `json:"contact_name" form:"contact_name"`
 	ContactPhone string `json:"contact_phone" form:"contact_phone"`
 	IsForwarded  bool   `json:"is_forwarded" form:"is_forwarded"`
+	MessageID    string `json:"message_id" form:"message_id"` // sent under this ID instead of a generated one
 }
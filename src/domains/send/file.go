@@ -3,8 +3,10 @@ package send
 import "mime/multipart"
 
 type FileRequest struct {
-	Phone       string                `json:"phone" form:"phone"`
-	File        *multipart.FileHeader `json:"file" form:"file"`
-	Caption     string                `json:"caption" form:"caption"`
-	IsForwarded bool                  `json:"is_forwarded" form:"is_forwarded"`
+	Phone         string                `json:"phone" form:"phone"`
+	File          *multipart.FileHeader `json:"file" form:"file"`
+	Caption       string                `json:"caption" form:"caption"`
+	IsForwarded   bool                  `json:"is_forwarded" form:"is_forwarded"`
+	SkipSignature bool                  `json:"skip_signature" form:"skip_signature"`
+	MessageID     string                `json:"message_id" form:"message_id"` // sent under this ID instead of a generated one
 }
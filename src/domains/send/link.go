@@ -5,4 +5,5 @@ type LinkRequest struct {
 	Caption     string `json:"caption"`
 	Link        string `json:"link"`
 	IsForwarded bool   `json:"is_forwarded" form:"is_forwarded"`
+	MessageID   string `json:"message_id" form:"message_id"` // sent under this ID instead of a generated one
 }
@@ -5,4 +5,5 @@ type LocationRequest struct {
 	Latitude    string `json:"latitude" form:"latitude"`
 	Longitude   string `json:"longitude" form:"longitude"`
 	IsForwarded bool   `json:"is_forwarded" form:"is_forwarded"`
+	MessageID   string `json:"message_id" form:"message_id"` // sent under this ID instead of a generated one
 }
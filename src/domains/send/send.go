@@ -15,9 +15,11 @@ type ISendUsecase interface {
 	SendAudio(ctx context.Context, request AudioRequest) (response GenericResponse, err error)
 	SendPoll(ctx context.Context, request PollRequest) (response GenericResponse, err error)
 	SendPresence(ctx context.Context, request PresenceRequest) (response GenericResponse, err error)
+	SendBroadcast(ctx context.Context, request BroadcastRequest) (result []BroadcastStatus, err error)
 }
 
 type GenericResponse struct {
 	MessageID string `json:"message_id"`
 	Status    string `json:"status"`
+	Mentioned int    `json:"mentioned,omitempty"` // members mentioned by mention_all
 }
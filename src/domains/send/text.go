@@ -5,4 +5,8 @@ type MessageRequest struct {
 	Message        string  `json:"message" form:"message"`
 	IsForwarded    bool    `json:"is_forwarded" form:"is_forwarded"`
 	ReplyMessageID *string `json:"reply_message_id" form:"reply_message_id"`
+	ReplyChat      string  `json:"reply_chat" form:"reply_chat"` // chat of the quoted message, when not the target
+	SkipSignature  bool    `json:"skip_signature" form:"skip_signature"`
+	MentionAll     bool    `json:"mention_all" form:"mention_all"` // mention every group member, the account must be an admin
+	MessageID      string  `json:"message_id" form:"message_id"`   // sent under this ID instead of a generated one
 }
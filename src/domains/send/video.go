@@ -3,10 +3,12 @@ package send
 import "mime/multipart"
 
 type VideoRequest struct {
-	Phone       string                `json:"phone" form:"phone"`
-	Caption     string                `json:"caption" form:"caption"`
-	Video       *multipart.FileHeader `json:"video" form:"video"`
-	ViewOnce    bool                  `json:"view_once" form:"view_once"`
-	Compress    bool                  `json:"compress"`
-	IsForwarded bool                  `json:"is_forwarded" form:"is_forwarded"`
+	Phone         string                `json:"phone" form:"phone"`
+	Caption       string                `json:"caption" form:"caption"`
+	Video         *multipart.FileHeader `json:"video" form:"video"`
+	ViewOnce      bool                  `json:"view_once" form:"view_once"`
+	Compress      bool                  `json:"compress"`
+	IsForwarded   bool                  `json:"is_forwarded" form:"is_forwarded"`
+	SkipSignature bool                  `json:"skip_signature" form:"skip_signature"`
+	MessageID     string                `json:"message_id" form:"message_id"` // sent under this ID instead of a generated one
 }
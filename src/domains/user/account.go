@@ -2,6 +2,7 @@ package user
 
 import (
 	"mime/multipart"
+	"time"
 
 	"go.mau.fi/whatsmeow/types"
 )
@@ -73,3 +74,109 @@ type MyListContactsResponseData struct {
 type ChangePushNameRequest struct {
 	PushName string `json:"push_name" form:"push_name"`
 }
+
+type ResolveRequest struct {
+	JID string `json:"jid" params:"jid"`
+}
+
+type ResolveResponse struct {
+	JID          string `json:"jid"`
+	PhoneJID     string `json:"phone_jid"`
+	LID          string `json:"lid"`
+	VerifiedName string `json:"verified_name"`
+}
+
+type BlockRequest struct {
+	Phone string `json:"phone" form:"phone"`
+}
+
+type BlockResponse struct {
+	Phone     string   `json:"phone"`
+	Blocked   bool     `json:"blocked"`
+	Changed   bool     `json:"changed"`
+	Blocklist []string `json:"blocklist"`
+}
+
+type BlocklistResponse struct {
+	Data []string `json:"data"`
+}
+
+type BusinessProfileRequest struct {
+	JID string `json:"jid" params:"jid"`
+}
+
+type BusinessCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type BusinessHours struct {
+	DayOfWeek string `json:"day_of_week"`
+	Mode      string `json:"mode"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+}
+
+type BusinessProfileResponse struct {
+	JID                   string             `json:"jid"`
+	Email                 string             `json:"email"`
+	Address               string             `json:"address"`
+	Categories            []BusinessCategory `json:"categories"`
+	ProfileOptions        map[string]string  `json:"profile_options"`
+	BusinessHoursTimeZone string             `json:"business_hours_timezone"`
+	BusinessHours         []BusinessHours    `json:"business_hours"`
+}
+
+type CatalogRequest struct {
+	JID   string `json:"jid" params:"jid"`
+	After string `json:"after" query:"after"` // next_cursor of the previous page
+}
+
+type CatalogProduct struct {
+	ID              string `json:"id"`
+	RetailerID      string `json:"retailer_id,omitempty"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	PriceAmount1000 int64  `json:"price_amount_1000"` // price multiplied by 1000
+	Currency        string `json:"currency"`
+	URL             string `json:"url,omitempty"`
+	ImageURL        string `json:"image_url,omitempty"`
+	Hidden          bool   `json:"hidden"`
+}
+
+type CatalogResponse struct {
+	JID        string           `json:"jid"`
+	Products   []CatalogProduct `json:"products"`
+	NextCursor string           `json:"next_cursor,omitempty"` // pass as after to get the next page
+}
+
+type PresenceRequest struct {
+	JID string `json:"jid" params:"jid"`
+}
+
+type PresenceResponse struct {
+	JID       string     `json:"jid"`
+	Status    string     `json:"status"`              // online or offline
+	LastSeen  *time.Time `json:"last_seen,omitempty"` // only when the contact shares it
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type ExportContactsRequest struct {
+	Page    int `json:"page" query:"page"`
+	PerPage int `json:"per_page" query:"per_page"`
+}
+
+type ExportContact struct {
+	JID        string   `json:"jid"`
+	Phone      string   `json:"phone"`
+	Name       string   `json:"name"`
+	InContacts bool     `json:"in_contacts"`
+	Groups     []string `json:"groups"`
+}
+
+type ExportContactsResponse struct {
+	Data    []ExportContact `json:"data"`
+	Page    int             `json:"page"`
+	PerPage int             `json:"per_page"`
+	Total   int             `json:"total"`
+}
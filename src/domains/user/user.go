@@ -13,4 +13,12 @@ type IUserUsecase interface {
 	MyListNewsletter(ctx context.Context) (response MyListNewsletterResponse, err error)
 	MyPrivacySetting(ctx context.Context) (response MyPrivacySettingResponse, err error)
 	MyListContacts(ctx context.Context) (response MyListContactsResponse, err error)
+	Resolve(ctx context.Context, request ResolveRequest) (response ResolveResponse, err error)
+	Block(ctx context.Context, request BlockRequest) (response BlockResponse, err error)
+	Unblock(ctx context.Context, request BlockRequest) (response BlockResponse, err error)
+	Blocklist(ctx context.Context) (response BlocklistResponse, err error)
+	BusinessProfile(ctx context.Context, request BusinessProfileRequest) (response BusinessProfileResponse, err error)
+	Catalog(ctx context.Context, request CatalogRequest) (response CatalogResponse, err error)
+	Presence(ctx context.Context, request PresenceRequest) (response PresenceResponse, err error)
+	ExportContacts(ctx context.Context, request ExportContactsRequest) (response ExportContactsResponse, err error)
 }
@@ -0,0 +1,71 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+// ackWaiters holds the callers blocked in WaitForAck, by message ID.
+var (
+	ackWaiters = map[string][]chan string{}
+	ackMutex   sync.Mutex
+)
+
+// notifyAck wakes every caller waiting for an ack of messageID.
+func notifyAck(messageID, status string) {
+	ackMutex.Lock()
+	waiters := ackWaiters[messageID]
+	delete(ackWaiters, messageID)
+	ackMutex.Unlock()
+
+	for _, waiter := range waiters {
+		waiter <- status
+	}
+}
+
+// WaitForAck blocks until the recipient acknowledges messageID (delivered, read
+// or played) or the timeout elapses. It returns the ack state and whether one
+// arrived in time; on timeout the state is "sent".
+func WaitForAck(ctx context.Context, messageID string, timeout time.Duration) (string, bool) {
+	waiter := make(chan string, 1)
+	ackMutex.Lock()
+	ackWaiters[messageID] = append(ackWaiters[messageID], waiter)
+	ackMutex.Unlock()
+
+	// The receipt may have arrived before the waiter was registered.
+	if receipt, err := utils.FindMessageReceipt(messageID); err == nil && receipt.Status != utils.ReceiptStatusSent {
+		removeAckWaiter(messageID, waiter)
+		return receipt.Status, true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case status := <-waiter:
+		return status, true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	removeAckWaiter(messageID, waiter)
+	return utils.ReceiptStatusSent, false
+}
+
+func removeAckWaiter(messageID string, waiter chan string) {
+	ackMutex.Lock()
+	defer ackMutex.Unlock()
+	waiters := ackWaiters[messageID]
+	for i, candidate := range waiters {
+		if candidate == waiter {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(ackWaiters, messageID)
+	} else {
+		ackWaiters[messageID] = waiters
+	}
+}
@@ -0,0 +1,28 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+func TestWaitForAck(t *testing.T) {
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		notifyAck("ACKED", utils.ReceiptStatusDelivered)
+	}()
+	status, acked := WaitForAck(context.Background(), "ACKED", time.Second)
+	if !acked || status != utils.ReceiptStatusDelivered {
+		t.Errorf("WaitForAck() = %q, %v, want delivered, true", status, acked)
+	}
+
+	status, acked = WaitForAck(context.Background(), "SILENT", 20*time.Millisecond)
+	if acked || status != utils.ReceiptStatusSent {
+		t.Errorf("WaitForAck() = %q, %v, want sent, false", status, acked)
+	}
+	if _, waiting := ackWaiters["SILENT"]; waiting {
+		t.Error("timed out waiter was not removed")
+	}
+}
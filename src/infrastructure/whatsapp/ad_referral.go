@@ -0,0 +1,110 @@
+package whatsapp
+
+import (
+	"sync"
+
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// adReferralCache keeps the last click-to-WhatsApp ad context received per chat,
+// so replies can echo it back and keep the conversation attributed to the ad.
+var adReferralCache sync.Map
+
+// messageContextInfo returns the context info of the content type carried by msg.
+func messageContextInfo(msg *waProto.Message) *waProto.ContextInfo {
+	switch {
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetContextInfo()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetContextInfo()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetContextInfo()
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage().GetContextInfo()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetContextInfo()
+	case msg.GetStickerMessage() != nil:
+		return msg.GetStickerMessage().GetContextInfo()
+	case msg.GetLocationMessage() != nil:
+		return msg.GetLocationMessage().GetContextInfo()
+	case msg.GetContactMessage() != nil:
+		return msg.GetContactMessage().GetContextInfo()
+	}
+	return nil
+}
+
+// adReferralContext extracts the ad attribution fields of an inbound message,
+// returning nil when the message did not come from an ad.
+func adReferralContext(evt *events.Message) *waProto.ContextInfo {
+	contextInfo := messageContextInfo(evt.Message)
+	if contextInfo.GetExternalAdReply() == nil && contextInfo.GetConversionSource() == "" &&
+		contextInfo.GetEntryPointConversionSource() == "" {
+		return nil
+	}
+	return &waProto.ContextInfo{
+		ExternalAdReply:            contextInfo.GetExternalAdReply(),
+		ConversionSource:           contextInfo.ConversionSource,
+		ConversionData:             contextInfo.GetConversionData(),
+		ConversionDelaySeconds:     contextInfo.ConversionDelaySeconds,
+		EntryPointConversionSource: contextInfo.EntryPointConversionSource,
+		EntryPointConversionApp:    contextInfo.EntryPointConversionApp,
+	}
+}
+
+// rememberAdReferral stores the ad context of an inbound message for its chat.
+func rememberAdReferral(evt *events.Message) {
+	if evt.Info.IsFromMe {
+		return
+	}
+	if referral := adReferralContext(evt); referral != nil {
+		adReferralCache.Store(evt.Info.Chat.ToNonAD().String(), referral)
+	}
+}
+
+// ApplyAdReferral copies the last ad context received from chat into contextInfo.
+// It reports false when the chat has no known ad referral.
+func ApplyAdReferral(chat types.JID, contextInfo *waProto.ContextInfo) bool {
+	value, ok := adReferralCache.Load(chat.ToNonAD().String())
+	if !ok {
+		return false
+	}
+	referral := value.(*waProto.ContextInfo)
+	if adReply := referral.GetExternalAdReply(); adReply != nil {
+		contextInfo.ExternalAdReply = proto.Clone(adReply).(*waProto.ContextInfo_ExternalAdReplyInfo)
+	}
+	contextInfo.ConversionSource = referral.ConversionSource
+	contextInfo.ConversionData = referral.ConversionData
+	contextInfo.ConversionDelaySeconds = referral.ConversionDelaySeconds
+	contextInfo.EntryPointConversionSource = referral.EntryPointConversionSource
+	contextInfo.EntryPointConversionApp = referral.EntryPointConversionApp
+	return true
+}
+
+// buildAdReferral renders the ad context of a message for the webhook payload.
+func buildAdReferral(evt *events.Message) map[string]interface{} {
+	referral := adReferralContext(evt)
+	if referral == nil {
+		return nil
+	}
+	adReply := referral.GetExternalAdReply()
+	mediaURL := adReply.GetMediaURL()
+	if mediaURL == "" {
+		mediaURL = adReply.GetThumbnailURL()
+	}
+	return map[string]interface{}{
+		"headline":          adReply.GetTitle(),
+		"body":              adReply.GetBody(),
+		"media_type":        adReply.GetMediaType().String(),
+		"media_url":         mediaURL,
+		"thumbnail_url":     adReply.GetThumbnailURL(),
+		"source_id":         adReply.GetSourceID(),
+		"source_type":       adReply.GetSourceType(),
+		"source_url":        adReply.GetSourceURL(),
+		"ctwa_clid":         adReply.GetCtwaClid(),
+		"conversion_source": referral.GetConversionSource(),
+		"entry_point":       referral.GetEntryPointConversionSource(),
+	}
+}
@@ -0,0 +1,42 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// MaxAutoDeleteDelay keeps auto-deletes inside the window in which WhatsApp
+// still accepts revoking a message for everyone.
+const MaxAutoDeleteDelay = 48 * time.Hour
+
+// ScheduleAutoDelete revokes a sent message for everyone after delay. The timer
+// is listed by GET /tasks under the returned key and can be cancelled there
+// until it fires. A failed revoke is only logged: the recipient may already
+// have seen the message, and nobody waits for the outcome.
+func ScheduleAutoDelete(chat types.JID, id types.MessageID, delay time.Duration) string {
+	key := AutoDeleteKey(id)
+	utils.DefaultScheduler.Schedule(key, delay, func() {
+		cli, err := ConnectedClient()
+		if err != nil {
+			logrus.Warnf("Cannot auto-delete message %s in %s: %v", id, chat.String(), err)
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := SendMessage(ctx, chat, cli.BuildRevoke(chat, types.EmptyJID, id)); err != nil {
+			logrus.Warnf("Failed to auto-delete message %s in %s: %v", id, chat.String(), err)
+			return
+		}
+		logrus.Infof("Auto-deleted message %s in %s", id, chat.String())
+	})
+	return key
+}
+
+// AutoDeleteKey is the scheduler key of the auto-delete timer of a message.
+func AutoDeleteKey(id types.MessageID) string {
+	return "auto-delete:" + id
+}
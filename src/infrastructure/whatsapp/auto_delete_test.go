@@ -0,0 +1,30 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestScheduleAutoDelete(t *testing.T) {
+	chat := types.NewJID("6281111111111", types.DefaultUserServer)
+	key := ScheduleAutoDelete(chat, "3EB0AUTODELETE", time.Hour)
+	if key != AutoDeleteKey("3EB0AUTODELETE") {
+		t.Errorf("key = %q, want %q", key, AutoDeleteKey("3EB0AUTODELETE"))
+	}
+
+	found := false
+	for _, task := range utils.DefaultScheduler.Tasks() {
+		if task.ID == key {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("auto-delete timer %q not listed in the scheduler tasks", key)
+	}
+	if !utils.DefaultScheduler.Cancel(key) {
+		t.Errorf("auto-delete timer %q could not be cancelled", key)
+	}
+}
@@ -0,0 +1,224 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// catalogPageSize is how many products each catalog query returns.
+	catalogPageSize = 100
+	// catalogMaxPages bounds the catalog walk when looking up a product.
+	catalogMaxPages = 20
+)
+
+// ErrProductNotFound is returned when the product is not in the account's catalog.
+var ErrProductNotFound = errors.New("product not found in catalog")
+
+// CatalogProduct is a product of a WhatsApp Business catalog.
+type CatalogProduct struct {
+	ID          string
+	RetailerID  string
+	Name        string
+	Description string
+	// PriceAmount1000 is the price multiplied by 1000, as WhatsApp stores it.
+	PriceAmount1000 int64
+	Currency        string
+	URL             string
+	ImageURL        string
+	Hidden          bool
+}
+
+// catalogPage queries one page of the product catalog of jid. WhatsApp has no
+// catalog API in whatsmeow, so the w:biz:catalog query is sent directly.
+func catalogPage(ctx context.Context, cli *whatsmeow.Client, jid types.JID, after string) ([]CatalogProduct, string, error) {
+	content := []waBinary.Node{
+		{Tag: "limit", Content: []byte(strconv.Itoa(catalogPageSize))},
+		{Tag: "width", Content: []byte("100")},
+		{Tag: "height", Content: []byte("100")},
+	}
+	if after != "" {
+		content = append(content, waBinary.Node{Tag: "after", Content: []byte(after)})
+	}
+	resp, err := cli.DangerousInternals().SendIQ(whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz:catalog",
+		Type:      whatsmeow.DangerousInfoQueryType("get"),
+		To:        types.ServerJID,
+		Context:   ctx,
+		Content: []waBinary.Node{{
+			Tag:     "product_catalog",
+			Attrs:   waBinary.Attrs{"jid": jid, "allow_shop_source": "true"},
+			Content: content,
+		}},
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query catalog: %w", err)
+	}
+	return parseCatalogPage(resp)
+}
+
+// parseCatalogPage reads the products and the next page cursor of a catalog response.
+func parseCatalogPage(resp *waBinary.Node) ([]CatalogProduct, string, error) {
+	catalog, ok := resp.GetOptionalChildByTag("product_catalog")
+	if !ok {
+		return nil, "", fmt.Errorf("catalog response has no product_catalog element")
+	}
+	var products []CatalogProduct
+	for _, node := range catalog.GetChildrenByTag("product") {
+		product := CatalogProduct{
+			ID:          nodeText(node, "id"),
+			RetailerID:  nodeText(node, "retailer_id"),
+			Name:        nodeText(node, "name"),
+			Description: nodeText(node, "description"),
+			Currency:    nodeText(node, "currency"),
+			URL:         nodeText(node, "url"),
+			ImageURL:    nodeText(node, "media", "image", "request_image_url"),
+			Hidden:      node.AttrGetter().OptionalString("is_hidden") == "true",
+		}
+		if price := nodeText(node, "price"); price != "" {
+			product.PriceAmount1000, _ = strconv.ParseInt(price, 10, 64)
+		}
+		products = append(products, product)
+	}
+	return products, nodeText(catalog, "paging", "after"), nil
+}
+
+// nodeText returns the text content of the child at the given tag path.
+func nodeText(node waBinary.Node, tags ...string) string {
+	child, ok := node.GetOptionalChildByTag(tags...)
+	if !ok {
+		return ""
+	}
+	switch content := child.Content.(type) {
+	case []byte:
+		return string(content)
+	case string:
+		return content
+	}
+	return ""
+}
+
+// CatalogPage returns one page of the product catalog of a business account,
+// and the cursor of the next page, empty on the last one.
+func CatalogPage(ctx context.Context, cli *whatsmeow.Client, jid types.JID, after string) ([]CatalogProduct, string, error) {
+	return catalogPage(ctx, cli, jid.ToNonAD(), after)
+}
+
+// FindCatalogProduct looks a product up by ID or retailer ID in this account's catalog.
+func FindCatalogProduct(ctx context.Context, productID string) (CatalogProduct, error) {
+	cli, err := ConnectedClient()
+	if err != nil {
+		return CatalogProduct{}, err
+	}
+	own := cli.Store.ID.ToNonAD()
+
+	after := ""
+	for page := 0; page < catalogMaxPages; page++ {
+		products, next, err := catalogPage(ctx, cli, own, after)
+		if err != nil {
+			return CatalogProduct{}, err
+		}
+		for _, product := range products {
+			if product.ID == productID || (product.RetailerID != "" && product.RetailerID == productID) {
+				return product, nil
+			}
+		}
+		if next == "" || len(products) == 0 {
+			break
+		}
+		after = next
+	}
+	return CatalogProduct{}, ErrProductNotFound
+}
+
+// SendProductMessage sends a catalog product as a product card with body as
+// the message text. The product image is attached when it can be fetched.
+func SendProductMessage(ctx context.Context, jid types.JID, product CatalogProduct, body, footer string) (whatsmeow.SendResponse, error) {
+	if err := CheckRecipient(jid); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	cli, err := ConnectedClient()
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	snapshot := &waProto.ProductMessage_ProductSnapshot{
+		ProductID:       proto.String(product.ID),
+		Title:           proto.String(product.Name),
+		Description:     proto.String(product.Description),
+		CurrencyCode:    proto.String(product.Currency),
+		PriceAmount1000: proto.Int64(product.PriceAmount1000),
+		RetailerID:      proto.String(product.RetailerID),
+		URL:             proto.String(product.URL),
+	}
+	if image, err := productImage(ctx, cli, product.ImageURL); err != nil {
+		logrus.Warnf("Sending product %s without image: %v", product.ID, err)
+	} else if image != nil {
+		snapshot.ProductImage = image
+		snapshot.ProductImageCount = proto.Uint32(1)
+	}
+
+	msg := &waProto.Message{ProductMessage: &waProto.ProductMessage{
+		Product:          snapshot,
+		BusinessOwnerJID: proto.String(cli.Store.ID.ToNonAD().String()),
+		Body:             proto.String(body),
+		Footer:           proto.String(footer),
+	}}
+	return SendMessage(ctx, jid, msg)
+}
+
+// productImage downloads the catalog image of a product and uploads it for the
+// product card. It returns nil without an error when the product has no image.
+func productImage(ctx context.Context, cli *whatsmeow.Client, imageURL string) (*waProto.ImageMessage, error) {
+	if imageURL == "" {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image download returned %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, config.WhatsappSettingMaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > config.WhatsappSettingMaxImageSize {
+		return nil, fmt.Errorf("image exceeds %d bytes", config.WhatsappSettingMaxImageSize)
+	}
+
+	upload, err := UploadMedia(ctx, cli, data, whatsmeow.MediaImage)
+	if err != nil {
+		return nil, err
+	}
+	return &waProto.ImageMessage{
+		Mimetype:      proto.String(http.DetectContentType(data)),
+		URL:           proto.String(upload.URL),
+		DirectPath:    proto.String(upload.DirectPath),
+		MediaKey:      upload.MediaKey,
+		FileEncSHA256: upload.FileEncSHA256,
+		FileSHA256:    upload.FileSHA256,
+		FileLength:    proto.Uint64(uint64(len(data))),
+	}, nil
+}
@@ -0,0 +1,58 @@
+package whatsapp
+
+import (
+	"testing"
+
+	waBinary "go.mau.fi/whatsmeow/binary"
+)
+
+func textNode(tag, text string) waBinary.Node {
+	return waBinary.Node{Tag: tag, Content: []byte(text)}
+}
+
+func TestParseCatalogPage(t *testing.T) {
+	resp := &waBinary.Node{Tag: "iq", Content: []waBinary.Node{{
+		Tag: "product_catalog",
+		Content: []waBinary.Node{
+			{Tag: "product", Content: []waBinary.Node{
+				textNode("id", "8372625432"),
+				textNode("retailer_id", "SKU-1"),
+				textNode("name", "Coffee"),
+				textNode("price", "25000"),
+				textNode("currency", "IDR"),
+				{Tag: "media", Content: []waBinary.Node{{Tag: "image", Content: []waBinary.Node{
+					textNode("request_image_url", "https://example.com/coffee.jpg"),
+				}}}},
+			}},
+			{Tag: "product", Attrs: waBinary.Attrs{"is_hidden": "true"}, Content: []waBinary.Node{
+				textNode("id", "1"),
+			}},
+			{Tag: "paging", Content: []waBinary.Node{textNode("after", "CURSOR")}},
+		},
+	}}}
+
+	products, after, err := parseCatalogPage(resp)
+	if err != nil {
+		t.Fatalf("parseCatalogPage() error = %v", err)
+	}
+	if after != "CURSOR" {
+		t.Errorf("after = %q, want CURSOR", after)
+	}
+	if len(products) != 2 {
+		t.Fatalf("got %d products, want 2", len(products))
+	}
+	want := CatalogProduct{
+		ID: "8372625432", RetailerID: "SKU-1", Name: "Coffee", PriceAmount1000: 25000,
+		Currency: "IDR", ImageURL: "https://example.com/coffee.jpg",
+	}
+	if products[0] != want {
+		t.Errorf("products[0] = %+v, want %+v", products[0], want)
+	}
+	if !products[1].Hidden {
+		t.Error("hidden product not flagged")
+	}
+
+	if _, _, err := parseCatalogPage(&waBinary.Node{Tag: "iq"}); err == nil {
+		t.Error("response without product_catalog did not fail")
+	}
+}
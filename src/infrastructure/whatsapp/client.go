@@ -0,0 +1,58 @@
+package whatsapp
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"go.mau.fi/whatsmeow"
+)
+
+var (
+	// ErrClientNotInitialized is returned before InitWaCLI has created the client.
+	ErrClientNotInitialized = errors.New("WhatsApp client not initialized")
+	// ErrReconnecting is returned while the client is disconnected, for example
+	// during a manual or automatic reconnect. The request can be retried shortly.
+	ErrReconnecting = errors.New("WhatsApp client is reconnecting, retry shortly")
+	// ErrNotLoggedIn is returned while the client is connected without a session.
+	ErrNotLoggedIn = errors.New("WhatsApp client not logged in")
+)
+
+var (
+	// client is only ever replaced as a whole, so handlers reading it through
+	// GetWaCli never observe a half-initialized client.
+	client       atomic.Pointer[whatsmeow.Client]
+	reconnecting atomic.Bool
+)
+
+// GetWaCli returns the WhatsApp client, or nil before it is initialized.
+func GetWaCli() *whatsmeow.Client {
+	return client.Load()
+}
+
+// ConnectedClient returns the client when it is connected and logged in, or the
+// error describing why messages cannot be sent right now.
+func ConnectedClient() (*whatsmeow.Client, error) {
+	cli := client.Load()
+	switch {
+	case cli == nil:
+		return nil, ErrClientNotInitialized
+	case reconnecting.Load() || !cli.IsConnected():
+		return nil, ErrReconnecting
+	case !cli.IsLoggedIn():
+		return nil, ErrNotLoggedIn
+	}
+	return cli, nil
+}
+
+// Reconnect drops and reopens the connection. Sends attempted meanwhile fail
+// with ErrReconnecting instead of reaching a closed socket.
+func Reconnect() error {
+	cli := client.Load()
+	if cli == nil {
+		return ErrClientNotInitialized
+	}
+	reconnecting.Store(true)
+	defer reconnecting.Store(false)
+	cli.Disconnect()
+	return cli.Connect()
+}
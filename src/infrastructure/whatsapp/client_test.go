@@ -0,0 +1,18 @@
+package whatsapp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConnectedClientBeforeInit(t *testing.T) {
+	if GetWaCli() != nil {
+		t.Skip("client already initialized")
+	}
+	if _, err := ConnectedClient(); !errors.Is(err, ErrClientNotInitialized) {
+		t.Errorf("ConnectedClient() error = %v, want ErrClientNotInitialized", err)
+	}
+	if err := Reconnect(); !errors.Is(err, ErrClientNotInitialized) {
+		t.Errorf("Reconnect() error = %v, want ErrClientNotInitialized", err)
+	}
+}
@@ -0,0 +1,54 @@
+package whatsapp
+
+import (
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// handleCommunityChange forwards groups being linked to or unlinked from a
+// community. Other group info changes are not forwarded.
+func handleCommunityChange(evt *events.GroupInfo) {
+	change, action := evt.Link, "link"
+	if change == nil {
+		change, action = evt.Unlink, "unlink"
+	}
+	if change == nil {
+		return
+	}
+	urls := utils.WebhookURLsForChat(evt.JID.String())
+	if len(urls) == 0 {
+		return
+	}
+
+	payload := map[string]interface{}{
+		"Type":       "community_" + action,
+		"chat":       evt.JID.String(),
+		"link_type":  string(change.Type),
+		"group":      change.Group.JID.String(),
+		"group_name": change.Group.Name,
+		"timestamp":  evt.Timestamp.Format(time.RFC3339),
+	}
+	if change.UnlinkReason != "" {
+		payload["unlink_reason"] = string(change.UnlinkReason)
+	}
+	if evt.Sender != nil {
+		payload["sender"] = evt.Sender.String()
+	}
+	if change.Type == types.GroupLinkChangeTypeSub {
+		// evt.JID is the community when one of its groups changes.
+		payload["community"] = evt.JID.String()
+	} else if change.Type == types.GroupLinkChangeTypeParent {
+		payload["community"] = change.Group.JID.String()
+	}
+	go func() {
+		for _, url := range urls {
+			if err := SubmitWebhook(payload, url); err != nil {
+				logrus.Errorf("Failed to send community webhook: %v", err)
+			}
+		}
+	}()
+}
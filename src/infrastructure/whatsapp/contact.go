@@ -0,0 +1,47 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// vCardEscaper escapes the characters vCard 3.0 gives a meaning in text values.
+var vCardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// ContactVCard builds the minimal vCard of a phone number. The waid parameter
+// lets the recipient tap the card to open a chat. Without a name the card is
+// named after the number.
+func ContactVCard(phone, name string) (vCard, displayName string, err error) {
+	digits, err := utils.NormalizePhone(phone)
+	if err != nil {
+		return "", "", err
+	}
+	displayName = strings.TrimSpace(name)
+	if displayName == "" {
+		displayName = "+" + digits
+	}
+	escaped := vCardEscaper.Replace(displayName)
+	vCard = fmt.Sprintf("BEGIN:VCARD\nVERSION:3.0\nN:;%s;;;\nFN:%s\nTEL;type=CELL;waid=%s:+%s\nEND:VCARD",
+		escaped, escaped, digits, digits)
+	return vCard, displayName, nil
+}
+
+// SendContactByPhone sends a contact card built from a phone number and an
+// optional name.
+func SendContactByPhone(ctx context.Context, jid types.JID, phone, name string) (whatsmeow.SendResponse, error) {
+	vCard, displayName, err := ContactVCard(phone, name)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	return SendMessage(ctx, jid, &waProto.Message{ContactMessage: &waProto.ContactMessage{
+		DisplayName: proto.String(displayName),
+		Vcard:       proto.String(vCard),
+	}})
+}
@@ -0,0 +1,30 @@
+package whatsapp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContactVCard(t *testing.T) {
+	vCard, name, err := ContactVCard("+62 (812) 3456-7890", "Budi; Sales")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Budi; Sales" {
+		t.Errorf("display name = %q", name)
+	}
+	for _, line := range []string{"BEGIN:VCARD", "VERSION:3.0", `FN:Budi\; Sales`, "TEL;type=CELL;waid=6281234567890:+6281234567890", "END:VCARD"} {
+		if !strings.Contains(vCard, line+"\n") && !strings.HasSuffix(vCard, line) {
+			t.Errorf("vCard %q lacks line %q", vCard, line)
+		}
+	}
+
+	if _, name, _ := ContactVCard("6281234567890", " "); name != "+6281234567890" {
+		t.Errorf("display name without a name = %q, want the number", name)
+	}
+	for _, phone := range []string{"", "08123456789", "62-call-me"} {
+		if _, _, err := ContactVCard(phone, "x"); err == nil {
+			t.Errorf("ContactVCard(%q) accepted an invalid phone", phone)
+		}
+	}
+}
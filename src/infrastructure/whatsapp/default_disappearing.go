@@ -0,0 +1,63 @@
+package whatsapp
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow"
+)
+
+// DefaultDisappearing is the disappearing timer applied to new chats of the
+// account.
+type DefaultDisappearing struct {
+	Seconds uint32     `json:"seconds"` // 0 when new chats do not disappear
+	Known   bool       `json:"known"`
+	SetAt   *time.Time `json:"set_at,omitempty"`
+}
+
+var (
+	defaultDisappearing   DefaultDisappearing
+	defaultDisappearingMu sync.RWMutex
+)
+
+// GetDefaultDisappearing returns the default disappearing timer. WhatsApp only
+// lets linked devices set it, not read it back, so it is the value last set
+// through SetDefaultDisappearing since the process started; Known is false
+// until then.
+func GetDefaultDisappearing() DefaultDisappearing {
+	defaultDisappearingMu.RLock()
+	defer defaultDisappearingMu.RUnlock()
+	return defaultDisappearing
+}
+
+// ValidateDefaultDisappearing checks that seconds is 0, turning the timer off,
+// or one of the durations WhatsApp offers.
+func ValidateDefaultDisappearing(seconds uint32) error {
+	switch time.Duration(seconds) * time.Second {
+	case whatsmeow.DisappearingTimerOff, whatsmeow.DisappearingTimer24Hours, whatsmeow.DisappearingTimer7Days, whatsmeow.DisappearingTimer90Days:
+		return nil
+	}
+	return errors.New(utils.T("default_disappearing_invalid"))
+}
+
+// SetDefaultDisappearing sets the disappearing timer applied to new chats.
+func SetDefaultDisappearing(seconds uint32) (DefaultDisappearing, error) {
+	if err := ValidateDefaultDisappearing(seconds); err != nil {
+		return DefaultDisappearing{}, err
+	}
+	cli, err := ConnectedClient()
+	if err != nil {
+		return DefaultDisappearing{}, err
+	}
+	if err := cli.SetDefaultDisappearingTimer(time.Duration(seconds) * time.Second); err != nil {
+		return DefaultDisappearing{}, err
+	}
+
+	defaultDisappearingMu.Lock()
+	defer defaultDisappearingMu.Unlock()
+	now := time.Now()
+	defaultDisappearing = DefaultDisappearing{Seconds: seconds, Known: true, SetAt: &now}
+	return defaultDisappearing, nil
+}
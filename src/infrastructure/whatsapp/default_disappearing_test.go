@@ -0,0 +1,22 @@
+package whatsapp
+
+import "testing"
+
+func TestValidateDefaultDisappearing(t *testing.T) {
+	for _, seconds := range []uint32{0, 86400, 604800, 7776000} {
+		if err := ValidateDefaultDisappearing(seconds); err != nil {
+			t.Errorf("ValidateDefaultDisappearing(%d) = %v, want nil", seconds, err)
+		}
+	}
+	for _, seconds := range []uint32{1, 3600, 2592000} {
+		if err := ValidateDefaultDisappearing(seconds); err == nil {
+			t.Errorf("ValidateDefaultDisappearing(%d) = nil, want an error", seconds)
+		}
+	}
+	if _, err := SetDefaultDisappearing(3600); err == nil {
+		t.Error("SetDefaultDisappearing should reject durations WhatsApp does not offer")
+	}
+	if setting := GetDefaultDisappearing(); setting.Known || setting.SetAt != nil {
+		t.Errorf("GetDefaultDisappearing() = %+v, want it unknown before a set", setting)
+	}
+}
@@ -0,0 +1,73 @@
+package whatsapp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// editedContent returns the ID of the edited message and its new content when
+// evt is a protocol edit.
+func editedContent(evt *events.Message) (string, *waProto.Message) {
+	protocolMessage := evt.Message.GetProtocolMessage()
+	if protocolMessage.GetType() != waProto.ProtocolMessage_MESSAGE_EDIT || protocolMessage.GetEditedMessage() == nil {
+		return "", nil
+	}
+	return protocolMessage.GetKey().GetID(), protocolMessage.GetEditedMessage()
+}
+
+// handleEditedMessage updates the stored copy of an edited message and, when
+// enabled, forwards the edit with its old and new text. It reports whether the
+// edit was forwarded, in which case the regular message webhook is skipped.
+func handleEditedMessage(evt *events.Message) bool {
+	messageID, edited := editedContent(evt)
+	if edited == nil {
+		return false
+	}
+	chat := evt.Info.Chat.String()
+	newText := messageText(edited)
+
+	oldText := ""
+	stored, err := utils.FindChatHistoryMessage(chat, messageID)
+	switch {
+	case err == nil:
+		oldText = stored.Content
+	case !errors.Is(err, utils.ErrRecordNotFound):
+		logrus.Warnf("Failed to read original of edited message %s: %v", messageID, err)
+	}
+	if _, err := utils.UpdateChatHistoryContent(chat, messageID, newText); err != nil {
+		logrus.Warnf("Failed to store edit of message %s: %v", messageID, err)
+	}
+
+	if !config.WhatsappWebhookEditedMessages {
+		return false
+	}
+	urls := utils.WebhookURLsForChat(chat)
+	if len(urls) == 0 {
+		return true
+	}
+	payload := map[string]interface{}{
+		"Type":       "edit_message",
+		"message_id": messageID,
+		"edit_id":    evt.Info.ID,
+		"old_text":   oldText,
+		"new_text":   newText,
+		"chat":       chat,
+		"sender":     evt.Info.Sender.ToNonAD().String(),
+		"from_me":    evt.Info.IsFromMe,
+		"timestamp":  evt.Info.Timestamp.Format(time.RFC3339),
+	}
+	go func() {
+		for _, url := range urls {
+			if err := SubmitWebhook(payload, url); err != nil {
+				logrus.Errorf("Failed to send edit webhook: %v", err)
+			}
+		}
+	}()
+	return true
+}
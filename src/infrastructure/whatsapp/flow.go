@@ -0,0 +1,125 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// flowButtonName names the native flow button that opens a WhatsApp Flow, and
+// the response sent back when the flow is completed.
+const flowButtonName = "galaxy_message"
+
+// Flow is a WhatsApp Flow, a form built in the WhatsApp Manager, sent as the
+// call to action of an interactive message.
+type Flow struct {
+	ID     string
+	Token  string // echoed back in the response to correlate it
+	CTA    string // label of the button opening the flow
+	Body   string
+	Header string
+	Footer string
+	Screen string         // first screen to open, the flow's default when empty
+	Data   map[string]any // initial data of the first screen
+	Draft  bool           // send the draft version of the flow instead of the published one
+}
+
+// buttonParams returns the parameters of the native flow button.
+func (f Flow) buttonParams() (string, error) {
+	params := map[string]any{
+		"flow_message_version": "3",
+		"flow_id":              f.ID,
+		"flow_cta":             f.CTA,
+		"flow_action":          "navigate",
+		"mode":                 "published",
+	}
+	if f.Token != "" {
+		params["flow_token"] = f.Token
+	}
+	if f.Draft {
+		params["mode"] = "draft"
+	}
+	if f.Screen != "" || len(f.Data) > 0 {
+		payload := map[string]any{}
+		if f.Screen != "" {
+			payload["screen"] = f.Screen
+		}
+		if len(f.Data) > 0 {
+			payload["data"] = f.Data
+		}
+		params["flow_action_payload"] = payload
+	}
+	encoded, err := json.Marshal(params)
+	return string(encoded), err
+}
+
+func (f Flow) message() (*waProto.Message, error) {
+	if f.ID == "" || f.CTA == "" || f.Body == "" {
+		return nil, errors.New("a flow needs an ID, a call to action and a body")
+	}
+	params, err := f.buttonParams()
+	if err != nil {
+		return nil, err
+	}
+	interactive := &waProto.InteractiveMessage{
+		Body: &waProto.InteractiveMessage_Body{Text: proto.String(f.Body)},
+		InteractiveMessage: &waProto.InteractiveMessage_NativeFlowMessage_{
+			NativeFlowMessage: &waProto.InteractiveMessage_NativeFlowMessage{
+				Buttons: []*waProto.InteractiveMessage_NativeFlowMessage_NativeFlowButton{{
+					Name:             proto.String(flowButtonName),
+					ButtonParamsJSON: proto.String(params),
+				}},
+				MessageVersion: proto.Int32(1),
+			},
+		},
+	}
+	if f.Header != "" {
+		interactive.Header = &waProto.InteractiveMessage_Header{Title: proto.String(f.Header)}
+	}
+	if f.Footer != "" {
+		interactive.Footer = &waProto.InteractiveMessage_Footer{Text: proto.String(f.Footer)}
+	}
+	return &waProto.Message{InteractiveMessage: interactive}, nil
+}
+
+// SendFlow sends a message whose button opens the flow.
+func SendFlow(ctx context.Context, jid types.JID, flow Flow) (whatsmeow.SendResponse, error) {
+	msg, err := flow.message()
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	return SendMessage(ctx, jid, msg)
+}
+
+// flowResponse returns the response to a completed flow, or nil when the
+// message is something else.
+func flowResponse(msg *waProto.Message) *waProto.InteractiveResponseMessage_NativeFlowResponseMessage {
+	response := msg.GetInteractiveResponseMessage().GetNativeFlowResponseMessage()
+	if response == nil || response.GetName() != flowButtonName {
+		return nil
+	}
+	return response
+}
+
+// flowResponsePayload describes a flow response for the webhook. The form data
+// travels inside the end-to-end encrypted message, so once the message is
+// decrypted it is plain JSON, holding the flow token and the submitted fields.
+func flowResponsePayload(response *waProto.InteractiveResponseMessage_NativeFlowResponseMessage) map[string]interface{} {
+	payload := map[string]interface{}{"name": response.GetName()}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(response.GetParamsJSON()), &data); err != nil {
+		payload["params_json"] = response.GetParamsJSON()
+		return payload
+	}
+	if token, ok := data["flow_token"].(string); ok {
+		payload["flow_token"] = token
+		delete(data, "flow_token")
+	}
+	payload["data"] = data
+	return payload
+}
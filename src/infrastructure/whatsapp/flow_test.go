@@ -0,0 +1,73 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"testing"
+
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFlowMessage(t *testing.T) {
+	if _, err := (Flow{ID: "123", CTA: "Open"}).message(); err == nil {
+		t.Error("a flow without a body should be rejected")
+	}
+
+	flow := Flow{ID: "123", Token: "order-42", CTA: "Book now", Body: "Pick a slot", Footer: "Acme", Screen: "BOOKING", Data: map[string]any{"branch": "downtown"}}
+	msg, err := flow.message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	interactive := msg.GetInteractiveMessage()
+	if interactive.GetBody().GetText() != "Pick a slot" || interactive.GetFooter().GetText() != "Acme" || interactive.GetHeader() != nil {
+		t.Errorf("interactive message = %v", interactive)
+	}
+	button := interactive.GetNativeFlowMessage().GetButtons()[0]
+	if button.GetName() != flowButtonName {
+		t.Errorf("button name = %q, want %q", button.GetName(), flowButtonName)
+	}
+	var params map[string]any
+	if err := json.Unmarshal([]byte(button.GetButtonParamsJSON()), &params); err != nil {
+		t.Fatal(err)
+	}
+	if params["flow_id"] != "123" || params["flow_token"] != "order-42" || params["flow_cta"] != "Book now" || params["mode"] != "published" {
+		t.Errorf("params = %v", params)
+	}
+	payload, _ := params["flow_action_payload"].(map[string]any)
+	if payload["screen"] != "BOOKING" || payload["data"].(map[string]any)["branch"] != "downtown" {
+		t.Errorf("flow_action_payload = %v", params["flow_action_payload"])
+	}
+}
+
+func TestFlowResponse(t *testing.T) {
+	evt := &events.Message{Message: &waProto.Message{InteractiveResponseMessage: &waProto.InteractiveResponseMessage{
+		InteractiveResponseMessage: &waProto.InteractiveResponseMessage_NativeFlowResponseMessage_{
+			NativeFlowResponseMessage: &waProto.InteractiveResponseMessage_NativeFlowResponseMessage{
+				Name:       proto.String(flowButtonName),
+				ParamsJSON: proto.String(`{"flow_token":"order-42","slot":"10:00","party_size":2}`),
+			},
+		},
+	}}}
+	if got := determineMessageType(evt, ""); got != "flow_response" {
+		t.Errorf("determineMessageType = %q, want flow_response", got)
+	}
+
+	payload := flowResponsePayload(flowResponse(evt.Message))
+	data, _ := payload["data"].(map[string]interface{})
+	if payload["flow_token"] != "order-42" || data["slot"] != "10:00" || data["party_size"] != float64(2) {
+		t.Errorf("payload = %v", payload)
+	}
+	if _, ok := data["flow_token"]; ok {
+		t.Error("the flow token should not be repeated in the form data")
+	}
+
+	other := &waProto.Message{InteractiveResponseMessage: &waProto.InteractiveResponseMessage{
+		InteractiveResponseMessage: &waProto.InteractiveResponseMessage_NativeFlowResponseMessage_{
+			NativeFlowResponseMessage: &waProto.InteractiveResponseMessage_NativeFlowResponseMessage{Name: proto.String("address_message")},
+		},
+	}}
+	if flowResponse(other) != nil {
+		t.Error("responses of other native flows are not flow responses")
+	}
+}
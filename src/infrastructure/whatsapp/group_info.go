@@ -0,0 +1,82 @@
+package whatsapp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// groupInfoTTL bounds how long the info of a group is reused without asking
+// WhatsApp. Renames and membership changes seen by this client update or drop
+// the entry right away.
+const groupInfoTTL = 30 * time.Minute
+
+// groupInfos caches group info by group JID, so neither the name of a group on
+// every forwarded message nor mentioning everyone costs a group info round-trip.
+var groupInfos = &groupInfoCache{entries: map[types.JID]cachedGroupInfo{}}
+
+type cachedGroupInfo struct {
+	info    *types.GroupInfo
+	expires time.Time
+}
+
+type groupInfoCache struct {
+	mu      sync.Mutex
+	entries map[types.JID]cachedGroupInfo
+}
+
+func (c *groupInfoCache) get(jid types.JID) (*types.GroupInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[jid]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.info, true
+}
+
+func (c *groupInfoCache) put(jid types.JID, info *types.GroupInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[jid] = cachedGroupInfo{info: info, expires: now.Add(groupInfoTTL)}
+}
+
+func (c *groupInfoCache) delete(jid types.JID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, jid)
+}
+
+// lookupGroupInfo returns the info of a group, from the cache when fresh.
+func lookupGroupInfo(jid types.JID) (*types.GroupInfo, error) {
+	if info, ok := groupInfos.get(jid); ok {
+		return info, nil
+	}
+	cli, err := ConnectedClient()
+	if err != nil {
+		return nil, err
+	}
+	info, err := cli.GetGroupInfo(jid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group info: %w", err)
+	}
+	groupInfos.put(jid, info)
+	return info, nil
+}
+
+// handleGroupParticipantsChange drops the cached info of a group when members
+// join, leave or change role.
+func handleGroupParticipantsChange(evt *events.GroupInfo) {
+	if len(evt.Join)+len(evt.Leave)+len(evt.Promote)+len(evt.Demote) > 0 {
+		groupInfos.delete(evt.JID)
+	}
+}
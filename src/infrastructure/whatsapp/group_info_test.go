@@ -0,0 +1,23 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestGroupInfoCacheInvalidation(t *testing.T) {
+	group := types.NewJID("120363000000000001", types.GroupServer)
+	groupInfos.put(group, &types.GroupInfo{JID: group})
+	t.Cleanup(func() { groupInfos.delete(group) })
+
+	handleGroupParticipantsChange(&events.GroupInfo{JID: group, Name: &types.GroupName{Name: "renamed"}})
+	if _, ok := groupInfos.get(group); !ok {
+		t.Error("a rename dropped the cached participants")
+	}
+	handleGroupParticipantsChange(&events.GroupInfo{JID: group, Join: []types.JID{types.NewJID("6283333333333", types.DefaultUserServer)}})
+	if _, ok := groupInfos.get(group); ok {
+		t.Error("a join kept the cached participants")
+	}
+}
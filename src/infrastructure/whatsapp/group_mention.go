@@ -0,0 +1,68 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// MaxMentionAll is the largest group whose members can all be mentioned at once.
+const MaxMentionAll = 512
+
+// ErrNotGroupAdmin is returned when mentioning everyone in a group the account
+// does not administer.
+var ErrNotGroupAdmin = pkgError.ValidationError("only group admins can mention everyone")
+
+// MentionAll returns the JIDs of every other member of a group and the
+// "@number" tokens mentioning them, for a message addressed to everyone. The
+// account must be an admin of the group, which may have at most MaxMentionAll
+// members.
+func MentionAll(group types.JID) (mentioned []string, tokens string, err error) {
+	if group.Server != types.GroupServer {
+		return nil, "", pkgError.ValidationError("mention_all is only available for groups")
+	}
+	info, err := lookupGroupInfo(group)
+	if err != nil {
+		return nil, "", err
+	}
+	cli, err := ConnectedClient()
+	if err != nil {
+		return nil, "", err
+	}
+	return mentionAllOf(info, cli.Store.ID, cli.Store.LID)
+}
+
+func mentionAllOf(info *types.GroupInfo, self *types.JID, selfLID types.JID) ([]string, string, error) {
+	isSelf := func(jid types.JID) bool {
+		return (self != nil && jid.User == self.User && jid.Server == self.Server) ||
+			(!selfLID.IsEmpty() && jid.User == selfLID.User && jid.Server == selfLID.Server)
+	}
+	if len(info.Participants) > MaxMentionAll {
+		return nil, "", pkgError.ValidationError(fmt.Sprintf("group has %d members, mention_all is limited to %d", len(info.Participants), MaxMentionAll))
+	}
+
+	admin := false
+	mentioned := make([]string, 0, len(info.Participants))
+	tokens := make([]string, 0, len(info.Participants))
+	for _, participant := range info.Participants {
+		if isSelf(participant.JID) || isSelf(participant.PhoneNumber) {
+			admin = participant.IsAdmin || participant.IsSuperAdmin
+			continue
+		}
+		// In LID-addressed groups the JID user is not a phone number. Members
+		// whose phone number is known are mentioned by it, so the "@number"
+		// token and the mentioned JID always name the same account.
+		jid := participant.JID
+		if jid.Server == types.HiddenUserServer && !participant.PhoneNumber.IsEmpty() {
+			jid = participant.PhoneNumber
+		}
+		mentioned = append(mentioned, jid.String())
+		tokens = append(tokens, "@"+jid.User)
+	}
+	if !admin {
+		return nil, "", ErrNotGroupAdmin
+	}
+	return mentioned, strings.Join(tokens, " "), nil
+}
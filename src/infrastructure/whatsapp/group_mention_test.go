@@ -0,0 +1,43 @@
+package whatsapp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestMentionAllOf(t *testing.T) {
+	self := types.NewJID("6281111111111", types.DefaultUserServer)
+	selfLID := types.NewJID("99887766", types.HiddenUserServer)
+	member := types.NewJID("6282222222222", types.DefaultUserServer)
+	lidMember := types.NewJID("11223344", types.HiddenUserServer)
+	lidMemberPhone := types.NewJID("6284444444444", types.DefaultUserServer)
+	info := &types.GroupInfo{Participants: []types.GroupParticipant{
+		{JID: selfLID, PhoneNumber: self, IsAdmin: true},
+		{JID: member},
+		{JID: lidMember, PhoneNumber: lidMemberPhone},
+	}}
+
+	mentioned, tokens, err := mentionAllOf(info, &self, selfLID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mentioned) != 2 || mentioned[0] != member.String() || mentioned[1] != lidMemberPhone.String() {
+		t.Errorf("mentioned = %v, want the other members", mentioned)
+	}
+	if tokens != "@6282222222222 @6284444444444" {
+		t.Errorf("tokens = %q", tokens)
+	}
+
+	info.Participants[0].IsAdmin = false
+	if _, _, err := mentionAllOf(info, &self, selfLID); !errors.Is(err, ErrNotGroupAdmin) {
+		t.Errorf("err = %v, want ErrNotGroupAdmin", err)
+	}
+
+	info.Participants = make([]types.GroupParticipant, MaxMentionAll+1)
+	if _, _, err := mentionAllOf(info, &self, selfLID); err == nil || !strings.Contains(err.Error(), "limited") {
+		t.Errorf("err = %v, want the member cap", err)
+	}
+}
@@ -0,0 +1,40 @@
+package whatsapp
+
+import (
+	"context"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// GetGroupName returns the name of a group, from the cache while it is fresh.
+// It returns an empty name for JIDs that are not groups.
+func GetGroupName(ctx context.Context, jid types.JID) (string, error) {
+	if jid.Server != types.GroupServer {
+		return "", nil
+	}
+	info, err := lookupGroupInfo(jid)
+	if err != nil {
+		return "", err
+	}
+	return info.GroupName.Name, nil
+}
+
+// RefreshGroupName drops the cached name of a group and fetches it again.
+func RefreshGroupName(ctx context.Context, jid types.JID) (string, error) {
+	groupInfos.delete(jid)
+	return GetGroupName(ctx, jid)
+}
+
+// handleGroupNameChange keeps the cached info in step with renames seen by the
+// client. The info is copied, callers may still hold the previous one.
+func handleGroupNameChange(evt *events.GroupInfo) {
+	if evt.Name == nil {
+		return
+	}
+	if info, ok := groupInfos.get(evt.JID); ok {
+		renamed := *info
+		renamed.GroupName = *evt.Name
+		groupInfos.put(evt.JID, &renamed)
+	}
+}
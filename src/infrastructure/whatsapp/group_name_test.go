@@ -0,0 +1,37 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestGroupNameCache(t *testing.T) {
+	group := types.NewJID("120363000000000001", types.GroupServer)
+	member := types.NewJID("6282222222222", types.DefaultUserServer)
+	t.Cleanup(func() { groupInfos.delete(group) })
+
+	if name, err := GetGroupName(context.Background(), types.NewJID("6281111111111", types.DefaultUserServer)); err != nil || name != "" {
+		t.Errorf("GetGroupName(user) = %q, %v; want empty", name, err)
+	}
+
+	original := &types.GroupInfo{JID: group, GroupName: types.GroupName{Name: "Lunch"}, Participants: []types.GroupParticipant{{JID: member}}}
+	groupInfos.put(group, original)
+	handleGroupNameChange(&events.GroupInfo{JID: group, Name: &types.GroupName{Name: "Renamed"}})
+	if name, err := GetGroupName(context.Background(), group); err != nil || name != "Renamed" {
+		t.Errorf("GetGroupName() = %q, %v; want the renamed name from the cache", name, err)
+	}
+	if info, _ := groupInfos.get(group); len(info.Participants) != 1 {
+		t.Errorf("a rename dropped the cached participants: %+v", info)
+	}
+	if original.GroupName.Name != "Lunch" {
+		t.Errorf("a rename changed the info held by earlier callers to %q", original.GroupName.Name)
+	}
+
+	handleGroupNameChange(&events.GroupInfo{JID: group})
+	if info, _ := groupInfos.get(group); info.GroupName.Name != "Renamed" {
+		t.Errorf("cached name = %q after an event without a rename, want it kept", info.GroupName.Name)
+	}
+}
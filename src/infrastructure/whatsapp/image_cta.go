@@ -0,0 +1,188 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// MaxCTAButtons is the number of buttons WhatsApp shows under a message.
+	MaxCTAButtons = 3
+	// maxCTAButtonText is the longest button label WhatsApp displays uncut.
+	maxCTAButtonText = 20
+)
+
+// Kinds of call-to-action buttons.
+const (
+	CTAButtonURL   = "url"   // opens a website
+	CTAButtonCall  = "call"  // calls a phone number
+	CTAButtonReply = "reply" // sends a quick reply back to the chat
+)
+
+// nativeFlowNames maps button kinds to their native flow button names.
+var nativeFlowNames = map[string]string{
+	CTAButtonURL:   "cta_url",
+	CTAButtonCall:  "cta_call",
+	CTAButtonReply: "quick_reply",
+}
+
+// CTAButton is a button under an image. URL and call taps are handled on the
+// recipient's device and never reported back; only reply taps come back, as a
+// "button_response" message.
+type CTAButton struct {
+	Type  string `json:"type"` // url, call or reply
+	Text  string `json:"text"`
+	URL   string `json:"url,omitempty"`
+	Phone string `json:"phone,omitempty"`
+	ID    string `json:"id,omitempty"` // echoed back when a reply button is tapped
+}
+
+// ValidateCTAButtons checks the number of buttons and the fields each kind needs.
+func ValidateCTAButtons(buttons []CTAButton) error {
+	if len(buttons) == 0 || len(buttons) > MaxCTAButtons {
+		return errors.New(utils.T("cta_button_count_invalid", MaxCTAButtons, len(buttons)))
+	}
+	for i, button := range buttons {
+		if button.Text == "" || utf8.RuneCountInString(button.Text) > maxCTAButtonText {
+			return errors.New(utils.T("cta_button_text_invalid", i+1, maxCTAButtonText))
+		}
+		switch button.Type {
+		case CTAButtonURL:
+			parsed, err := url.Parse(button.URL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return errors.New(utils.T("cta_button_url_invalid", i+1))
+			}
+		case CTAButtonCall:
+			phone := strings.TrimPrefix(button.Phone, "+")
+			if phone == "" || strings.Trim(phone, "0123456789") != "" {
+				return errors.New(utils.T("cta_button_phone_invalid", i+1))
+			}
+		case CTAButtonReply:
+			if button.ID == "" {
+				return errors.New(utils.T("cta_button_id_required", i+1))
+			}
+		default:
+			return errors.New(utils.T("cta_button_type_invalid", i+1))
+		}
+	}
+	return nil
+}
+
+// nativeFlowButton converts a button to the native flow button WhatsApp renders.
+func (b CTAButton) nativeFlowButton() (*waProto.InteractiveMessage_NativeFlowMessage_NativeFlowButton, error) {
+	params := map[string]string{"display_text": b.Text}
+	switch b.Type {
+	case CTAButtonURL:
+		params["url"], params["merchant_url"] = b.URL, b.URL
+	case CTAButtonCall:
+		params["phone_number"] = "+" + strings.TrimPrefix(b.Phone, "+")
+	case CTAButtonReply:
+		params["id"] = b.ID
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	return &waProto.InteractiveMessage_NativeFlowMessage_NativeFlowButton{
+		Name:             proto.String(nativeFlowNames[b.Type]),
+		ButtonParamsJSON: proto.String(string(encoded)),
+	}, nil
+}
+
+// SendImageCTA sends an image with text and up to MaxCTAButtons buttons under it.
+func SendImageCTA(ctx context.Context, jid types.JID, imageData []byte, mimeType, text, footer string, buttons []CTAButton) (whatsmeow.SendResponse, error) {
+	if err := ValidateCTAButtons(buttons); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	if err := CheckRecipient(jid); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	cli, err := ConnectedClient()
+	if err != nil {
+		logrus.Errorf("Cannot send to %s: %v", jid.String(), err)
+		return whatsmeow.SendResponse{}, err
+	}
+	if int64(len(imageData)) > config.WhatsappSettingMaxFileSize {
+		return whatsmeow.SendResponse{}, fmt.Errorf("image size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxFileSize)
+	}
+
+	upload, err := UploadMedia(ctx, cli, imageData, whatsmeow.MediaImage)
+	if err != nil {
+		logrus.Errorf("Upload failed: %v, Data length: %d", err, len(imageData))
+		return whatsmeow.SendResponse{}, fmt.Errorf("failed to upload image: %v", err)
+	}
+	image := &waProto.ImageMessage{
+		Mimetype:      proto.String(mimeType),
+		URL:           proto.String(upload.URL),
+		DirectPath:    proto.String(upload.DirectPath),
+		MediaKey:      upload.MediaKey,
+		FileEncSHA256: upload.FileEncSHA256,
+		FileSHA256:    upload.FileSHA256,
+		FileLength:    proto.Uint64(uint64(len(imageData))),
+	}
+
+	msg, err := imageCTAMessage(image, text, footer, buttons)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	return SendMessage(ctx, jid, msg)
+}
+
+func imageCTAMessage(image *waProto.ImageMessage, text, footer string, buttons []CTAButton) (*waProto.Message, error) {
+	nativeFlow := &waProto.InteractiveMessage_NativeFlowMessage{MessageVersion: proto.Int32(1)}
+	for _, button := range buttons {
+		converted, err := button.nativeFlowButton()
+		if err != nil {
+			return nil, err
+		}
+		nativeFlow.Buttons = append(nativeFlow.Buttons, converted)
+	}
+	interactive := &waProto.InteractiveMessage{
+		Header: &waProto.InteractiveMessage_Header{
+			HasMediaAttachment: proto.Bool(true),
+			Media:              &waProto.InteractiveMessage_Header_ImageMessage{ImageMessage: image},
+		},
+		Body:               &waProto.InteractiveMessage_Body{Text: proto.String(text)},
+		InteractiveMessage: &waProto.InteractiveMessage_NativeFlowMessage_{NativeFlowMessage: nativeFlow},
+	}
+	if footer != "" {
+		interactive.Footer = &waProto.InteractiveMessage_Footer{Text: proto.String(footer)}
+	}
+	return &waProto.Message{InteractiveMessage: interactive}, nil
+}
+
+// buttonResponse returns the response to a tapped reply button, or nil when the
+// message is something else.
+func buttonResponse(msg *waProto.Message) *waProto.InteractiveResponseMessage {
+	response := msg.GetInteractiveResponseMessage()
+	if response.GetNativeFlowResponseMessage().GetName() != nativeFlowNames[CTAButtonReply] {
+		return nil
+	}
+	return response
+}
+
+// buttonResponsePayload describes a tapped reply button for the webhook: the ID
+// given to the button and the text it showed.
+func buttonResponsePayload(response *waProto.InteractiveResponseMessage) map[string]interface{} {
+	payload := map[string]interface{}{"text": response.GetBody().GetText()}
+	var params struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(response.GetNativeFlowResponseMessage().GetParamsJSON()), &params); err == nil {
+		payload["id"] = params.ID
+	}
+	return payload
+}
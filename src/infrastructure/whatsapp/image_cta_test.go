@@ -0,0 +1,89 @@
+package whatsapp
+
+import (
+	"strings"
+	"testing"
+
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestValidateCTAButtons(t *testing.T) {
+	valid := []CTAButton{
+		{Type: CTAButtonURL, Text: "Visit website", URL: "https://example.com/sale"},
+		{Type: CTAButtonCall, Text: "Call us", Phone: "+6281234567890"},
+		{Type: CTAButtonReply, Text: "Not now", ID: "dismiss"},
+	}
+	if err := ValidateCTAButtons(valid); err != nil {
+		t.Errorf("ValidateCTAButtons(valid) = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		buttons []CTAButton
+		want    string
+	}{
+		{"no buttons", nil, "between 1 and 3"},
+		{"too many buttons", append(valid, valid[0]), "between 1 and 3"},
+		{"relative url", []CTAButton{{Type: CTAButtonURL, Text: "Open", URL: "/sale"}}, "http or https"},
+		{"javascript url", []CTAButton{{Type: CTAButtonURL, Text: "Open", URL: "javascript:alert(1)"}}, "http or https"},
+		{"phone with letters", []CTAButton{{Type: CTAButtonCall, Text: "Call", Phone: "call-me"}}, "international format"},
+		{"reply without id", []CTAButton{{Type: CTAButtonReply, Text: "Yes"}}, "id is required"},
+		{"long text", []CTAButton{{Type: CTAButtonReply, Text: strings.Repeat("x", 21), ID: "x"}}, "1 to 20"},
+		{"unknown type", []CTAButton{{Type: "copy", Text: "Copy"}}, "url, call or reply"},
+	}
+	for _, tt := range tests {
+		if err := ValidateCTAButtons(tt.buttons); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ValidateCTAButtons() = %v, want an error mentioning %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestImageCTAMessage(t *testing.T) {
+	image := &waProto.ImageMessage{Mimetype: proto.String("image/jpeg")}
+	buttons := []CTAButton{
+		{Type: CTAButtonURL, Text: "Visit website", URL: "https://example.com"},
+		{Type: CTAButtonCall, Text: "Call us", Phone: "6281234567890"},
+	}
+	msg, err := imageCTAMessage(image, "Spring sale", "", buttons)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interactive := msg.GetInteractiveMessage()
+	if interactive.GetHeader().GetImageMessage() != image || !interactive.GetHeader().GetHasMediaAttachment() {
+		t.Errorf("header = %v, want the image attached", interactive.GetHeader())
+	}
+	if interactive.GetBody().GetText() != "Spring sale" || interactive.GetFooter() != nil {
+		t.Errorf("body = %v, footer = %v", interactive.GetBody(), interactive.GetFooter())
+	}
+	flowButtons := interactive.GetNativeFlowMessage().GetButtons()
+	if len(flowButtons) != 2 || flowButtons[0].GetName() != "cta_url" || flowButtons[1].GetName() != "cta_call" {
+		t.Fatalf("buttons = %v", flowButtons)
+	}
+	if !strings.Contains(flowButtons[1].GetButtonParamsJSON(), `"phone_number":"+6281234567890"`) {
+		t.Errorf("call button params = %s", flowButtons[1].GetButtonParamsJSON())
+	}
+}
+
+func TestButtonResponse(t *testing.T) {
+	evt := &events.Message{Message: &waProto.Message{InteractiveResponseMessage: &waProto.InteractiveResponseMessage{
+		Body: &waProto.InteractiveResponseMessage_Body{Text: proto.String("Not now")},
+		InteractiveResponseMessage: &waProto.InteractiveResponseMessage_NativeFlowResponseMessage_{
+			NativeFlowResponseMessage: &waProto.InteractiveResponseMessage_NativeFlowResponseMessage{
+				Name:       proto.String("quick_reply"),
+				ParamsJSON: proto.String(`{"id":"dismiss"}`),
+			},
+		},
+	}}}
+	if got := determineMessageType(evt, ""); got != "button_response" {
+		t.Errorf("determineMessageType = %q, want button_response", got)
+	}
+	payload := buttonResponsePayload(buttonResponse(evt.Message))
+	if payload["id"] != "dismiss" || payload["text"] != "Not now" {
+		t.Errorf("payload = %v", payload)
+	}
+	if buttonResponse(&waProto.Message{Conversation: proto.String("hi")}) != nil {
+		t.Error("a text message is not a button response")
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	waProto "go.mau.fi/whatsmeow/proto/waE2E"
@@ -28,6 +29,7 @@ type ExtractedMedia struct {
 	MediaPath string `json:"media_path"`
 	MimeType  string `json:"mime_type"`
 	Caption   string `json:"caption"`
+	Base64    string `json:"base64,omitempty"` // data URI, only for media under the webhook inline limit
 }
 
 type evtReaction struct {
@@ -43,7 +45,6 @@ type evtMessage struct {
 }
 
 var (
-	cli                *whatsmeow.Client
 	log                waLog.Logger
 	historySyncID      int32
 	startupTime        = time.Now().Unix()
@@ -91,55 +92,46 @@ func InitWaCLI(ctx context.Context, storeContainer *sqlstore.Container) *whatsme
 	store.DeviceProps.PlatformType = &config.AppPlatform
 	store.DeviceProps.Os = &osName
 
-	cli = whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
+	cli := whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
 	cli.EnableAutoReconnect = true
 	cli.AutoTrustIdentity = true
+	configureRetries(cli)
 	cli.AddEventHandler(func(rawEvt interface{}) {
 		handler(ctx, rawEvt)
 	})
+	client.Store(cli)
 
 	return cli
 }
 
-func GetWaCli() *whatsmeow.Client {
-	return cli
-}
-
-func GetGroupName(ctx context.Context, jid types.JID) (string, error) {
-	if !strings.Contains(jid.String(), "@g.us") {
-		return "", nil
-	}
-	groupInfo, err := cli.GetGroupInfo(jid)
-	if err != nil {
-		return "", fmt.Errorf("failed to get group info: %v", err)
-	}
-	return groupInfo.GroupName.Name, nil
-}
-
-func SendAudioMessage(ctx context.Context, jid types.JID, audioData []byte, mimeType string) error {
-	if cli == nil {
-		logrus.Error("WhatsApp client is nil")
-		return fmt.Errorf("WhatsApp client not initialized")
+// SendAudioMessage uploads and sends an audio file. A view-once audio is sent as
+// a voice note, the only form WhatsApp plays once.
+func SendAudioMessage(ctx context.Context, jid types.JID, audioData []byte, mimeType string, viewOnce bool) (whatsmeow.SendResponse, error) {
+	if viewOnce {
+		if err := ValidateViewOnce("audio", mimeType); err != nil {
+			return whatsmeow.SendResponse{}, err
+		}
 	}
 
-	if !cli.IsConnected() {
-		logrus.Error("WhatsApp client not connected")
-		return fmt.Errorf("WhatsApp client not connected")
+	// Refuse a forbidden recipient before spending an upload on it.
+	if err := CheckRecipient(jid); err != nil {
+		return whatsmeow.SendResponse{}, err
 	}
 
-	if !cli.IsLoggedIn() {
-		logrus.Error("WhatsApp client not logged in")
-		return fmt.Errorf("WhatsApp client not logged in")
+	cli, err := ConnectedClient()
+	if err != nil {
+		logrus.Errorf("Cannot send to %s: %v", jid.String(), err)
+		return whatsmeow.SendResponse{}, err
 	}
 
 	if int64(len(audioData)) > config.WhatsappSettingMaxFileSize {
-		return fmt.Errorf("audio size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxFileSize)
+		return whatsmeow.SendResponse{}, fmt.Errorf("audio size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxFileSize)
 	}
 
-	upload, err := cli.Upload(ctx, audioData, whatsmeow.MediaAudio)
+	upload, err := UploadMedia(ctx, cli, audioData, whatsmeow.MediaAudio)
 	if err != nil {
 		logrus.Errorf("Upload failed: %v, Data length: %d", err, len(audioData))
-		return fmt.Errorf("failed to upload audio: %v", err)
+		return whatsmeow.SendResponse{}, fmt.Errorf("failed to upload audio: %v", err)
 	}
 
 	msg := &waProto.Message{
@@ -151,43 +143,46 @@ func SendAudioMessage(ctx context.Context, jid types.JID, audioData []byte, mime
 			FileEncSHA256: upload.FileEncSHA256,
 			FileSHA256:    upload.FileSHA256,
 			FileLength:    proto.Uint64(uint64(len(audioData))),
-			PTT:           proto.Bool(false),
+			PTT:           proto.Bool(viewOnce),
+			ViewOnce:      proto.Bool(viewOnce),
 		},
 	}
 
-	_, err = cli.SendMessage(ctx, jid, msg)
+	resp, err := SendMessage(ctx, jid, msg)
 	if err != nil {
 		logrus.Errorf("Failed to send audio message to %s: %v", jid.String(), err)
-		return err
+		return resp, err
 	}
 	logrus.Infof("Audio message sent successfully to %s", jid.String())
-	return nil
+	return resp, nil
 }
 
-func SendDocumentMessage(ctx context.Context, jid types.JID, documentData []byte, mimeType, fileName, caption string, isForwarded bool) error {
-	if cli == nil {
-		logrus.Error("WhatsApp client is nil")
-		return fmt.Errorf("WhatsApp client not initialized")
+// SendDocumentMessage uploads and sends a document. The thumbnail is an optional
+// JPEG preview; without one, PDFs get their first page rendered when possible.
+// WhatsApp has no view-once documents, so viewOnce is always rejected.
+func SendDocumentMessage(ctx context.Context, jid types.JID, documentData []byte, mimeType, fileName, caption string, isForwarded, viewOnce bool, thumbnail []byte) (whatsmeow.SendResponse, error) {
+	if viewOnce {
+		return whatsmeow.SendResponse{}, ValidateViewOnce("document", mimeType)
 	}
 
-	if !cli.IsConnected() {
-		logrus.Error("WhatsApp client not connected")
-		return fmt.Errorf("WhatsApp client not connected")
+	if err := CheckRecipient(jid); err != nil {
+		return whatsmeow.SendResponse{}, err
 	}
 
-	if !cli.IsLoggedIn() {
-		logrus.Error("WhatsApp client not logged in")
-		return fmt.Errorf("WhatsApp client not logged in")
+	cli, err := ConnectedClient()
+	if err != nil {
+		logrus.Errorf("Cannot send to %s: %v", jid.String(), err)
+		return whatsmeow.SendResponse{}, err
 	}
 
 	if int64(len(documentData)) > config.WhatsappSettingMaxFileSize {
-		return fmt.Errorf("document size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxFileSize)
+		return whatsmeow.SendResponse{}, fmt.Errorf("document size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxFileSize)
 	}
 
-	upload, err := cli.Upload(ctx, documentData, whatsmeow.MediaDocument)
+	upload, err := UploadMedia(ctx, cli, documentData, whatsmeow.MediaDocument)
 	if err != nil {
 		logrus.Errorf("Upload failed: %v, Data length: %d", err, len(documentData))
-		return fmt.Errorf("failed to upload document: %v", err)
+		return whatsmeow.SendResponse{}, fmt.Errorf("failed to upload document: %v", err)
 	}
 
 	docMsg := &waProto.DocumentMessage{
@@ -202,6 +197,15 @@ func SendDocumentMessage(ctx context.Context, jid types.JID, documentData []byte
 		Caption:       proto.String(caption),
 	}
 
+	if thumbnail == nil {
+		thumbnail = DocumentThumbnail(ctx, documentData, mimeType)
+	}
+	if width, height, ok := thumbnailSize(thumbnail); ok {
+		docMsg.JPEGThumbnail = thumbnail
+		docMsg.ThumbnailWidth = proto.Uint32(width)
+		docMsg.ThumbnailHeight = proto.Uint32(height)
+	}
+
 	if isForwarded {
 		docMsg.ContextInfo = &waProto.ContextInfo{
 			IsForwarded: proto.Bool(true),
@@ -212,39 +216,34 @@ func SendDocumentMessage(ctx context.Context, jid types.JID, documentData []byte
 		DocumentMessage: docMsg,
 	}
 
-	_, err = cli.SendMessage(ctx, jid, msg)
+	resp, err := SendMessage(ctx, jid, msg)
 	if err != nil {
 		logrus.Errorf("Failed to send document message to %s: %v", jid.String(), err)
-		return err
+		return resp, err
 	}
 	logrus.Infof("Document message sent successfully to %s", jid.String())
-	return nil
+	return resp, nil
 }
 
-func SendVideoMessage(ctx context.Context, jid types.JID, videoData []byte, mimeType, fileName, caption string, viewOnce, isForwarded bool) error {
-	if cli == nil {
-		logrus.Error("WhatsApp client is nil")
-		return fmt.Errorf("WhatsApp client not initialized")
-	}
-
-	if !cli.IsConnected() {
-		logrus.Error("WhatsApp client not connected")
-		return fmt.Errorf("WhatsApp client not connected")
+func SendVideoMessage(ctx context.Context, jid types.JID, videoData []byte, mimeType, fileName, caption string, viewOnce, isForwarded, gifPlayback bool) (whatsmeow.SendResponse, error) {
+	if err := CheckRecipient(jid); err != nil {
+		return whatsmeow.SendResponse{}, err
 	}
 
-	if !cli.IsLoggedIn() {
-		logrus.Error("WhatsApp client not logged in")
-		return fmt.Errorf("WhatsApp client not logged in")
+	cli, err := ConnectedClient()
+	if err != nil {
+		logrus.Errorf("Cannot send to %s: %v", jid.String(), err)
+		return whatsmeow.SendResponse{}, err
 	}
 
 	if int64(len(videoData)) > config.WhatsappSettingMaxVideoSize {
-		return fmt.Errorf("video size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxVideoSize)
+		return whatsmeow.SendResponse{}, fmt.Errorf("video size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxVideoSize)
 	}
 
-	upload, err := cli.Upload(ctx, videoData, whatsmeow.MediaVideo)
+	upload, err := UploadMedia(ctx, cli, videoData, whatsmeow.MediaVideo)
 	if err != nil {
 		logrus.Errorf("Upload failed: %v, Data length: %d", err, len(videoData))
-		return fmt.Errorf("failed to upload video: %v", err)
+		return whatsmeow.SendResponse{}, fmt.Errorf("failed to upload video: %v", err)
 	}
 
 	videoMsg := &waProto.VideoMessage{
@@ -257,6 +256,7 @@ func SendVideoMessage(ctx context.Context, jid types.JID, videoData []byte, mime
 		FileLength:    proto.Uint64(uint64(len(videoData))),
 		Caption:       proto.String(caption),
 		ViewOnce:      proto.Bool(viewOnce),
+		GifPlayback:   proto.Bool(gifPlayback),
 	}
 
 	if isForwarded {
@@ -269,39 +269,34 @@ func SendVideoMessage(ctx context.Context, jid types.JID, videoData []byte, mime
 		VideoMessage: videoMsg,
 	}
 
-	_, err = cli.SendMessage(ctx, jid, msg)
+	resp, err := SendMessage(ctx, jid, msg)
 	if err != nil {
 		logrus.Errorf("Failed to send video message to %s: %v", jid.String(), err)
-		return err
+		return resp, err
 	}
 	logrus.Infof("Video message sent successfully to %s", jid.String())
-	return nil
+	return resp, nil
 }
 
-func SendImageMessage(ctx context.Context, jid types.JID, imageData []byte, mimeType, fileName, caption string, viewOnce, isForwarded bool) error {
-	if cli == nil {
-		logrus.Error("WhatsApp client is nil")
-		return fmt.Errorf("WhatsApp client not initialized")
-	}
-
-	if !cli.IsConnected() {
-		logrus.Error("WhatsApp client not connected")
-		return fmt.Errorf("WhatsApp client not connected")
+func SendImageMessage(ctx context.Context, jid types.JID, imageData []byte, mimeType, fileName, caption string, viewOnce, isForwarded bool) (whatsmeow.SendResponse, error) {
+	if err := CheckRecipient(jid); err != nil {
+		return whatsmeow.SendResponse{}, err
 	}
 
-	if !cli.IsLoggedIn() {
-		logrus.Error("WhatsApp client not logged in")
-		return fmt.Errorf("WhatsApp client not logged in")
+	cli, err := ConnectedClient()
+	if err != nil {
+		logrus.Errorf("Cannot send to %s: %v", jid.String(), err)
+		return whatsmeow.SendResponse{}, err
 	}
 
 	if int64(len(imageData)) > config.WhatsappSettingMaxFileSize {
-		return fmt.Errorf("image size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxFileSize)
+		return whatsmeow.SendResponse{}, fmt.Errorf("image size exceeds the maximum limit of %d bytes", config.WhatsappSettingMaxFileSize)
 	}
 
-	upload, err := cli.Upload(ctx, imageData, whatsmeow.MediaImage)
+	upload, err := UploadMedia(ctx, cli, imageData, whatsmeow.MediaImage)
 	if err != nil {
 		logrus.Errorf("Upload failed: %v, Data length: %d", err, len(imageData))
-		return fmt.Errorf("failed to upload image: %v", err)
+		return whatsmeow.SendResponse{}, fmt.Errorf("failed to upload image: %v", err)
 	}
 
 	imageMsg := &waProto.ImageMessage{
@@ -326,29 +321,20 @@ func SendImageMessage(ctx context.Context, jid types.JID, imageData []byte, mime
 		ImageMessage: imageMsg,
 	}
 
-	_, err = cli.SendMessage(ctx, jid, msg)
+	resp, err := SendMessage(ctx, jid, msg)
 	if err != nil {
 		logrus.Errorf("Failed to send image message to %s: %v", jid.String(), err)
-		return err
+		return resp, err
 	}
 	logrus.Infof("Image message sent successfully to %s", jid.String())
-	return nil
+	return resp, nil
 }
 
-func SendLocationMessage(ctx context.Context, jid types.JID, latitude, longitude float64) error {
-	if cli == nil {
-		logrus.Error("WhatsApp client is nil")
-		return fmt.Errorf("WhatsApp client not initialized")
-	}
-
-	if !cli.IsConnected() {
-		logrus.Error("WhatsApp client not connected")
-		return fmt.Errorf("WhatsApp client not connected")
-	}
-
-	if !cli.IsLoggedIn() {
-		logrus.Error("WhatsApp client not logged in")
-		return fmt.Errorf("WhatsApp client not logged in")
+func SendLocationMessage(ctx context.Context, jid types.JID, latitude, longitude float64) (whatsmeow.SendResponse, error) {
+	_, err := ConnectedClient()
+	if err != nil {
+		logrus.Errorf("Cannot send to %s: %v", jid.String(), err)
+		return whatsmeow.SendResponse{}, err
 	}
 
 	msg := &waProto.Message{
@@ -358,13 +344,13 @@ func SendLocationMessage(ctx context.Context, jid types.JID, latitude, longitude
 		},
 	}
 
-	_, err := cli.SendMessage(ctx, jid, msg)
+	resp, err := SendMessage(ctx, jid, msg)
 	if err != nil {
 		logrus.Errorf("Failed to send location message to %s: %v", jid.String(), err)
-		return err
+		return resp, err
 	}
 	logrus.Infof("Location message sent successfully to %s", jid.String())
-	return nil
+	return resp, nil
 }
 
 func handler(ctx context.Context, rawEvt interface{}) {
@@ -383,14 +369,28 @@ func handler(ctx context.Context, rawEvt interface{}) {
 		handleStreamReplaced(ctx)
 	case *events.Message:
 		handleMessage(ctx, evt)
+	case *events.Presence:
+		handlePresence(evt)
+	case *events.UndecryptableMessage:
+		handleUndecryptableMessage(evt)
 	case *events.Receipt:
 	 handleReceipt(ctx, evt)
 	case *events.HistorySync:
 		handleHistorySync(ctx, evt)
+	case *events.LabelEdit:
+		handleLabelEdit(evt)
+	case *events.LabelAssociationChat:
+		handleLabelAssociationChat(evt)
 	case *events.AppState:
 		handleAppState(ctx, evt)
 	case *events.CallOffer:
 		handleCallOffer(ctx, evt)
+	case *events.GroupInfo:
+		handleGroupNameChange(evt)
+		handleGroupParticipantsChange(evt)
+		handleCommunityChange(evt)
+	case *events.NewsletterLiveUpdate:
+		handleNewsletterLiveUpdate(evt)
 	default:
 		logrus.Debugf("Received unhandled event type: %T", rawEvt)
 	}
@@ -401,12 +401,8 @@ func handleDeleteForMe(_ context.Context, evt *events.DeleteForMe) {
 }
 
 func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncComplete) {
-	if len(cli.Store.PushName) > 0 && evt.Name == appstate.WAPatchCriticalBlock {
-		if err := cli.SendPresence(types.PresenceAvailable); err != nil {
-			log.Warnf("Failed to send available presence: %v", err)
-		} else {
-			log.Infof("Marked self as available")
-		}
+	if len(GetWaCli().Store.PushName) > 0 && evt.Name == appstate.WAPatchCriticalBlock {
+		sendPresenceOnConnect()
 	}
 }
 
@@ -419,14 +415,35 @@ func handleLoggedOut(_ context.Context) {
 }
 
 func handleConnected(_ context.Context) {
-	if len(cli.Store.PushName) == 0 {
+	reconnecting.Store(false)
+	if len(GetWaCli().Store.PushName) == 0 {
+		return
+	}
+
+	sendPresenceOnConnect()
+	go subscribeAdminNewsletters(context.Background())
+}
+
+// sendPresenceOnConnect applies config.WhatsappPresenceOnConnect. WhatsApp only
+// delivers presence updates of subscribed contacts while we are available.
+func sendPresenceOnConnect() {
+	var presence types.Presence
+	switch config.WhatsappPresenceOnConnect {
+	case "none":
+		return
+	case string(types.PresenceAvailable):
+		presence = types.PresenceAvailable
+	case string(types.PresenceUnavailable):
+		presence = types.PresenceUnavailable
+	default:
+		log.Warnf("Unknown presence on connect %q, expected available, unavailable or none", config.WhatsappPresenceOnConnect)
 		return
 	}
 
-	if err := cli.SendPresence(types.PresenceAvailable); err != nil {
-		log.Warnf("Failed to send available presence: %v", err)
+	if err := GetWaCli().SendPresence(presence); err != nil {
+		log.Warnf("Failed to send %s presence: %v", presence, err)
 	} else {
-		log.Infof("Marked self as available")
+		log.Infof("Marked self as %s", presence)
 	}
 }
 
@@ -435,6 +452,7 @@ func handleStreamReplaced(_ context.Context) {
 }
 
 func handleMessage(ctx context.Context, evt *events.Message) {
+	unwrapViewOnce(evt)
 	metaParts := buildMessageMetaParts(evt)
 	logrus.Infof("Mensagem recebida %s de %s (%s): %+v",
 		evt.Info.ID,
@@ -448,6 +466,11 @@ func handleMessage(ctx context.Context, evt *events.Message) {
 		RecordMessage(evt.Info.ID, evt.Info.Sender.String(), ExtractMessageText(evt))
 	}
 
+	if handleEditedMessage(evt) {
+		return
+	}
+	linkRequestResponse(evt)
+
 	// Log detalhado para verificar se o PollCreationMessage é recebido
 	if pollCreation := evt.Message.GetPollCreationMessage(); pollCreation != nil {
 		pollID := evt.Info.ID
@@ -477,13 +500,40 @@ func handleMessage(ctx context.Context, evt *events.Message) {
 	} else {
 		logrus.Debugf("No PollCreationMessage: MessageID=%s, Type=%s", evt.Info.ID, evt.Info.Type)
 	}
+	if poll := pollCreation(evt.Message); poll != nil {
+		rememberPoll(evt.Info.ID, evt.Info.Chat, evt.Info.Sender, evt.Info.IsFromMe, poll)
+	}
 
 	message := ExtractMessageText(evt)
 	RecordMessage(evt.Info.ID, evt.Info.Sender.String(), message)
+	if _, err := utils.RecordChatHistory([]utils.ChatHistoryMessage{chatHistoryMessage(evt)}); err != nil {
+		logrus.Warnf("Failed to store message %s in chat history: %v", evt.Info.ID, err)
+	}
+	if reaction := evt.Message.GetReactionMessage(); reaction != nil {
+		RecordReaction(evt.Info.Chat, evt.Info.Sender, reaction, evt.Info.Timestamp)
+	}
+	if evt.Message.GetPollUpdateMessage() != nil {
+		recordPollVote(ctx, evt)
+	}
+	rememberMedia(evt.Info.Chat, evt.Info.ID, evt.Message)
+
+	rememberAdReferral(evt)
 
 	handleImageMessage(ctx, evt)
 	handleAutoReply(evt)
 	handleWebhookForward(ctx, evt)
+	handleAutoMarkRead(evt)
+}
+
+func handleAutoMarkRead(evt *events.Message) {
+	if !config.WhatsappAutoMarkRead || evt.Info.IsFromMe || evt.Info.IsIncomingBroadcast() ||
+		utils.MatchesJIDPattern(evt.Info.Chat.String(), config.WhatsappAutoMarkReadExclude) {
+		return
+	}
+
+	if err := GetWaCli().MarkRead([]types.MessageID{evt.Info.ID}, time.Now(), evt.Info.Chat, evt.Info.Sender); err != nil {
+		logrus.Warnf("Failed to auto mark message %s as read: %v", evt.Info.ID, err)
+	}
 }
 
 func handleCallOffer(ctx context.Context, evt *events.CallOffer) {
@@ -538,19 +588,27 @@ func handleAutoReply(evt *events.Message) {
 	if config.WhatsappAutoReplyMessage != "" &&
 		!strings.Contains(evt.Info.Chat.String(), "@g.us") &&
 		!evt.Info.IsIncomingBroadcast() &&
-		evt.Message.GetExtendedTextMessage().GetText() != "" {
-		_, _ = cli.SendMessage(
-			context.Background(),
-			FormatJID(evt.Info.Sender.String()),
-			&waProto.Message{Conversation: proto.String(config.WhatsappAutoReplyMessage)},
-		)
+		evt.Message.GetExtendedTextMessage().GetText() != "" &&
+		CheckRecipient(evt.Info.Sender) == nil {
+		// SendMessage may wait for a send slot, so the reply does not hold up
+		// the event handler.
+		done := utils.DefaultTasks.Track("auto_reply", "replies to message "+evt.Info.ID)
+		go func(sender types.JID) {
+			defer done()
+			msg := &waProto.Message{Conversation: proto.String(config.WhatsappAutoReplyMessage)}
+			if _, err := SendMessage(context.Background(), FormatJID(sender.String()), msg); err != nil {
+				logrus.Warnf("Failed to send auto-reply to %s: %v", sender.String(), err)
+			}
+		}(evt.Info.Sender)
 	}
 }
 
 func handleWebhookForward(ctx context.Context, evt *events.Message) {
-	if len(config.WhatsappWebhook) > 0 &&
+	if (len(config.WhatsappWebhook) > 0 || len(config.WhatsappWebhookRoutes) > 0) &&
 		!strings.Contains(evt.Info.SourceString(), "broadcast") {
+		done := utils.DefaultTasks.Track("webhook", "forwards message "+evt.Info.ID)
 		go func(evt *events.Message) {
+			defer done()
 			if err := forwardToWebhook(ctx, evt); err != nil {
 				logrus.Error("Failed forward to webhook: ", err)
 			}
@@ -564,6 +622,25 @@ func handleReceipt(_ context.Context, evt *events.Receipt) {
 	} else if evt.Type == types.ReceiptTypeDelivered {
 		log.Infof("%s was delivered to %s at %s", evt.MessageIDs[0], evt.SourceString(), evt.Timestamp)
 	}
+
+	var status string
+	switch evt.Type {
+	case types.ReceiptTypeDelivered:
+		status = utils.ReceiptStatusDelivered
+	case types.ReceiptTypeRead:
+		status = utils.ReceiptStatusRead
+	case types.ReceiptTypePlayed:
+		status = utils.ReceiptStatusPlayed
+	default:
+		return
+	}
+
+	for _, id := range evt.MessageIDs {
+		if _, err := utils.UpdateMessageReceipt(id, status, evt.Timestamp); err != nil {
+			logrus.Errorf("Failed to record %s receipt for %s: %v", status, id, err)
+		}
+		notifyAck(id, status)
+	}
 }
 
 func handleHistorySync(_ context.Context, evt *events.HistorySync) {
@@ -571,7 +648,7 @@ func handleHistorySync(_ context.Context, evt *events.HistorySync) {
 	fileName := fmt.Sprintf("%s/history-%d-%s-%d-%s.json",
 		config.PathStorages,
 		startupTime,
-		cli.Store.ID.String(),
+		GetWaCli().Store.ID.String(),
 		id,
 		evt.Data.SyncType.String(),
 	)
@@ -591,6 +668,101 @@ func handleHistorySync(_ context.Context, evt *events.HistorySync) {
 	}
 
 	log.Infof("Wrote history sync to %s", fileName)
+
+	storeHistorySync(evt)
+}
+
+// storeHistorySync saves the backfilled messages into chat storage and, when
+// enabled, tells the webhooks how much history arrived.
+func storeHistorySync(evt *events.HistorySync) {
+	conversations := evt.Data.GetConversations()
+	var messages []utils.ChatHistoryMessage
+	var media []utils.MediaReference
+	cli := GetWaCli()
+	for _, conversation := range conversations {
+		chatJID, err := types.ParseJID(conversation.GetID())
+		if err != nil {
+			log.Warnf("Skipping history of invalid chat %s: %v", conversation.GetID(), err)
+			continue
+		}
+		for _, historyMsg := range conversation.GetMessages() {
+			parsed, err := cli.ParseWebMessage(chatJID, historyMsg.GetMessage())
+			if err != nil || parsed.Message == nil {
+				continue
+			}
+			unwrapViewOnce(parsed)
+			messages = append(messages, chatHistoryMessage(parsed))
+			if reference, ok := mediaReferenceOf(parsed.Info.Chat, parsed.Info.ID, parsed.Message); ok {
+				media = append(media, reference)
+			}
+		}
+	}
+	if err := utils.RecordMediaReferences(media); err != nil {
+		log.Errorf("Failed to store media references of history sync: %v", err)
+	}
+
+	stored, err := utils.RecordChatHistory(messages)
+	if err != nil {
+		log.Errorf("Failed to store history sync: %v", err)
+	}
+	log.Infof("History sync %s: %d conversations, %d messages, %d stored",
+		evt.Data.GetSyncType().String(), len(conversations), len(messages), stored)
+
+	if !config.WhatsappWebhookHistorySync || len(config.WhatsappWebhook) == 0 {
+		return
+	}
+	payload := map[string]interface{}{
+		"Type":          "history_sync",
+		"sync_type":     evt.Data.GetSyncType().String(),
+		"chunk_order":   evt.Data.GetChunkOrder(),
+		"progress":      evt.Data.GetProgress(),
+		"conversations": len(conversations),
+		"messages":      len(messages),
+		"stored":        stored,
+		"timestamp":     time.Now().Format(time.RFC3339),
+	}
+	go func() {
+		for _, url := range config.WhatsappWebhook {
+			if err := SubmitWebhook(payload, url); err != nil {
+				logrus.Errorf("Failed to send history sync webhook: %v", err)
+			}
+		}
+	}()
+}
+
+func chatHistoryMessage(evt *events.Message) utils.ChatHistoryMessage {
+	text := ExtractMessageText(evt)
+	message := utils.ChatHistoryMessage{
+		ChatJID:   evt.Info.Chat.String(),
+		MessageID: evt.Info.ID,
+		SenderJID: evt.Info.Sender.String(),
+		FromMe:    evt.Info.IsFromMe,
+		Timestamp: evt.Info.Timestamp,
+		Content:   text,
+		Type:      determineMessageType(evt, text),
+	}
+	message.MediaType, message.MediaPath = mediaReference(evt.Message)
+	return message
+}
+
+// mediaReference returns the mimetype and WhatsApp direct path of the media
+// attached to a message, or empty strings for messages without media.
+func mediaReference(msg *waProto.Message) (mimeType, directPath string) {
+	switch {
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetMimetype(), msg.GetImageMessage().GetDirectPath()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetMimetype(), msg.GetVideoMessage().GetDirectPath()
+	case msg.GetPtvMessage() != nil:
+		return msg.GetPtvMessage().GetMimetype(), msg.GetPtvMessage().GetDirectPath()
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage().GetMimetype(), msg.GetAudioMessage().GetDirectPath()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetMimetype(), msg.GetDocumentMessage().GetDirectPath()
+	case msg.GetStickerMessage() != nil:
+		return msg.GetStickerMessage().GetMimetype(), msg.GetStickerMessage().GetDirectPath()
+	}
+	return "", ""
 }
 
 func handleAppState(ctx context.Context, evt *events.AppState) {
@@ -628,6 +800,14 @@ func buildEventReaction(evt *events.Message) evtReaction {
 	return waReaction
 }
 
+// RecordReaction stores a reaction against its target message in chat storage.
+func RecordReaction(chat, sender types.JID, reaction *waProto.ReactionMessage, timestamp time.Time) {
+	targetID := reaction.GetKey().GetID()
+	if err := utils.RecordReaction(chat.String(), targetID, sender.ToNonAD().String(), reaction.GetText(), timestamp); err != nil {
+		logrus.Warnf("Failed to store reaction to %s: %v", targetID, err)
+	}
+}
+
 func buildForwarded(evt *events.Message) bool {
 	if extendedText := evt.Message.GetExtendedTextMessage(); extendedText != nil {
 		return extendedText.ContextInfo.GetIsForwarded()
@@ -0,0 +1,142 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Label is a WhatsApp Business chat label.
+type Label struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color int32  `json:"color"`
+}
+
+// labels are only known from app state sync events, so they are kept in memory,
+// loaded once with a full sync of the regular patch and kept current from
+// LabelEdit events after that. An account without labels stays loaded too.
+var (
+	labelCache  = map[string]Label{}
+	labelsReady bool
+	labelMutex  sync.RWMutex
+)
+
+func handleLabelEdit(evt *events.LabelEdit) {
+	labelMutex.Lock()
+	defer labelMutex.Unlock()
+
+	if evt.Action.GetDeleted() {
+		delete(labelCache, evt.LabelID)
+		return
+	}
+	labelCache[evt.LabelID] = Label{
+		ID:    evt.LabelID,
+		Name:  evt.Action.GetName(),
+		Color: evt.Action.GetColor(),
+	}
+}
+
+func handleLabelAssociationChat(evt *events.LabelAssociationChat) {
+	if evt.FromFullSync {
+		return
+	}
+	urls := utils.WebhookURLsForChat(evt.JID.String())
+	if len(urls) == 0 {
+		return
+	}
+
+	payload := map[string]interface{}{
+		"Type":      "label_association",
+		"chat":      evt.JID.String(),
+		"label_id":  evt.LabelID,
+		"labeled":   evt.Action.GetLabeled(),
+		"timestamp": evt.Timestamp.Format(time.RFC3339),
+	}
+	go func() {
+		for _, url := range urls {
+			if err := SubmitWebhook(payload, url); err != nil {
+				logrus.Errorf("Failed to send label webhook: %v", err)
+			}
+		}
+	}()
+}
+
+// Labels returns the labels of the account ordered by ID.
+func Labels(ctx context.Context) ([]Label, error) {
+	labelMutex.RLock()
+	ready := labelsReady
+	labelMutex.RUnlock()
+	if !ready {
+		cli := GetWaCli()
+		if cli == nil {
+			return nil, ErrClientNotInitialized
+		}
+		// A full sync replays every label as a LabelEdit event.
+		if err := cli.FetchAppState(ctx, appstate.WAPatchRegular, true, false); err != nil {
+			return nil, fmt.Errorf("failed to sync labels: %w", err)
+		}
+		labelMutex.Lock()
+		labelsReady = true
+		labelMutex.Unlock()
+	}
+
+	labelMutex.RLock()
+	defer labelMutex.RUnlock()
+	labels := make([]Label, 0, len(labelCache))
+	for _, label := range labelCache {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		a, _ := strconv.Atoi(labels[i].ID)
+		b, _ := strconv.Atoi(labels[j].ID)
+		return a < b
+	})
+	return labels, nil
+}
+
+// CreateLabel adds a label using the next free numeric ID, as the phone does.
+func CreateLabel(ctx context.Context, name string, color int32) (Label, error) {
+	labels, err := Labels(ctx)
+	if err != nil {
+		return Label{}, err
+	}
+	nextID := 1
+	for _, label := range labels {
+		if id, err := strconv.Atoi(label.ID); err == nil && id >= nextID {
+			nextID = id + 1
+		}
+	}
+
+	label := Label{ID: strconv.Itoa(nextID), Name: name, Color: color}
+	cli := GetWaCli()
+	if cli == nil {
+		return Label{}, ErrClientNotInitialized
+	}
+	if err := cli.SendAppState(ctx, appstate.BuildLabelEdit(label.ID, name, color, false)); err != nil {
+		return Label{}, err
+	}
+
+	labelMutex.Lock()
+	labelCache[label.ID] = label
+	labelMutex.Unlock()
+	return label, nil
+}
+
+// LabelChat adds or removes a label on a chat.
+func LabelChat(ctx context.Context, chat types.JID, labelID string, labeled bool) error {
+	cli := GetWaCli()
+	if cli == nil {
+		return ErrClientNotInitialized
+	}
+	return cli.SendAppState(ctx, appstate.BuildLabelChat(chat, labelID, labeled))
+}
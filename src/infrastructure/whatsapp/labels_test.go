@@ -0,0 +1,40 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	"go.mau.fi/whatsmeow/proto/waSyncAction"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLabelsFromEdits(t *testing.T) {
+	labelMutex.Lock()
+	labelCache, labelsReady = map[string]Label{}, true
+	labelMutex.Unlock()
+	t.Cleanup(func() {
+		labelMutex.Lock()
+		labelCache, labelsReady = map[string]Label{}, false
+		labelMutex.Unlock()
+	})
+
+	// A loaded account without labels is not synced again.
+	labels, err := Labels(context.Background())
+	if err != nil || len(labels) != 0 {
+		t.Fatalf("Labels() = %v, %v; want no labels without a sync", labels, err)
+	}
+
+	handleLabelEdit(&events.LabelEdit{LabelID: "10", Action: &waSyncAction.LabelEditAction{Name: proto.String("Paid"), Color: proto.Int32(3)}})
+	handleLabelEdit(&events.LabelEdit{LabelID: "2", Action: &waSyncAction.LabelEditAction{Name: proto.String("New")}})
+	handleLabelEdit(&events.LabelEdit{LabelID: "5", Action: &waSyncAction.LabelEditAction{Name: proto.String("Old")}})
+	handleLabelEdit(&events.LabelEdit{LabelID: "5", Action: &waSyncAction.LabelEditAction{Deleted: proto.Bool(true)}})
+
+	labels, err = Labels(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 2 || labels[0].ID != "2" || labels[1] != (Label{ID: "10", Name: "Paid", Color: 3}) {
+		t.Errorf("Labels() = %+v, want labels 2 and 10 in ID order", labels)
+	}
+}
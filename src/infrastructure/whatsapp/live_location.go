@@ -0,0 +1,92 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// MaxLiveLocationDuration is the longest live location WhatsApp clients offer.
+const MaxLiveLocationDuration = 8 * time.Hour
+
+// liveLocationStopTimeout bounds sending the stop update, which may run while
+// the process is shutting down.
+const liveLocationStopTimeout = 10 * time.Second
+
+// LiveLocation describes a position shared live from the account.
+type LiveLocation struct {
+	Latitude       float64
+	Longitude      float64
+	AccuracyMeters uint32
+	Caption        string
+}
+
+func (l LiveLocation) message(sequence int64, offset time.Duration) *waProto.LiveLocationMessage {
+	msg := &waProto.LiveLocationMessage{
+		DegreesLatitude:  proto.Float64(l.Latitude),
+		DegreesLongitude: proto.Float64(l.Longitude),
+		SequenceNumber:   proto.Int64(sequence),
+		TimeOffset:       proto.Uint32(uint32(offset / time.Second)),
+	}
+	if l.AccuracyMeters > 0 {
+		msg.AccuracyInMeters = proto.Uint32(l.AccuracyMeters)
+	}
+	if l.Caption != "" {
+		msg.Caption = proto.String(l.Caption)
+	}
+	return msg
+}
+
+// StartLiveLocation shares location live with jid and stops the share once
+// duration elapses. The wait runs as a cancellable task: cancelling it, or the
+// shutdown of the process, stops the share early. It returns the sent message
+// and the ID of that task.
+func StartLiveLocation(ctx context.Context, jid types.JID, location LiveLocation, duration time.Duration) (whatsmeow.SendResponse, string, error) {
+	if duration <= 0 || duration > MaxLiveLocationDuration {
+		return whatsmeow.SendResponse{}, "", fmt.Errorf("live location duration must be between 1 second and %s", MaxLiveLocationDuration)
+	}
+
+	resp, err := SendMessage(ctx, jid, &waProto.Message{LiveLocationMessage: location.message(1, 0)})
+	if err != nil {
+		return resp, "", err
+	}
+
+	description := fmt.Sprintf("live location %s to %s for %s", resp.ID, jid.String(), duration)
+	taskID := utils.DefaultTasks.Go("live_location", description, func(ctx context.Context) {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		stopLiveLocation(jid, resp, location)
+	})
+	return resp, taskID, nil
+}
+
+// stopLiveLocation sends the final update of a live location. The end-to-end
+// protocol has no stop message, so the stop is an edit of the original share
+// carrying its last sequence number and the time it was shared for.
+func stopLiveLocation(jid types.JID, started whatsmeow.SendResponse, location LiveLocation) {
+	ctx, cancel := context.WithTimeout(context.Background(), liveLocationStopTimeout)
+	defer cancel()
+
+	cli, err := ConnectedClient()
+	if err != nil {
+		logrus.Warnf("Cannot stop live location %s to %s: %v", started.ID, jid.String(), err)
+		return
+	}
+	final := &waProto.Message{LiveLocationMessage: location.message(2, time.Since(started.Timestamp))}
+	if _, err := SendMessage(ctx, jid, cli.BuildEdit(jid, started.ID, final)); err != nil {
+		logrus.Warnf("Failed to stop live location %s to %s: %v", started.ID, jid.String(), err)
+		return
+	}
+	logrus.Infof("Live location %s to %s stopped", started.ID, jid.String())
+}
@@ -0,0 +1,30 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestStartLiveLocationDuration(t *testing.T) {
+	jid := types.NewJID("6281111111111", types.DefaultUserServer)
+	location := LiveLocation{Latitude: -6.2, Longitude: 106.8}
+	for _, duration := range []time.Duration{0, -time.Minute, MaxLiveLocationDuration + time.Second} {
+		if _, _, err := StartLiveLocation(context.Background(), jid, location, duration); err == nil {
+			t.Errorf("StartLiveLocation(%s) succeeded, want a duration error", duration)
+		}
+	}
+}
+
+func TestLiveLocationMessage(t *testing.T) {
+	location := LiveLocation{Latitude: -6.2, Longitude: 106.8, Caption: "On my way"}
+	msg := location.message(2, 90*time.Second)
+	if msg.GetSequenceNumber() != 2 || msg.GetTimeOffset() != 90 || msg.GetCaption() != "On my way" {
+		t.Errorf("message() = %v, want sequence 2, offset 90 and the caption", msg)
+	}
+	if msg.AccuracyInMeters != nil {
+		t.Error("accuracy set although none was given")
+	}
+}
@@ -0,0 +1,147 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrMediaNotStored is returned by DownloadStoredMedia when the message is not
+// stored or carries no media.
+var ErrMediaNotStored = errors.New("message has no stored media")
+
+// StoredMedia is the decrypted media of a stored message.
+type StoredMedia struct {
+	Data     []byte
+	MimeType string
+	FileName string // only documents carry their original file name
+}
+
+// storableMedia strips a message down to its media content, dropping the inline
+// thumbnail and context info that are not needed to download it again.
+func storableMedia(msg *waProto.Message) *waProto.Message {
+	switch {
+	case msg.GetImageMessage() != nil:
+		media := proto.Clone(msg.GetImageMessage()).(*waProto.ImageMessage)
+		media.JPEGThumbnail, media.ContextInfo = nil, nil
+		return &waProto.Message{ImageMessage: media}
+	case msg.GetVideoMessage() != nil:
+		media := proto.Clone(msg.GetVideoMessage()).(*waProto.VideoMessage)
+		media.JPEGThumbnail, media.ContextInfo = nil, nil
+		return &waProto.Message{VideoMessage: media}
+	case msg.GetPtvMessage() != nil:
+		media := proto.Clone(msg.GetPtvMessage()).(*waProto.VideoMessage)
+		media.JPEGThumbnail, media.ContextInfo = nil, nil
+		return &waProto.Message{PtvMessage: media}
+	case msg.GetAudioMessage() != nil:
+		media := proto.Clone(msg.GetAudioMessage()).(*waProto.AudioMessage)
+		media.ContextInfo = nil
+		return &waProto.Message{AudioMessage: media}
+	case msg.GetDocumentMessage() != nil:
+		media := proto.Clone(msg.GetDocumentMessage()).(*waProto.DocumentMessage)
+		media.JPEGThumbnail, media.ContextInfo = nil, nil
+		return &waProto.Message{DocumentMessage: media}
+	case msg.GetStickerMessage() != nil:
+		media := proto.Clone(msg.GetStickerMessage()).(*waProto.StickerMessage)
+		media.PngThumbnail, media.ContextInfo = nil, nil
+		return &waProto.Message{StickerMessage: media}
+	}
+	return nil
+}
+
+// mediaReferenceOf returns the reference needed to download the media of a
+// message later, or false when the message has no media.
+func mediaReferenceOf(chat types.JID, id types.MessageID, msg *waProto.Message) (utils.MediaReference, bool) {
+	media := storableMedia(msg)
+	if media == nil || id == "" {
+		return utils.MediaReference{}, false
+	}
+	encoded, err := proto.Marshal(media)
+	if err != nil {
+		logrus.Warnf("Failed to encode media reference of %s: %v", id, err)
+		return utils.MediaReference{}, false
+	}
+	return utils.MediaReference{
+		ChatJID:   chat.String(),
+		MessageID: id,
+		Message:   base64.StdEncoding.EncodeToString(encoded),
+	}, true
+}
+
+// rememberMedia stores the media reference of a message, if it has media.
+func rememberMedia(chat types.JID, id types.MessageID, msg *waProto.Message) {
+	reference, ok := mediaReferenceOf(chat, id, msg)
+	if !ok {
+		return
+	}
+	if err := utils.RecordMediaReferences([]utils.MediaReference{reference}); err != nil {
+		logrus.Warnf("Failed to store media reference of %s: %v", id, err)
+	}
+}
+
+// decodeMediaReference returns the media message kept in a media reference.
+func decodeMediaReference(reference utils.MediaReference) (*waProto.Message, error) {
+	encoded, err := base64.StdEncoding.DecodeString(reference.Message)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt media reference: %w", err)
+	}
+	var msg waProto.Message
+	if err := proto.Unmarshal(encoded, &msg); err != nil {
+		return nil, fmt.Errorf("corrupt media reference: %w", err)
+	}
+	return &msg, nil
+}
+
+// DownloadStoredMedia downloads and decrypts the media of a stored message.
+func DownloadStoredMedia(ctx context.Context, chat types.JID, id types.MessageID) (StoredMedia, error) {
+	reference, err := utils.FindMediaReference(chat.String(), id)
+	if errors.Is(err, utils.ErrRecordNotFound) {
+		return StoredMedia{}, ErrMediaNotStored
+	}
+	if err != nil {
+		return StoredMedia{}, err
+	}
+
+	msg, err := decodeMediaReference(reference)
+	if err != nil {
+		return StoredMedia{}, err
+	}
+
+	var downloadable whatsmeow.DownloadableMessage
+	var media StoredMedia
+	switch {
+	case msg.GetImageMessage() != nil:
+		downloadable, media.MimeType = msg.GetImageMessage(), msg.GetImageMessage().GetMimetype()
+	case msg.GetVideoMessage() != nil:
+		downloadable, media.MimeType = msg.GetVideoMessage(), msg.GetVideoMessage().GetMimetype()
+	case msg.GetPtvMessage() != nil:
+		downloadable, media.MimeType = msg.GetPtvMessage(), msg.GetPtvMessage().GetMimetype()
+	case msg.GetAudioMessage() != nil:
+		downloadable, media.MimeType = msg.GetAudioMessage(), msg.GetAudioMessage().GetMimetype()
+	case msg.GetDocumentMessage() != nil:
+		downloadable, media.MimeType = msg.GetDocumentMessage(), msg.GetDocumentMessage().GetMimetype()
+		media.FileName = msg.GetDocumentMessage().GetFileName()
+	case msg.GetStickerMessage() != nil:
+		downloadable, media.MimeType = msg.GetStickerMessage(), msg.GetStickerMessage().GetMimetype()
+	default:
+		return StoredMedia{}, ErrMediaNotStored
+	}
+
+	cli := GetWaCli()
+	if cli == nil {
+		return StoredMedia{}, ErrClientNotInitialized
+	}
+	media.Data, err = cli.Download(ctx, downloadable)
+	if err != nil {
+		return StoredMedia{}, err
+	}
+	return media, nil
+}
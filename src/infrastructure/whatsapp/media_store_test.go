@@ -0,0 +1,49 @@
+package whatsapp
+
+import (
+	"encoding/base64"
+	"testing"
+
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMediaReferenceOf(t *testing.T) {
+	chat := types.NewJID("6281234567890", types.DefaultUserServer)
+	msg := &waProto.Message{ImageMessage: &waProto.ImageMessage{
+		Mimetype:      proto.String("image/jpeg"),
+		DirectPath:    proto.String("/v/t62.7118-24/abc"),
+		MediaKey:      []byte("key"),
+		JPEGThumbnail: []byte("thumbnail"),
+		Caption:       proto.String("hello"),
+		ContextInfo:   &waProto.ContextInfo{StanzaID: proto.String("quoted")},
+	}}
+
+	reference, ok := mediaReferenceOf(chat, "3EB0IMAGE", msg)
+	if !ok {
+		t.Fatal("expected a media reference for an image")
+	}
+	encoded, err := base64.StdEncoding.DecodeString(reference.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored waProto.Message
+	if err := proto.Unmarshal(encoded, &stored); err != nil {
+		t.Fatal(err)
+	}
+	image := stored.GetImageMessage()
+	if image.GetDirectPath() != "/v/t62.7118-24/abc" || string(image.GetMediaKey()) != "key" {
+		t.Fatalf("download fields were not kept: %v", image)
+	}
+	if image.JPEGThumbnail != nil || image.ContextInfo != nil {
+		t.Fatalf("thumbnail and context info should be dropped: %v", image)
+	}
+	if msg.GetImageMessage().JPEGThumbnail == nil {
+		t.Fatal("the original message must not be modified")
+	}
+
+	if _, ok := mediaReferenceOf(chat, "3EB0TEXT", &waProto.Message{Conversation: proto.String("hi")}); ok {
+		t.Fatal("text messages have no media reference")
+	}
+}
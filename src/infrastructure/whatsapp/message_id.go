@@ -0,0 +1,92 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	minMessageIDLength = 16
+	maxMessageIDLength = 64
+	// messageIDReuseWindow is how long a caller-supplied message ID is refused
+	// after a successful send.
+	messageIDReuseWindow = 24 * time.Hour
+)
+
+// ErrDuplicateMessageID is returned by SendMessage when a caller-supplied
+// message ID was already used within messageIDReuseWindow.
+var ErrDuplicateMessageID = errors.New("message_id was already used")
+
+type messageIDKey struct{}
+
+// WithMessageID makes SendMessage send the message under id instead of a
+// generated one, so callers can match webhooks and receipts to their own
+// records. Check the id with ValidateMessageID first.
+func WithMessageID(ctx context.Context, id types.MessageID) context.Context {
+	return context.WithValue(ctx, messageIDKey{}, id)
+}
+
+// WithRequestedMessageID checks a caller-supplied message ID and applies it with
+// WithMessageID. An empty id leaves ctx unchanged, so a generated ID is used.
+func WithRequestedMessageID(ctx context.Context, id types.MessageID) (context.Context, error) {
+	if id == "" {
+		return ctx, nil
+	}
+	if err := ValidateMessageID(id); err != nil {
+		return ctx, err
+	}
+	return WithMessageID(ctx, id), nil
+}
+
+// ValidateMessageID checks that id looks like the IDs WhatsApp clients
+// generate: 16 to 64 uppercase hexadecimal characters.
+func ValidateMessageID(id types.MessageID) error {
+	if len(id) < minMessageIDLength || len(id) > maxMessageIDLength {
+		return errors.New(utils.T("message_id_length_invalid", minMessageIDLength, maxMessageIDLength))
+	}
+	for _, r := range id {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'F') {
+			return errors.New(utils.T("message_id_charset_invalid", r))
+		}
+	}
+	return nil
+}
+
+// usedMessageIDs remembers caller-supplied message IDs, so the same ID is not
+// sent twice, which would make recipients drop or overwrite the message.
+var usedMessageIDs = &messageIDRegistry{used: map[types.MessageID]time.Time{}}
+
+type messageIDRegistry struct {
+	mu   sync.Mutex
+	used map[types.MessageID]time.Time
+}
+
+// reserve claims id, or reports false when it was used within the window or
+// is being sent right now.
+func (r *messageIDRegistry) reserve(id types.MessageID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for used, at := range r.used {
+		if now.Sub(at) > messageIDReuseWindow {
+			delete(r.used, used)
+		}
+	}
+	if _, ok := r.used[id]; ok {
+		return false
+	}
+	r.used[id] = now
+	return true
+}
+
+// release frees an id whose send failed, so the caller can retry with it.
+func (r *messageIDRegistry) release(id types.MessageID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.used, id)
+}
@@ -0,0 +1,60 @@
+package whatsapp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestValidateMessageID(t *testing.T) {
+	for _, id := range []string{"3EB0C0FFEE0123456789AB", strings.Repeat("A", 64)} {
+		if err := ValidateMessageID(id); err != nil {
+			t.Errorf("ValidateMessageID(%q) = %v", id, err)
+		}
+	}
+	for _, id := range []string{"", "3EB0C0FFEE", strings.Repeat("A", 65), "3eb0c0ffee0123456789ab", "ORDER-1234-5678-90AB"} {
+		if err := ValidateMessageID(id); err == nil {
+			t.Errorf("ValidateMessageID(%q) accepted an invalid id", id)
+		}
+	}
+}
+
+func TestWithRequestedMessageID(t *testing.T) {
+	ctx := context.Background()
+	if got, err := WithRequestedMessageID(ctx, ""); err != nil || got != ctx {
+		t.Errorf("WithRequestedMessageID(\"\") = %v, %v; want ctx unchanged", got, err)
+	}
+	if _, err := WithRequestedMessageID(ctx, "order-42"); err == nil {
+		t.Error("WithRequestedMessageID() accepted an invalid id")
+	}
+	got, err := WithRequestedMessageID(ctx, "3EB0C0FFEE0123456789AB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := got.Value(messageIDKey{}).(types.MessageID); id != "3EB0C0FFEE0123456789AB" {
+		t.Errorf("message id in ctx = %q", id)
+	}
+}
+
+func TestMessageIDRegistry(t *testing.T) {
+	registry := &messageIDRegistry{used: map[types.MessageID]time.Time{}}
+	if !registry.reserve("3EB0C0FFEE0123456789AB") {
+		t.Fatal("a fresh id was refused")
+	}
+	if registry.reserve("3EB0C0FFEE0123456789AB") {
+		t.Error("a used id was accepted again")
+	}
+
+	registry.release("3EB0C0FFEE0123456789AB")
+	if !registry.reserve("3EB0C0FFEE0123456789AB") {
+		t.Error("a released id was refused")
+	}
+
+	registry.used["3EB0C0FFEE0123456789AB"] = time.Now().Add(-messageIDReuseWindow - time.Minute)
+	if !registry.reserve("3EB0C0FFEE0123456789AB") {
+		t.Error("an id used before the window was refused")
+	}
+}
@@ -0,0 +1,93 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// subscribeAdminNewsletters subscribes to the reaction and view count updates of
+// every channel this account owns or administers, so they can be forwarded to
+// the webhooks. Nothing is subscribed when no webhook is configured.
+func subscribeAdminNewsletters(ctx context.Context) {
+	if len(config.WhatsappWebhook) == 0 && len(config.WhatsappWebhookRoutes) == 0 {
+		return
+	}
+	cli := GetWaCli()
+	if cli == nil {
+		return
+	}
+	newsletters, err := cli.GetSubscribedNewsletters()
+	if err != nil {
+		logrus.Warnf("Failed to list channels for live updates: %v", err)
+		return
+	}
+	for _, newsletter := range newsletters {
+		if newsletter.ViewerMeta == nil {
+			continue
+		}
+		if role := newsletter.ViewerMeta.Role; role == types.NewsletterRoleOwner || role == types.NewsletterRoleAdmin {
+			subscribeNewsletterLiveUpdates(ctx, newsletter.ID)
+		}
+	}
+}
+
+// subscribeNewsletterLiveUpdates subscribes to the live updates of a channel and
+// renews the subscription shortly before WhatsApp ends it.
+func subscribeNewsletterLiveUpdates(ctx context.Context, jid types.JID) {
+	cli := GetWaCli()
+	if cli == nil {
+		return
+	}
+	duration, err := cli.NewsletterSubscribeLiveUpdates(ctx, jid)
+	if err != nil {
+		logrus.Warnf("Failed to subscribe to live updates of channel %s: %v", jid.String(), err)
+		return
+	}
+	if duration <= 0 {
+		return
+	}
+	renewIn := duration - duration/10
+	utils.DefaultScheduler.Schedule("newsletter-live:"+jid.String(), renewIn, func() {
+		if cli := GetWaCli(); cli != nil && cli.IsConnected() {
+			subscribeNewsletterLiveUpdates(context.Background(), jid)
+		}
+	})
+}
+
+// handleNewsletterLiveUpdate forwards the new reaction and view counts of
+// channel messages as a "newsletter_reactions" webhook event.
+func handleNewsletterLiveUpdate(evt *events.NewsletterLiveUpdate) {
+	urls := utils.WebhookURLsForChat(evt.JID.String())
+	if len(urls) == 0 || len(evt.Messages) == 0 {
+		return
+	}
+
+	messages := make([]map[string]interface{}, 0, len(evt.Messages))
+	for _, message := range evt.Messages {
+		messages = append(messages, map[string]interface{}{
+			"server_id":  message.MessageServerID,
+			"message_id": message.MessageID,
+			"views":      message.ViewsCount,
+			"reactions":  message.ReactionCounts,
+		})
+	}
+	payload := map[string]interface{}{
+		"Type":       "newsletter_reactions",
+		"newsletter": evt.JID.String(),
+		"messages":   messages,
+		"timestamp":  evt.Time.Format(time.RFC3339),
+	}
+	go func() {
+		for _, url := range urls {
+			if err := SubmitWebhook(payload, url); err != nil {
+				logrus.Errorf("Failed to send channel reaction webhook: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,43 @@
+package whatsapp
+
+import (
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// forwardSentMessage posts a sent message to config.WhatsappWebhookOutbound,
+// so the webhooks log both directions. It runs in the background and does not
+// hold up the send.
+func forwardSentMessage(sent utils.ChatHistoryMessage) {
+	if len(config.WhatsappWebhookOutbound) == 0 {
+		return
+	}
+	payload := sentMessagePayload(sent)
+	done := utils.DefaultTasks.Track("webhook", "forwards sent message "+sent.MessageID)
+	go func() {
+		defer done()
+		for _, url := range config.WhatsappWebhookOutbound {
+			if err := SubmitWebhook(payload, url); err != nil {
+				logrus.Errorf("Failed to send outbound message webhook for %s: %v", sent.MessageID, err)
+			}
+		}
+	}()
+}
+
+func sentMessagePayload(sent utils.ChatHistoryMessage) map[string]interface{} {
+	payload := map[string]interface{}{
+		"Type":         "message_sent",
+		"recipient":    sent.ChatJID,
+		"message_id":   sent.MessageID,
+		"message_type": sent.Type,
+		"content":      sent.Content,
+		"timestamp":    sent.Timestamp.Format(time.RFC3339),
+	}
+	if sent.MediaType != "" {
+		payload["media_type"] = sent.MediaType
+	}
+	return payload
+}
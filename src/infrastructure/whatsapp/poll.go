@@ -0,0 +1,187 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// PollDetails is what is needed to vote in a poll: where it was posted, who
+// created it and its options in their original order.
+type PollDetails struct {
+	Chat            types.JID
+	Sender          types.JID
+	FromMe          bool
+	Options         []string
+	SelectableCount int // 0 means any number of options
+}
+
+// ErrPollNotFound is returned by BuildPollVote when the poll was never seen,
+// so its options and creator are unknown.
+var ErrPollNotFound = errors.New("poll not found")
+
+// pollCacheTTL bounds how long a poll stays in memory. Older polls are still
+// found through the poll storage.
+const pollCacheTTL = 24 * time.Hour
+
+// polls keeps recently sent or received polls, so votes and tallies of active
+// polls do not read the poll storage.
+var polls = &pollCache{entries: map[types.MessageID]cachedPoll{}}
+
+type cachedPoll struct {
+	details PollDetails
+	expires time.Time
+}
+
+type pollCache struct {
+	mu      sync.Mutex
+	entries map[types.MessageID]cachedPoll
+}
+
+func (c *pollCache) get(id types.MessageID) (PollDetails, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[id]
+	if !ok || time.Now().After(entry.expires) {
+		return PollDetails{}, false
+	}
+	return entry.details, true
+}
+
+func (c *pollCache) put(id types.MessageID, details PollDetails) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[id] = cachedPoll{details: details, expires: now.Add(pollCacheTTL)}
+}
+
+// pollCreation returns the poll of a message, whichever version carries it.
+func pollCreation(msg *waProto.Message) *waProto.PollCreationMessage {
+	switch {
+	case msg.GetPollCreationMessage() != nil:
+		return msg.GetPollCreationMessage()
+	case msg.GetPollCreationMessageV2() != nil:
+		return msg.GetPollCreationMessageV2()
+	case msg.GetPollCreationMessageV3() != nil:
+		return msg.GetPollCreationMessageV3()
+	}
+	return nil
+}
+
+// rememberPoll stores a sent or received poll so the bot can vote in it later.
+func rememberPoll(id types.MessageID, chat, sender types.JID, fromMe bool, poll *waProto.PollCreationMessage) {
+	details := PollDetails{
+		Chat:            chat,
+		Sender:          sender,
+		FromMe:          fromMe,
+		SelectableCount: int(poll.GetSelectableOptionsCount()),
+	}
+	for _, option := range poll.GetOptions() {
+		details.Options = append(details.Options, option.GetOptionName())
+	}
+	polls.put(id, details)
+	err := utils.RecordPoll(utils.StoredPoll{
+		ChatJID:         chat.ToNonAD().String(),
+		PollID:          id,
+		SenderJID:       sender.ToNonAD().String(),
+		FromMe:          fromMe,
+		SelectableCount: details.SelectableCount,
+		Options:         details.Options,
+	})
+	if err != nil {
+		logrus.Warnf("Failed to store poll %s: %v", id, err)
+	}
+}
+
+// FindPoll returns a previously sent or received poll, reading it from the poll
+// storage when it is no longer in memory, for instance after a restart.
+func FindPoll(id types.MessageID) (PollDetails, bool) {
+	if details, ok := polls.get(id); ok {
+		return details, true
+	}
+	stored, err := utils.FindStoredPoll("", id)
+	if err != nil {
+		if !errors.Is(err, utils.ErrRecordNotFound) {
+			logrus.Warnf("Failed to read poll %s: %v", id, err)
+		}
+		return PollDetails{}, false
+	}
+	chat, err := types.ParseJID(stored.ChatJID)
+	if err != nil {
+		return PollDetails{}, false
+	}
+	sender, err := types.ParseJID(stored.SenderJID)
+	if err != nil {
+		return PollDetails{}, false
+	}
+	details := PollDetails{
+		Chat:            chat,
+		Sender:          sender,
+		FromMe:          stored.FromMe,
+		Options:         stored.Options,
+		SelectableCount: stored.SelectableCount,
+	}
+	polls.put(id, details)
+	return details, true
+}
+
+// PollSelection resolves the chosen options, given by text or by zero-based
+// index, against the poll and checks them against its selectable limit.
+func (p PollDetails) PollSelection(names []string, indexes []int) ([]string, error) {
+	var selected []string
+	for _, index := range indexes {
+		if index < 0 || index >= len(p.Options) {
+			return nil, errors.New(utils.T("poll_option_index_out_of_range", index, len(p.Options)))
+		}
+		names = append(names, p.Options[index])
+	}
+	for _, name := range names {
+		if !slices.Contains(p.Options, name) {
+			return nil, errors.New(utils.T("poll_option_unknown", name))
+		}
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.New(utils.T("poll_options_required"))
+	}
+	if p.SelectableCount > 0 && len(selected) > p.SelectableCount {
+		return nil, errors.New(utils.T("poll_too_many_options", len(selected), p.SelectableCount))
+	}
+	return selected, nil
+}
+
+// BuildPollVote encrypts a vote for the given options of a known poll.
+func BuildPollVote(ctx context.Context, id types.MessageID, options []string) (*waProto.Message, error) {
+	poll, ok := FindPoll(id)
+	if !ok {
+		return nil, ErrPollNotFound
+	}
+	cli := GetWaCli()
+	if cli == nil {
+		return nil, ErrClientNotInitialized
+	}
+	info := &types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:     poll.Chat,
+			Sender:   poll.Sender,
+			IsFromMe: poll.FromMe,
+			IsGroup:  poll.Chat.Server == types.GroupServer,
+		},
+		ID: id,
+	}
+	return cli.BuildPollVote(ctx, info, options)
+}
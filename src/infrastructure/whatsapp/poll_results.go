@@ -0,0 +1,124 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// PollOptionResult is the tally of one option of a poll.
+type PollOptionResult struct {
+	Name   string   `json:"name"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+// PollResults is the tally of a poll from the votes kept in chat storage.
+type PollResults struct {
+	PollID      string             `json:"poll_id"`
+	ChatJID     string             `json:"chat_jid"`
+	Known       bool               `json:"known"` // false when the options of the poll are unknown
+	TotalVoters int                `json:"total_voters"`
+	Options     []PollOptionResult `json:"options"`
+}
+
+// recordPollVote decrypts a vote and stores it with the poll it belongs to.
+func recordPollVote(ctx context.Context, evt *events.Message) {
+	update := evt.Message.GetPollUpdateMessage()
+	cli := GetWaCli()
+	if update == nil || cli == nil {
+		return
+	}
+	vote, err := cli.DecryptPollVote(ctx, evt)
+	if err != nil {
+		logrus.Warnf("Failed to decrypt poll vote %s: %v", evt.Info.ID, err)
+		return
+	}
+	hashes := make([]string, 0, len(vote.GetSelectedOptions()))
+	for _, hash := range vote.GetSelectedOptions() {
+		hashes = append(hashes, hex.EncodeToString(hash))
+	}
+	pollID := update.GetPollCreationMessageKey().GetID()
+	if err := utils.RecordPollVote(evt.Info.Chat.String(), pollID, evt.Info.Sender.ToNonAD().String(), hashes, evt.Info.Timestamp); err != nil {
+		logrus.Warnf("Failed to store vote in poll %s: %v", pollID, err)
+	}
+}
+
+// RecordOwnPollVote stores a vote sent through the API, which never comes
+// back as an event.
+func RecordOwnPollVote(chat types.JID, pollID types.MessageID, options []string) {
+	cli := GetWaCli()
+	if cli == nil || cli.Store.ID == nil {
+		return
+	}
+	hashes := make([]string, 0, len(options))
+	for _, hash := range whatsmeow.HashPollOptions(options) {
+		hashes = append(hashes, hex.EncodeToString(hash))
+	}
+	if err := utils.RecordPollVote(chat.String(), pollID, cli.Store.ID.ToNonAD().String(), hashes, time.Now()); err != nil {
+		logrus.Warnf("Failed to store vote in poll %s: %v", pollID, err)
+	}
+}
+
+// ErrPollResultsDisabled is returned by GetPollResults when chat storage, which
+// holds the votes, is disabled.
+var ErrPollResultsDisabled = pkgError.FeatureDisabledError("poll results need chat storage, which is disabled")
+
+// GetPollResults tallies the current votes of a poll per option. When the
+// options of the poll are known, they are listed in the order of the poll,
+// including those without votes; otherwise only options with votes are listed,
+// named after their hash.
+func GetPollResults(chat types.JID, pollID types.MessageID) (PollResults, error) {
+	if !config.WhatsappChatStorage {
+		return PollResults{}, ErrPollResultsDisabled
+	}
+	votes, err := utils.PollVotes(chat.String(), pollID)
+	if err != nil {
+		return PollResults{}, err
+	}
+	poll, known := FindPoll(pollID)
+	options := poll.Options
+	if !known || poll.Chat.ToNonAD() != chat.ToNonAD() {
+		stored, err := utils.FindStoredPoll(chat.ToNonAD().String(), pollID)
+		if errors.Is(err, utils.ErrRecordNotFound) {
+			return tallyPoll(chat, pollID, nil, false, votes), nil
+		} else if err != nil {
+			return PollResults{}, err
+		}
+		options = stored.Options
+	}
+	return tallyPoll(chat, pollID, options, true, votes), nil
+}
+
+func tallyPoll(chat types.JID, pollID types.MessageID, options []string, known bool, votes []utils.PollVote) PollResults {
+	results := PollResults{PollID: pollID, ChatJID: chat.String(), Known: known, TotalVoters: len(votes), Options: []PollOptionResult{}}
+	index := make(map[string]int)
+	if known {
+		for i, hash := range whatsmeow.HashPollOptions(options) {
+			index[hex.EncodeToString(hash)] = i
+			results.Options = append(results.Options, PollOptionResult{Name: options[i], Voters: []string{}})
+		}
+	}
+	for _, vote := range votes {
+		for _, hash := range vote.Options {
+			i, ok := index[hash]
+			if !ok {
+				i = len(results.Options)
+				index[hash] = i
+				results.Options = append(results.Options, PollOptionResult{Name: "Option_" + hash, Voters: []string{}})
+			}
+			results.Options[i].Votes++
+			results.Options[i].Voters = append(results.Options[i].Voters, vote.VoterJID)
+		}
+	}
+	return results
+}
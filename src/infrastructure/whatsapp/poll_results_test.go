@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestTallyPoll(t *testing.T) {
+	chat := types.NewJID("120363000000000000", types.GroupServer)
+	hashes := whatsmeow.HashPollOptions([]string{"Pizza", "Sushi", "Salad"})
+	pizza, sushi := hex.EncodeToString(hashes[0]), hex.EncodeToString(hashes[1])
+	votes := []utils.PollVote{
+		{VoterJID: "628111@s.whatsapp.net", Options: []string{pizza, sushi}},
+		{VoterJID: "628222@s.whatsapp.net", Options: []string{sushi}},
+	}
+
+	results := tallyPoll(chat, "POLL1", []string{"Pizza", "Sushi", "Salad"}, true, votes)
+	if results.TotalVoters != 2 || len(results.Options) != 3 {
+		t.Fatalf("tallyPoll() = %+v, want 2 voters over 3 options", results)
+	}
+	for i, want := range []struct {
+		name  string
+		votes int
+	}{{"Pizza", 1}, {"Sushi", 2}, {"Salad", 0}} {
+		if got := results.Options[i]; got.Name != want.name || got.Votes != want.votes || len(got.Voters) != want.votes {
+			t.Errorf("option %d = %+v, want %s with %d votes", i, got, want.name, want.votes)
+		}
+	}
+
+	unknown := tallyPoll(chat, "POLL1", nil, false, votes)
+	if len(unknown.Options) != 2 || unknown.Options[0].Name != "Option_"+pizza || unknown.Options[1].Votes != 2 {
+		t.Errorf("tallyPoll() of an unknown poll = %+v, want the voted options named after their hash", unknown)
+	}
+}
+
+func TestGetPollResultsFromStorage(t *testing.T) {
+	dir := t.TempDir()
+	storage, votesPath, pollsPath := config.WhatsappChatStorage, config.PathPollVotes, config.PathPolls
+	config.WhatsappChatStorage = true
+	config.PathPollVotes, config.PathPolls = filepath.Join(dir, "votes.csv"), filepath.Join(dir, "polls.csv")
+	t.Cleanup(func() {
+		config.WhatsappChatStorage, config.PathPollVotes, config.PathPolls = storage, votesPath, pollsPath
+	})
+
+	// A poll this process has not seen is named from storage, as after a restart.
+	chat := types.NewJID("120363000000000000", types.GroupServer)
+	err := utils.RecordPoll(utils.StoredPoll{ChatJID: chat.String(), PollID: "STOREDPOLL", SenderJID: "628123@s.whatsapp.net", Options: []string{"Yes", "No"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := GetPollResults(chat, "STOREDPOLL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results.Known || len(results.Options) != 2 || results.Options[0].Name != "Yes" {
+		t.Errorf("GetPollResults() = %+v, want the stored options", results)
+	}
+
+	config.WhatsappChatStorage = false
+	if _, err := GetPollResults(chat, "STOREDPOLL"); !errors.Is(err, ErrPollResultsDisabled) {
+		t.Errorf("GetPollResults() without chat storage = %v, want ErrPollResultsDisabled", err)
+	}
+}
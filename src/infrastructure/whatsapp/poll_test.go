@@ -0,0 +1,102 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPollSelection(t *testing.T) {
+	chat := types.NewJID("120363000000000000", types.GroupServer)
+	sender := types.NewJID("6281234567890", types.DefaultUserServer)
+	rememberPoll("POLL1", chat, sender, false, &waProto.PollCreationMessage{
+		Name: proto.String("Lunch?"),
+		Options: []*waProto.PollCreationMessage_Option{
+			{OptionName: proto.String("Pizza")},
+			{OptionName: proto.String("Sushi")},
+			{OptionName: proto.String("Salad")},
+		},
+		SelectableOptionsCount: proto.Uint32(2),
+	})
+
+	poll, ok := FindPoll("POLL1")
+	if !ok {
+		t.Fatal("FindPoll() did not find the stored poll")
+	}
+	if poll.Chat != chat || poll.Sender != sender || poll.SelectableCount != 2 {
+		t.Fatalf("FindPoll() = %+v", poll)
+	}
+
+	tests := []struct {
+		name    string
+		names   []string
+		indexes []int
+		want    []string
+		wantErr bool
+	}{
+		{name: "By text", names: []string{"Sushi"}, want: []string{"Sushi"}},
+		{name: "By index", indexes: []int{2}, want: []string{"Salad"}},
+		{name: "Text and index deduplicated", names: []string{"Pizza"}, indexes: []int{0}, want: []string{"Pizza"}},
+		{name: "Unknown option", names: []string{"Tacos"}, wantErr: true},
+		{name: "Index out of range", indexes: []int{3}, wantErr: true},
+		{name: "Nothing selected", wantErr: true},
+		{name: "Above selectable limit", indexes: []int{0, 1, 2}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := poll.PollSelection(tt.names, tt.indexes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PollSelection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Errorf("PollSelection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := FindPoll("UNKNOWN"); ok {
+		t.Error("FindPoll() found a poll that was never stored")
+	}
+}
+
+func TestFindPollAfterRestart(t *testing.T) {
+	storage, pollsPath, cache := config.WhatsappChatStorage, config.PathPolls, polls
+	config.WhatsappChatStorage = true
+	config.PathPolls = filepath.Join(t.TempDir(), "polls.csv")
+	t.Cleanup(func() {
+		config.WhatsappChatStorage, config.PathPolls, polls = storage, pollsPath, cache
+	})
+
+	chat := types.NewJID("120363000000000000", types.GroupServer)
+	sender := types.NewJID("6281234567890", types.DefaultUserServer)
+	polls = &pollCache{entries: map[types.MessageID]cachedPoll{}}
+	rememberPoll("POLL2", chat, sender, true, &waProto.PollCreationMessage{
+		Name: proto.String("Color?"),
+		Options: []*waProto.PollCreationMessage_Option{
+			{OptionName: proto.String("Red")},
+			{OptionName: proto.String("Blue")},
+		},
+		SelectableOptionsCount: proto.Uint32(1),
+	})
+
+	// A restart empties the cache; the poll is read back from storage.
+	polls = &pollCache{entries: map[types.MessageID]cachedPoll{}}
+	poll, ok := FindPoll("POLL2")
+	if !ok {
+		t.Fatal("FindPoll() did not find the stored poll")
+	}
+	if poll.Chat != chat || poll.Sender != sender || !poll.FromMe || poll.SelectableCount != 1 {
+		t.Errorf("FindPoll() = %+v", poll)
+	}
+	if !slices.Equal(poll.Options, []string{"Red", "Blue"}) {
+		t.Errorf("FindPoll() options = %v, want [Red Blue]", poll.Options)
+	}
+	if _, ok := FindPoll("UNKNOWN"); ok {
+		t.Error("FindPoll() found a poll that was never stored")
+	}
+}
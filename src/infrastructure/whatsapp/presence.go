@@ -0,0 +1,111 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// chatPresenceState is the last chat presence sent to a JID.
+type chatPresenceState struct {
+	presence types.ChatPresence
+	media    types.ChatPresenceMedia
+	sentAt   time.Time
+}
+
+var (
+	chatPresences     = map[string]*chatPresenceState{}
+	chatPresenceMutex sync.Mutex
+)
+
+// SendChatPresence sends a typing or recording state to a chat. The same state
+// repeated within config.WhatsappPresenceDebounceMs is coalesced instead of sent
+// again. When pauseAfter is positive the chat is paused after that long; a new
+// call resets the pending pause instead of scheduling another one. It reports
+// whether an update was actually sent.
+func SendChatPresence(jid types.JID, presence types.ChatPresence, media types.ChatPresenceMedia, pauseAfter time.Duration) (bool, error) {
+	cli := GetWaCli()
+	if cli == nil {
+		return false, ErrClientNotInitialized
+	}
+	key := jid.ToNonAD().String()
+
+	// The lock only guards the state, presences are sent without it so one slow
+	// chat does not hold up the others.
+	window := time.Duration(config.WhatsappPresenceDebounceMs) * time.Millisecond
+	chatPresenceMutex.Lock()
+	state, ok := chatPresences[key]
+	if !ok {
+		state = &chatPresenceState{}
+		chatPresences[key] = state
+	}
+	previous := *state
+	coalesced := presence == types.ChatPresenceComposing && state.presence == presence &&
+		state.media == media && time.Since(state.sentAt) < window
+	if !coalesced {
+		// Claimed before sending, so concurrent calls coalesce on this update.
+		state.presence, state.media, state.sentAt = presence, media, time.Now()
+	}
+	claimed := *state
+	chatPresenceMutex.Unlock()
+
+	pauseKey := "presence-pause:" + key
+	utils.DefaultScheduler.Cancel(pauseKey)
+
+	if !coalesced {
+		if err := cli.SendChatPresence(jid, presence, media); err != nil {
+			chatPresenceMutex.Lock()
+			if *state == claimed {
+				*state = previous
+			}
+			chatPresenceMutex.Unlock()
+			return false, err
+		}
+	}
+
+	if pauseAfter > 0 && presence == types.ChatPresenceComposing {
+		utils.DefaultScheduler.Schedule(pauseKey, pauseAfter, func() {
+			if err := cli.SendChatPresence(jid, types.ChatPresencePaused, types.ChatPresenceMediaText); err != nil {
+				logrus.Errorf("Failed to send paused presence to %s: %v", jid.String(), err)
+				return
+			}
+			chatPresenceMutex.Lock()
+			defer chatPresenceMutex.Unlock()
+			state.presence, state.media, state.sentAt = types.ChatPresencePaused, types.ChatPresenceMediaText, time.Now()
+		})
+	}
+	return !coalesced, nil
+}
+
+// ContactPresence is the last presence received from a contact.
+type ContactPresence struct {
+	Online    bool
+	LastSeen  time.Time // zero when the contact hides it
+	UpdatedAt time.Time
+}
+
+// contactPresences caches the latest presence update per user JID.
+var contactPresences sync.Map
+
+// handlePresence stores a presence update of a subscribed contact.
+func handlePresence(evt *events.Presence) {
+	contactPresences.Store(evt.From.ToNonAD().String(), ContactPresence{
+		Online:    !evt.Unavailable,
+		LastSeen:  evt.LastSeen,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// LastPresence returns the last presence received from jid, if any.
+func LastPresence(jid types.JID) (ContactPresence, bool) {
+	value, ok := contactPresences.Load(jid.ToNonAD().String())
+	if !ok {
+		return ContactPresence{}, false
+	}
+	return value.(ContactPresence), true
+}
@@ -0,0 +1,33 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestHandlePresence(t *testing.T) {
+	contact := types.NewJID("6281234567890", types.DefaultUserServer)
+	t.Cleanup(func() { contactPresences.Delete(contact.String()) })
+
+	if _, ok := LastPresence(contact); ok {
+		t.Fatal("presence known before any update")
+	}
+
+	device := contact
+	device.Device = 2
+	handlePresence(&events.Presence{From: device})
+	presence, ok := LastPresence(contact)
+	if !ok || !presence.Online {
+		t.Fatalf("LastPresence() = %+v, %v, want online", presence, ok)
+	}
+
+	lastSeen := time.Unix(1700000000, 0)
+	handlePresence(&events.Presence{From: contact, Unavailable: true, LastSeen: lastSeen})
+	presence, _ = LastPresence(contact)
+	if presence.Online || !presence.LastSeen.Equal(lastSeen) {
+		t.Errorf("LastPresence() = %+v, want offline last seen at %v", presence, lastSeen)
+	}
+}
@@ -0,0 +1,102 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// SessionReadiness reports how prepared the client is to message a number.
+type SessionReadiness struct {
+	Phone    string `json:"phone"`
+	JID      string `json:"jid,omitempty"`
+	Status   string `json:"status"` // ready, partial, not_on_whatsapp or failed
+	Devices  int    `json:"devices"`
+	Sessions int    `json:"sessions"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PrewarmSession does ahead of time what the first message to a number would
+// otherwise do on the send path: it resolves the number, fetches its device
+// list and establishes an encryption session with every device from their
+// prekey bundles. The sessions are kept in the device store, so the real send
+// skips these round-trips.
+func PrewarmSession(ctx context.Context, phone string) SessionReadiness {
+	readiness := SessionReadiness{Phone: phone, Status: "failed"}
+	cli, err := ConnectedClient()
+	if err != nil {
+		readiness.Error = err.Error()
+		return readiness
+	}
+
+	jid, err := ResolveRecipientJID(phone)
+	if err == nil && jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+		err = pkgError.InvalidJID("only contacts have sessions to prewarm")
+	}
+	if err != nil {
+		readiness.Error = err.Error()
+		return readiness
+	}
+	if jid, err = lookupRecipient(ctx, cli, jid); err != nil {
+		// The lookup only reports an invalid JID for numbers without an account.
+		var invalid pkgError.InvalidJID
+		if errors.As(err, &invalid) {
+			readiness.Status = "not_on_whatsapp"
+		}
+		readiness.Error = err.Error()
+		return readiness
+	}
+	readiness.JID = jid.String()
+
+	devices, err := cli.GetUserDevicesContext(ctx, []types.JID{jid})
+	if err != nil {
+		readiness.Error = err.Error()
+		return readiness
+	}
+	readiness.Devices = len(devices)
+
+	var missing []types.JID
+	for _, device := range devices {
+		if hasSession(ctx, cli, device) {
+			readiness.Sessions++
+		} else {
+			missing = append(missing, device)
+		}
+	}
+	if len(missing) > 0 {
+		// Encrypting for devices without a session fetches their prekey bundles and
+		// stores the resulting sessions. The ciphertext itself is never sent.
+		nodes, _ := cli.DangerousInternals().EncryptMessageForDevices(ctx, missing, "prewarm", []byte{}, nil, nil)
+		readiness.Sessions += len(nodes)
+	}
+
+	switch {
+	case readiness.Devices > 0 && readiness.Sessions >= readiness.Devices:
+		readiness.Status = "ready"
+	case readiness.Sessions > 0:
+		readiness.Status = "partial"
+	default:
+		readiness.Error = "no session could be established"
+	}
+	return readiness
+}
+
+// hasSession reports whether a device already has a session, under its phone
+// number address or the LID address sessions are migrated to.
+func hasSession(ctx context.Context, cli *whatsmeow.Client, device types.JID) bool {
+	if ok, err := cli.Store.ContainsSession(ctx, device.SignalAddress()); err == nil && ok {
+		return true
+	}
+	if device.Server != types.DefaultUserServer {
+		return false
+	}
+	lid, err := cli.Store.LIDs.GetLIDForPN(ctx, device)
+	if err != nil || lid.IsEmpty() {
+		return false
+	}
+	ok, err := cli.Store.ContainsSession(ctx, lid.SignalAddress())
+	return err == nil && ok
+}
@@ -0,0 +1,19 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPrewarmSessionBeforeInit(t *testing.T) {
+	if GetWaCli() != nil {
+		t.Skip("client already initialized")
+	}
+	readiness := PrewarmSession(context.Background(), "6281111111111")
+	if readiness.Status != "failed" || readiness.Error != ErrClientNotInitialized.Error() {
+		t.Errorf("PrewarmSession() = %+v, want failed with %v", readiness, ErrClientNotInitialized)
+	}
+	if readiness.Phone != "6281111111111" {
+		t.Errorf("Phone = %q, want the requested number", readiness.Phone)
+	}
+}
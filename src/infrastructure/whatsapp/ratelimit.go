@@ -0,0 +1,91 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+// sendLimiter spaces outbound messages evenly so that no more than
+// config.WhatsappSendRateLimit messages leave per minute.
+var sendLimiter = &rateLimiter{}
+
+type rateLimiter struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// reserve returns how long the caller must wait before its send slot.
+func (l *rateLimiter) reserve(perMinute int) time.Duration {
+	if perMinute <= 0 {
+		return 0
+	}
+	interval := time.Minute / time.Duration(perMinute)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(interval)
+	return wait
+}
+
+// RateLimitStatus is the send budget left under config.WhatsappSendRateLimit.
+type RateLimitStatus struct {
+	Enabled   bool      `json:"enabled"`
+	PerMinute int       `json:"per_minute,omitempty"`
+	Remaining int       `json:"remaining"` // sends that still fit in the coming minute
+	Queued    int       `json:"queued"`    // slots already reserved by sends that are waiting
+	NextSlot  time.Time `json:"next_slot"` // when the next send would leave
+}
+
+// status reads the limiter without reserving a slot.
+func (l *rateLimiter) status(perMinute int, now time.Time) RateLimitStatus {
+	if perMinute <= 0 {
+		return RateLimitStatus{Remaining: -1, NextSlot: now}
+	}
+	interval := time.Minute / time.Duration(perMinute)
+
+	l.mu.Lock()
+	next := l.next
+	l.mu.Unlock()
+	if next.Before(now) {
+		next = now
+	}
+	queued := int((next.Sub(now) + interval - 1) / interval)
+	return RateLimitStatus{
+		Enabled:   true,
+		PerMinute: perMinute,
+		Remaining: max(perMinute-queued, 0),
+		Queued:    queued,
+		NextSlot:  next,
+	}
+}
+
+// SendRateLimitStatus reports the budget of the outbound rate limiter. Remaining
+// is -1 when sends are not limited.
+func SendRateLimitStatus() RateLimitStatus {
+	return sendLimiter.status(config.WhatsappSendRateLimit, time.Now())
+}
+
+// waitSendSlot blocks until the rate limiter allows the next send.
+func waitSendSlot(ctx context.Context) error {
+	wait := sendLimiter.reserve(config.WhatsappSendRateLimit)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,31 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterStatus(t *testing.T) {
+	limiter := &rateLimiter{}
+	now := time.Now()
+
+	if status := limiter.status(0, now); status.Enabled || status.Remaining != -1 {
+		t.Errorf("status() without a limit = %+v, want disabled with Remaining -1", status)
+	}
+
+	status := limiter.status(60, now)
+	if !status.Enabled || status.Remaining != 60 || status.Queued != 0 || !status.NextSlot.Equal(now) {
+		t.Errorf("status() when idle = %+v, want the full budget available now", status)
+	}
+
+	for i := 0; i < 3; i++ {
+		limiter.reserve(60)
+	}
+	status = limiter.status(60, time.Now())
+	if status.Queued != 3 || status.Remaining != 57 {
+		t.Errorf("status() after 3 sends = %+v, want 3 queued and 57 remaining", status)
+	}
+	if !status.NextSlot.After(time.Now().Add(2 * time.Second)) {
+		t.Errorf("NextSlot = %s, want about 3 seconds ahead", status.NextSlot)
+	}
+}
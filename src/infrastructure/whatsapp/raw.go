@@ -0,0 +1,101 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// rawMessageFields are the waE2E.Message fields accepted by the raw send
+// endpoint, at the top level and in every message nested in it, such as a
+// quoted message. Protocol, key distribution and media messages are left out:
+// they can revoke or edit other messages, break encryption sessions, or point
+// at media this server never uploaded.
+var rawMessageFields = []protoreflect.Name{
+	"conversation",
+	"extendedTextMessage",
+	"locationMessage",
+	"liveLocationMessage",
+	"contactMessage",
+	"contactsArrayMessage",
+	"listMessage",
+	"buttonsMessage",
+	"templateMessage",
+	"interactiveMessage",
+	"pollCreationMessage",
+	"pollCreationMessageV3",
+	"reactionMessage",
+	"eventMessage",
+	"messageContextInfo",
+}
+
+// rawMessageName is the protobuf name of waE2E.Message.
+var rawMessageName = (&waProto.Message{}).ProtoReflect().Descriptor().FullName()
+
+// ParseRawMessage maps the protojson representation of a waE2E.Message and
+// rejects fields outside rawMessageFields, wherever a message is nested.
+func ParseRawMessage(data []byte) (*waProto.Message, error) {
+	msg := &waProto.Message{}
+	if err := protojson.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+
+	var rejected []string
+	rejectRawFields(msg.ProtoReflect(), "", &rejected)
+	if len(rejected) > 0 {
+		return nil, fmt.Errorf("fields not allowed in a raw message: %s", strings.Join(rejected, ", "))
+	}
+
+	content := 0
+	msg.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if field.Name() != "messageContextInfo" {
+			content++
+		}
+		return true
+	})
+	if content == 0 {
+		return nil, errors.New("raw message has no content")
+	}
+	return msg, nil
+}
+
+// rejectRawFields walks msg and appends to rejected the path of every field
+// outside rawMessageFields set on a waE2E.Message found in it.
+func rejectRawFields(msg protoreflect.Message, path string, rejected *[]string) {
+	isMessage := msg.Descriptor().FullName() == rawMessageName
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		fieldPath := string(field.Name())
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+		if isMessage && !slices.Contains(rawMessageFields, field.Name()) {
+			*rejected = append(*rejected, fieldPath)
+			return true
+		}
+		if field.Kind() != protoreflect.MessageKind && field.Kind() != protoreflect.GroupKind {
+			return true
+		}
+		switch {
+		case field.IsList():
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				rejectRawFields(list.Get(i).Message(), fmt.Sprintf("%s[%d]", fieldPath, i), rejected)
+			}
+		case field.IsMap():
+			if field.MapValue().Message() != nil {
+				value.Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+					rejectRawFields(value.Message(), fieldPath+"["+key.String()+"]", rejected)
+					return true
+				})
+			}
+		default:
+			rejectRawFields(value.Message(), fieldPath, rejected)
+		}
+		return true
+	})
+}
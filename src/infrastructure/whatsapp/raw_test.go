@@ -0,0 +1,54 @@
+package whatsapp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRawMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     string
+		rejected string
+	}{
+		{name: "text", json: `{"conversation":"hi"}`},
+		{
+			name: "quoted text",
+			json: `{"extendedTextMessage":{"text":"hi","contextInfo":{"quotedMessage":{"conversation":"hello"}}}}`,
+		},
+		{name: "top-level protocol message", json: `{"protocolMessage":{"type":"REVOKE"}}`, rejected: "protocolMessage"},
+		{
+			name:     "quoted protocol message",
+			json:     `{"extendedTextMessage":{"text":"hi","contextInfo":{"quotedMessage":{"protocolMessage":{"type":"REVOKE"}}}}}`,
+			rejected: "extendedTextMessage.contextInfo.quotedMessage.protocolMessage",
+		},
+		{
+			name:     "media in a quoted template",
+			json:     `{"templateMessage":{"contextInfo":{"quotedMessage":{"imageMessage":{"URL":"https://example.com/a.jpg"}}}}}`,
+			rejected: "templateMessage.contextInfo.quotedMessage.imageMessage",
+		},
+		{
+			name:     "key distribution in a quoted interactive message",
+			json:     `{"interactiveMessage":{"contextInfo":{"quotedMessage":{"senderKeyDistributionMessage":{"groupID":"g"}}}}}`,
+			rejected: "interactiveMessage.contextInfo.quotedMessage.senderKeyDistributionMessage",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseRawMessage([]byte(tt.json))
+			if tt.rejected == "" {
+				if err != nil || msg == nil {
+					t.Fatalf("ParseRawMessage() = %v, want the message", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.rejected) {
+				t.Errorf("ParseRawMessage() = %v, want %s rejected", err, tt.rejected)
+			}
+		})
+	}
+
+	if _, err := ParseRawMessage([]byte(`{"messageContextInfo":{}}`)); err == nil {
+		t.Error("ParseRawMessage() accepted a message without content")
+	}
+}
@@ -0,0 +1,114 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// recipientLookupTTL bounds how long the JID WhatsApp returned for a number is
+// reused. Numbers rarely move, but they can be registered or dropped.
+const recipientLookupTTL = 24 * time.Hour
+
+type skipLookupKey struct{}
+
+// WithoutRecipientLookup makes SendMessage address the JID as given, for callers
+// that already hold the canonical JID of the recipient.
+func WithoutRecipientLookup(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipLookupKey{}, true)
+}
+
+// recipientLookups caches IsOnWhatsApp answers by phone number.
+var recipientLookups = &recipientLookupCache{entries: map[string]cachedRecipient{}}
+
+type cachedRecipient struct {
+	jid     types.JID
+	isIn    bool
+	expires time.Time
+}
+
+type recipientLookupCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedRecipient
+}
+
+func (c *recipientLookupCache) get(phone string) (cachedRecipient, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[phone]
+	if !ok || time.Now().After(entry.expires) {
+		return cachedRecipient{}, false
+	}
+	return entry, true
+}
+
+func (c *recipientLookupCache) put(phone string, jid types.JID, isIn bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[phone] = cachedRecipient{jid: jid, isIn: isIn, expires: now.Add(recipientLookupTTL)}
+}
+
+// ResolveRecipient returns the JID SendMessage delivers to jid on, so callers
+// can key statuses, receipts, history and timers on the chat the message really
+// lands in rather than on the number as it was typed.
+func ResolveRecipient(ctx context.Context, jid types.JID) (types.JID, error) {
+	cli, err := ConnectedClient()
+	if err != nil {
+		return types.JID{}, err
+	}
+	return lookupRecipient(ctx, cli, jid)
+}
+
+// lookupRecipient replaces a phone number JID with the one WhatsApp registered
+// for it, so first contact with a number lands on the account that owns it even
+// when the number was written in a different form (a missing or extra mobile
+// prefix digit, for example). Groups, LIDs and other servers pass through.
+func lookupRecipient(ctx context.Context, cli *whatsmeow.Client, jid types.JID) (types.JID, error) {
+	if jid.Server != types.DefaultUserServer || !config.WhatsappRecipientLookup {
+		return jid, nil
+	}
+	if skip, _ := ctx.Value(skipLookupKey{}).(bool); skip {
+		return jid, nil
+	}
+
+	if cached, ok := recipientLookups.get(jid.User); ok {
+		if !cached.isIn {
+			return types.JID{}, pkgError.InvalidJID(fmt.Sprintf("Phone %s is not on WhatsApp", jid.User))
+		}
+		return cached.jid, nil
+	}
+
+	results, err := cli.IsOnWhatsApp([]string{"+" + jid.User})
+	if err != nil {
+		return types.JID{}, fmt.Errorf("failed to look up %s on WhatsApp: %w", jid.User, err)
+	}
+	if len(results) == 0 {
+		return types.JID{}, fmt.Errorf("failed to look up %s on WhatsApp: empty response", jid.User)
+	}
+	result := results[0]
+	resolved := result.JID.ToNonAD()
+	if result.IsIn && resolved.User == "" {
+		resolved = jid
+	}
+	recipientLookups.put(jid.User, resolved, result.IsIn)
+	if !result.IsIn {
+		return types.JID{}, pkgError.InvalidJID(fmt.Sprintf("Phone %s is not on WhatsApp", jid.User))
+	}
+	if resolved != jid {
+		logrus.Debugf("Resolved recipient %s to %s", jid, resolved)
+	}
+	return resolved, nil
+}
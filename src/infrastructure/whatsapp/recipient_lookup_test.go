@@ -0,0 +1,36 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestLookupRecipient(t *testing.T) {
+	ctx := context.Background()
+	group := types.NewJID("120363025246125486", types.GroupServer)
+	if got, err := lookupRecipient(ctx, nil, group); err != nil || got != group {
+		t.Errorf("lookupRecipient(group) = %v, %v; want it unchanged", got, err)
+	}
+
+	typed := types.NewJID("5511987654321", types.DefaultUserServer)
+	if got, err := lookupRecipient(WithoutRecipientLookup(ctx), nil, typed); err != nil || got != typed {
+		t.Errorf("lookupRecipient(skipped) = %v, %v; want it unchanged", got, err)
+	}
+
+	registered := types.NewJID("551187654321", types.DefaultUserServer)
+	recipientLookups.put(typed.User, registered, true)
+	if got, err := lookupRecipient(ctx, nil, typed); err != nil || got != registered {
+		t.Errorf("lookupRecipient(cached) = %v, %v; want %v", got, err, registered)
+	}
+
+	missing := types.NewJID("15550000000", types.DefaultUserServer)
+	recipientLookups.put(missing.User, types.EmptyJID, false)
+	var invalid pkgError.InvalidJID
+	if _, err := lookupRecipient(ctx, nil, missing); !errors.As(err, &invalid) {
+		t.Errorf("lookupRecipient(not on WhatsApp) error = %v, want InvalidJID", err)
+	}
+}
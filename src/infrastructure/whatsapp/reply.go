@@ -0,0 +1,54 @@
+package whatsapp
+
+import (
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// CrossChatQuote builds the context info quoting a stored message of chat from
+// a message sent to another chat. The message must be in the chat history; its
+// author becomes the quoted participant.
+func CrossChatQuote(chat types.JID, messageID string) (*waProto.ContextInfo, error) {
+	chat = chat.ToNonAD()
+	message, err := utils.FindChatHistoryMessage(chat.String(), messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	participant, err := quotedParticipant(chat, message)
+	if err != nil {
+		return nil, err
+	}
+	return &waProto.ContextInfo{
+		StanzaID:      proto.String(messageID),
+		Participant:   proto.String(participant.String()),
+		RemoteJID:     proto.String(chat.String()),
+		QuotedMessage: &waProto.Message{Conversation: proto.String(message.Content)},
+	}, nil
+}
+
+// quotedParticipant returns the author of a stored message: this account for
+// its own messages, the sender in groups and the chat itself in direct chats.
+func quotedParticipant(chat types.JID, message utils.ChatHistoryMessage) (types.JID, error) {
+	switch {
+	case message.FromMe:
+		cli := GetWaCli()
+		if cli == nil || cli.Store.ID == nil {
+			return types.JID{}, fmt.Errorf("WhatsApp client not logged in")
+		}
+		return cli.Store.ID.ToNonAD(), nil
+	case message.SenderJID != "":
+		sender, err := types.ParseJID(message.SenderJID)
+		if err != nil {
+			return types.JID{}, fmt.Errorf("invalid sender of message %s: %w", message.MessageID, err)
+		}
+		return sender.ToNonAD(), nil
+	case chat.Server == types.GroupServer:
+		return types.JID{}, fmt.Errorf("sender of group message %s is unknown", message.MessageID)
+	}
+	return chat, nil
+}
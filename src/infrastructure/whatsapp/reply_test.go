@@ -0,0 +1,24 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestQuotedParticipant(t *testing.T) {
+	user := types.NewJID("6281234567890", types.DefaultUserServer)
+	group := types.NewJID("120363000000000000", types.GroupServer)
+
+	got, err := quotedParticipant(group, utils.ChatHistoryMessage{MessageID: "A", SenderJID: "6282222222222:3@s.whatsapp.net"})
+	if err != nil || got.String() != "6282222222222@s.whatsapp.net" {
+		t.Errorf("group message participant = %v, %v, want the sender without device", got, err)
+	}
+	if _, err := quotedParticipant(group, utils.ChatHistoryMessage{MessageID: "B"}); err == nil {
+		t.Error("group message without sender did not fail")
+	}
+	if got, err := quotedParticipant(user, utils.ChatHistoryMessage{MessageID: "C"}); err != nil || got != user {
+		t.Errorf("direct message participant = %v, %v, want the chat", got, err)
+	}
+}
@@ -0,0 +1,110 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	requestKindLocation = "location"
+	requestKindPhone    = "phone"
+)
+
+// requestPromptTTL is how long a location or phone request waits for its answer.
+const requestPromptTTL = 24 * time.Hour
+
+// requestResponseTTL is how long the link between an answer and its request is
+// kept, long enough for the webhook and chat history of the answer to use it.
+const requestResponseTTL = 10 * time.Minute
+
+type requestPrompt struct {
+	kind      string
+	messageID types.MessageID
+	sentAt    time.Time
+}
+
+var (
+	// pendingRequests holds the last unanswered request per chat.
+	pendingRequests sync.Map
+	// requestResponses maps answer message IDs to the request they answer.
+	requestResponses sync.Map
+)
+
+// RequestLocation asks the chat to share its location with a native flow button.
+func RequestLocation(ctx context.Context, jid types.JID, text string) (whatsmeow.SendResponse, error) {
+	msg := &waProto.Message{InteractiveMessage: &waProto.InteractiveMessage{
+		Body: &waProto.InteractiveMessage_Body{Text: proto.String(text)},
+		InteractiveMessage: &waProto.InteractiveMessage_NativeFlowMessage_{
+			NativeFlowMessage: &waProto.InteractiveMessage_NativeFlowMessage{
+				Buttons: []*waProto.InteractiveMessage_NativeFlowMessage_NativeFlowButton{{
+					Name:             proto.String("send_location"),
+					ButtonParamsJSON: proto.String("{}"),
+				}},
+			},
+		},
+	}}
+	return sendRequestPrompt(ctx, jid, requestKindLocation, msg)
+}
+
+// RequestPhoneNumber asks the chat to share its phone number.
+func RequestPhoneNumber(ctx context.Context, jid types.JID) (whatsmeow.SendResponse, error) {
+	msg := &waProto.Message{RequestPhoneNumberMessage: &waProto.RequestPhoneNumberMessage{}}
+	return sendRequestPrompt(ctx, jid, requestKindPhone, msg)
+}
+
+func sendRequestPrompt(ctx context.Context, jid types.JID, kind string, msg *waProto.Message) (whatsmeow.SendResponse, error) {
+	resp, err := SendMessage(ctx, jid, msg)
+	if err != nil {
+		return resp, err
+	}
+	pendingRequests.Store(jid.ToNonAD().String(), requestPrompt{kind: kind, messageID: resp.ID, sentAt: time.Now()})
+	return resp, nil
+}
+
+// linkRequestResponse checks whether an inbound message answers the pending
+// request of its chat and, if so, links the two so the answer is classified as
+// the response to the request.
+func linkRequestResponse(evt *events.Message) {
+	if evt.Info.IsFromMe {
+		return
+	}
+	kind := requestKindPhone
+	switch {
+	case evt.Message.GetLocationMessage() != nil:
+		kind = requestKindLocation
+	case evt.Message.GetContactMessage() == nil:
+		return
+	}
+
+	chat := evt.Info.Chat.ToNonAD().String()
+	value, ok := pendingRequests.Load(chat)
+	if !ok {
+		return
+	}
+	request := value.(requestPrompt)
+	if request.kind != kind || time.Since(request.sentAt) > requestPromptTTL {
+		return
+	}
+	pendingRequests.Delete(chat)
+	requestResponses.Store(evt.Info.ID, request)
+	utils.DefaultScheduler.Schedule("request-response:"+evt.Info.ID, requestResponseTTL, func() {
+		requestResponses.Delete(evt.Info.ID)
+	})
+}
+
+// requestResponse returns the request a message answers, if any.
+func requestResponse(id types.MessageID) (requestPrompt, bool) {
+	value, ok := requestResponses.Load(id)
+	if !ok {
+		return requestPrompt{}, false
+	}
+	return value.(requestPrompt), true
+}
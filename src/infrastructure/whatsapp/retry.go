@@ -0,0 +1,106 @@
+package whatsapp
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// retryCacheSize is how many sent messages are kept to answer retry receipts.
+// whatsmeow only keeps the last 256, which is too few for a busy bot, so a
+// recipient whose device failed to decrypt a message would be left with
+// "waiting for this message".
+const retryCacheSize = 5000
+
+// RetryStats counts message retries since the process started.
+type RetryStats struct {
+	// Resent is how many retry receipts were answered by resending the message.
+	Resent int64 `json:"resent"`
+	// Missed is how many retry receipts asked for a message no longer known.
+	Missed int64 `json:"missed"`
+	// Undecryptable is how many received messages could not be decrypted and
+	// were re-requested from the sender or the primary phone.
+	Undecryptable int64 `json:"undecryptable"`
+}
+
+var (
+	retriesResent        atomic.Int64
+	retriesMissed        atomic.Int64
+	undecryptableCounter atomic.Int64
+
+	retryCache = &sentMessageCache{entries: map[string]*waProto.Message{}}
+)
+
+type sentMessageCache struct {
+	mu      sync.Mutex
+	entries map[string]*waProto.Message
+	order   []string
+}
+
+func retryCacheKey(chat types.JID, id types.MessageID) string {
+	return chat.ToNonAD().String() + "/" + id
+}
+
+func (c *sentMessageCache) put(chat types.JID, id types.MessageID, msg *waProto.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := retryCacheKey(chat, id)
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = proto.Clone(msg).(*waProto.Message)
+	for len(c.order) > retryCacheSize {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func (c *sentMessageCache) get(chat types.JID, id types.MessageID) *waProto.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if msg, ok := c.entries[retryCacheKey(chat, id)]; ok {
+		return proto.Clone(msg).(*waProto.Message)
+	}
+	return nil
+}
+
+// configureRetries makes the client answer retry receipts for every message
+// sent by this process and re-request undecryptable messages from the phone.
+func configureRetries(client *whatsmeow.Client) {
+	client.AutomaticMessageRerequestFromPhone = true
+	client.GetMessageForRetry = func(requester, to types.JID, id types.MessageID) *waProto.Message {
+		msg := retryCache.get(to, id)
+		if msg == nil {
+			retriesMissed.Add(1)
+			logrus.Warnf("Retry receipt from %s for unknown message %s in %s", requester, id, to)
+		}
+		return msg
+	}
+	client.PreRetryCallback = func(receipt *events.Receipt, id types.MessageID, retryCount int, _ *waProto.Message) bool {
+		retriesResent.Add(1)
+		logrus.Infof("Resending message %s to %s in %s (retry %d)", id, receipt.Sender, receipt.Chat, retryCount)
+		return true
+	}
+}
+
+// handleUndecryptableMessage records a message this device could not decrypt.
+func handleUndecryptableMessage(evt *events.UndecryptableMessage) {
+	undecryptableCounter.Add(1)
+	logrus.Warnf("Could not decrypt message %s from %s in %s (unavailable: %t)",
+		evt.Info.ID, evt.Info.SourceString(), evt.Info.Chat, evt.IsUnavailable)
+}
+
+// GetRetryStats returns the retry counters.
+func GetRetryStats() RetryStats {
+	return RetryStats{
+		Resent:        retriesResent.Load(),
+		Missed:        retriesMissed.Load(),
+		Undecryptable: undecryptableCounter.Load(),
+	}
+}
@@ -0,0 +1,37 @@
+package whatsapp
+
+import (
+	"strconv"
+	"testing"
+
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSentMessageCache(t *testing.T) {
+	cache := &sentMessageCache{entries: map[string]*waProto.Message{}}
+	chat := types.NewJID("6281234567890", types.DefaultUserServer)
+
+	for i := 0; i <= retryCacheSize; i++ {
+		cache.put(chat, types.MessageID(strconv.Itoa(i)), &waProto.Message{Conversation: proto.String("hi")})
+	}
+	if len(cache.entries) != retryCacheSize || len(cache.order) != retryCacheSize {
+		t.Fatalf("cache holds %d entries, want %d", len(cache.entries), retryCacheSize)
+	}
+
+	cache.put(chat, "LAST", &waProto.Message{Conversation: proto.String("hello")})
+	device := chat
+	device.Device = 3
+	msg := cache.get(device, "LAST")
+	if msg.GetConversation() != "hello" {
+		t.Fatalf("get() = %v, want the stored message", msg)
+	}
+	msg.Conversation = proto.String("changed")
+	if cache.get(chat, "LAST").GetConversation() != "hello" {
+		t.Error("get() returned the stored message instead of a copy")
+	}
+	if cache.get(chat, "MISSING") != nil {
+		t.Error("get() found a message that was never stored")
+	}
+}
@@ -0,0 +1,253 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+type ephemeralKey struct{}
+
+// WithEphemeral makes SendMessage mark the outgoing message as disappearing
+// after the given number of seconds.
+func WithEphemeral(ctx context.Context, seconds uint32) context.Context {
+	return context.WithValue(ctx, ephemeralKey{}, seconds)
+}
+
+// ValidateEphemeral checks that seconds is one of the durations WhatsApp accepts
+// and, for groups, that it matches the chat-level disappearing timer if one is set.
+func ValidateEphemeral(waCli *whatsmeow.Client, jid types.JID, seconds uint32) error {
+	switch time.Duration(seconds) * time.Second {
+	case whatsmeow.DisappearingTimer24Hours, whatsmeow.DisappearingTimer7Days, whatsmeow.DisappearingTimer90Days:
+	default:
+		return errors.New(utils.T("ephemeral_seconds_invalid"))
+	}
+
+	if jid.Server != types.GroupServer {
+		return nil
+	}
+	group, err := waCli.GetGroupInfo(jid)
+	if err != nil {
+		return fmt.Errorf("failed to read group disappearing timer: %w", err)
+	}
+	if group.IsEphemeral && group.DisappearingTimer != seconds {
+		return errors.New(utils.T("ephemeral_timer_conflict", seconds, group.DisappearingTimer))
+	}
+	return nil
+}
+
+// ValidateViewOnce checks that WhatsApp accepts view-once for the given kind of
+// media. Images and videos always qualify, audio only as an OGG/Opus voice note,
+// and documents never do.
+func ValidateViewOnce(kind, mimeType string) error {
+	switch kind {
+	case "image", "video":
+		return nil
+	case "audio":
+		if mimeType != "audio/ogg" {
+			return errors.New(utils.T("view_once_audio_invalid", mimeType))
+		}
+		return nil
+	}
+	return errors.New(utils.T("view_once_unsupported", kind))
+}
+
+// CheckRecipient returns a RecipientNotAllowed error when the configured
+// allow/deny lists forbid messaging jid.
+func CheckRecipient(jid types.JID) error {
+	if !utils.RecipientAllowed(jid.ToNonAD().String()) {
+		return pkgError.RecipientNotAllowed(utils.T("recipient_not_allowed", jid.ToNonAD().String()))
+	}
+	return nil
+}
+
+// SendMessage is the shared send path for every outbound message. It enforces the
+// recipient policy and records the message ID so its delivery status can be
+// queried later.
+func SendMessage(ctx context.Context, jid types.JID, msg *waProto.Message) (whatsmeow.SendResponse, error) {
+	if err := CheckRecipient(jid); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	cli, err := ConnectedClient()
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	if resolved, err := lookupRecipient(ctx, cli, jid); err != nil {
+		return whatsmeow.SendResponse{}, err
+	} else if resolved != jid {
+		// The policy applies to the account the message lands on, not only to the
+		// number as it was typed.
+		if err := CheckRecipient(resolved); err != nil {
+			return whatsmeow.SendResponse{}, err
+		}
+		jid = resolved
+	}
+
+	if seconds, ok := ctx.Value(ephemeralKey{}).(uint32); ok && seconds > 0 {
+		applyEphemeral(msg, seconds)
+	}
+
+	// Claim a caller-supplied ID before waiting for a send slot, so a duplicate
+	// is refused right away instead of after the rate limit and typing delay.
+	var extra whatsmeow.SendRequestExtra
+	if id, ok := ctx.Value(messageIDKey{}).(types.MessageID); ok && id != "" {
+		if !usedMessageIDs.reserve(id) {
+			return whatsmeow.SendResponse{}, fmt.Errorf("%w: %s", ErrDuplicateMessageID, id)
+		}
+		extra.ID = id
+	}
+	delivered := false
+	defer func() {
+		if !delivered && extra.ID != "" {
+			usedMessageIDs.release(extra.ID)
+		}
+	}()
+
+	if err := waitSendSlot(ctx); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	if err := humanizeSend(ctx, jid, msg); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	resp, err := cli.SendMessage(ctx, jid, msg, extra)
+	delivered = err == nil
+	if errors.Is(err, whatsmeow.ErrNotConnected) || (err != nil && reconnecting.Load()) {
+		// The connection dropped while the message was waiting to be sent.
+		return resp, fmt.Errorf("%w: %v", ErrReconnecting, err)
+	} else if err != nil {
+		return resp, err
+	}
+
+	if err := utils.RecordSentMessage(resp.ID, jid.String(), resp.Timestamp); err != nil {
+		logrus.Warnf("Failed to record sent message %s: %v", resp.ID, err)
+	}
+	text := messageText(msg)
+	sent := utils.ChatHistoryMessage{
+		ChatJID:   jid.String(),
+		MessageID: resp.ID,
+		FromMe:    true,
+		Timestamp: resp.Timestamp,
+		Content:   text,
+		Type:      determineMessageType(&events.Message{Message: msg}, text),
+	}
+	sent.MediaType, sent.MediaPath = mediaReference(msg)
+	if editedID, edited := editedContent(&events.Message{Message: msg}); edited != nil {
+		// As with a received edit, the stored original takes the new content.
+		sent.Content = messageText(edited)
+		if _, err := utils.UpdateChatHistoryContent(jid.String(), editedID, sent.Content); err != nil {
+			logrus.Warnf("Failed to store edit of message %s: %v", editedID, err)
+		}
+	} else if _, err := utils.RecordChatHistory([]utils.ChatHistoryMessage{sent}); err != nil {
+		logrus.Warnf("Failed to store message %s in chat history: %v", resp.ID, err)
+	}
+	forwardSentMessage(sent)
+	retryCache.put(jid, resp.ID, msg)
+	rememberMedia(jid, resp.ID, msg)
+	if reaction := msg.GetReactionMessage(); reaction != nil && cli.Store.ID != nil {
+		RecordReaction(jid, *cli.Store.ID, reaction, resp.Timestamp)
+	}
+	if poll := pollCreation(msg); poll != nil && cli.Store.ID != nil {
+		rememberPoll(resp.ID, jid, cli.Store.ID.ToNonAD(), true, poll)
+	}
+	return resp, nil
+}
+
+// applyEphemeral sets the expiration on the context info of the message content.
+func applyEphemeral(msg *waProto.Message, seconds uint32) {
+	if msg.Conversation != nil {
+		msg.ExtendedTextMessage = &waProto.ExtendedTextMessage{Text: msg.Conversation}
+		msg.Conversation = nil
+	}
+
+	var info **waProto.ContextInfo
+	switch {
+	case msg.ExtendedTextMessage != nil:
+		info = &msg.ExtendedTextMessage.ContextInfo
+	case msg.ImageMessage != nil:
+		info = &msg.ImageMessage.ContextInfo
+	case msg.VideoMessage != nil:
+		info = &msg.VideoMessage.ContextInfo
+	case msg.AudioMessage != nil:
+		info = &msg.AudioMessage.ContextInfo
+	case msg.DocumentMessage != nil:
+		info = &msg.DocumentMessage.ContextInfo
+	case msg.LocationMessage != nil:
+		info = &msg.LocationMessage.ContextInfo
+	case msg.StickerMessage != nil:
+		info = &msg.StickerMessage.ContextInfo
+	case msg.ContactMessage != nil:
+		info = &msg.ContactMessage.ContextInfo
+	default:
+		logrus.Warnf("Ephemeral expiration is not supported for this message type, sending as is")
+		return
+	}
+
+	if *info == nil {
+		*info = &waProto.ContextInfo{}
+	}
+	(*info).Expiration = proto.Uint32(seconds)
+	(*info).EphemeralSettingTimestamp = proto.Int64(time.Now().Unix())
+}
+
+// humanizeSend waits the configured jittered delay before a send, showing a
+// typing indicator meanwhile when enabled. It is a no-op with the default config
+// and for revokes, edits and reactions.
+func humanizeSend(ctx context.Context, jid types.JID, msg *waProto.Message) error {
+	if msg.GetProtocolMessage() != nil || msg.GetReactionMessage() != nil {
+		return nil
+	}
+	delay := utils.SendDelay(len(messageText(msg)), rand.Float64())
+	if delay <= 0 {
+		return nil
+	}
+
+	if config.WhatsappPreSendTyping {
+		if _, err := SendChatPresence(jid, types.ChatPresenceComposing, types.ChatPresenceMediaText, 0); err != nil {
+			logrus.Warnf("Failed to send typing presence to %s: %v", jid.String(), err)
+		}
+		defer func() {
+			if _, err := SendChatPresence(jid, types.ChatPresencePaused, types.ChatPresenceMediaText, 0); err != nil {
+				logrus.Warnf("Failed to clear typing presence for %s: %v", jid.String(), err)
+			}
+		}()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// messageText returns the text or caption carried by a message.
+func messageText(msg *waProto.Message) string {
+	switch {
+	case msg.GetConversation() != "":
+		return msg.GetConversation()
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetText()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetCaption()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetCaption()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetCaption()
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestValidateViewOnce(t *testing.T) {
+	tests := []struct {
+		kind     string
+		mimeType string
+		wantErr  bool
+	}{
+		{"image", "image/jpeg", false},
+		{"video", "video/mp4", false},
+		{"audio", "audio/ogg", false},
+		{"audio", "audio/mpeg", true},
+		{"document", "application/pdf", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateViewOnce(tt.kind, tt.mimeType); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateViewOnce(%q, %q) error = %v, wantErr %v", tt.kind, tt.mimeType, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMediaSendsCheckRecipientFirst(t *testing.T) {
+	orig := config.WhatsappRecipientDeny
+	defer func() { config.WhatsappRecipientDeny = orig }()
+	config.WhatsappRecipientDeny = []string{"628111@s.whatsapp.net"}
+
+	// No client is connected, so only the policy check can answer.
+	jid := types.NewJID("628111", types.DefaultUserServer)
+	sends := map[string]func() error{
+		"audio": func() error {
+			_, err := SendAudioMessage(context.Background(), jid, []byte("ogg"), "audio/ogg", false)
+			return err
+		},
+		"document": func() error {
+			_, err := SendDocumentMessage(context.Background(), jid, []byte("pdf"), "application/pdf", "a.pdf", "", false, false, nil)
+			return err
+		},
+		"video": func() error {
+			_, err := SendVideoMessage(context.Background(), jid, []byte("mp4"), "video/mp4", "a.mp4", "", false, false, false)
+			return err
+		},
+		"image": func() error {
+			_, err := SendImageMessage(context.Background(), jid, []byte("jpeg"), "image/jpeg", "a.jpg", "", false, false)
+			return err
+		},
+	}
+	for kind, send := range sends {
+		var denied pkgError.RecipientNotAllowed
+		if err := send(); !errors.As(err, &denied) {
+			t.Errorf("%s send error = %v, want RecipientNotAllowed", kind, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+const documentThumbnailSize = "320"
+
+// DocumentThumbnail renders the first page of a PDF as a JPEG preview with
+// poppler's pdftoppm. It returns nil when rendering is disabled, the document
+// is not a PDF, or the renderer is not installed, so callers fall back to the
+// generic file icon.
+func DocumentThumbnail(ctx context.Context, documentData []byte, mimeType string) []byte {
+	if !config.WhatsappDocumentThumbnail || mimeType != "application/pdf" {
+		return nil
+	}
+	renderer, err := exec.LookPath("pdftoppm")
+	if err != nil {
+		logrus.Debug("pdftoppm not installed, sending document without thumbnail")
+		return nil
+	}
+
+	workDir, err := os.MkdirTemp("", "doc-thumbnail")
+	if err != nil {
+		logrus.Warnf("Failed to create thumbnail directory: %v", err)
+		return nil
+	}
+	defer os.RemoveAll(workDir)
+
+	inputPath := filepath.Join(workDir, "document.pdf")
+	if err := os.WriteFile(inputPath, documentData, 0600); err != nil {
+		logrus.Warnf("Failed to write document for thumbnail: %v", err)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	outputPrefix := filepath.Join(workDir, "thumbnail")
+	cmd := exec.CommandContext(ctx, renderer, "-jpeg", "-f", "1", "-l", "1", "-singlefile", "-scale-to", documentThumbnailSize, inputPath, outputPrefix)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logrus.Warnf("Failed to render document thumbnail: %v: %s", err, output)
+		return nil
+	}
+
+	thumbnail, err := os.ReadFile(outputPrefix + ".jpg")
+	if err != nil {
+		logrus.Warnf("Failed to read document thumbnail: %v", err)
+		return nil
+	}
+	return thumbnail
+}
+
+// thumbnailSize returns the dimensions of an encoded thumbnail image.
+func thumbnailSize(thumbnail []byte) (width, height uint32, ok bool) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(thumbnail))
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint32(cfg.Width), uint32(cfg.Height), true
+}
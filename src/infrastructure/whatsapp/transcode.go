@@ -0,0 +1,159 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	audioTranscodeTimeout = time.Minute
+	videoTranscodeTimeout = 5 * time.Minute
+
+	// transcodedAudioBitrate is the bitrate of transcoded video soundtracks,
+	// which is subtracted from the budget of the size cap.
+	transcodedAudioBitrate = 128000
+	// minVideoBitrate keeps very long videos watchable; they may overshoot the cap.
+	minVideoBitrate = 200000
+)
+
+// TranscodeAudio converts audio to OGG/Opus, the format WhatsApp plays voice
+// notes in. Audio is returned unchanged when transcoding is disabled, it is
+// already OGG, or ffmpeg is not installed or fails.
+func TranscodeAudio(ctx context.Context, data []byte, mimeType string) ([]byte, string) {
+	if !config.WhatsappTranscodeMedia {
+		return data, mimeType
+	}
+	if isOgg(data, mimeType) {
+		return data, "audio/ogg"
+	}
+	ctx, cancel := context.WithTimeout(ctx, audioTranscodeTimeout)
+	defer cancel()
+	output, err := runFFmpeg(ctx, data, "audio.ogg", func(input, output string) []string {
+		return []string{"-y", "-i", input, "-vn", "-c:a", "libopus", "-b:a", "64k", "-ar", "48000", "-ac", "1", "-f", "ogg", output}
+	})
+	if err != nil {
+		logrus.Warnf("Sending %s audio as is, transcoding failed: %v", mimeType, err)
+		return data, mimeType
+	}
+	logrus.Infof("Transcoded %s audio of %d bytes to OGG/Opus of %d bytes", mimeType, len(data), len(output))
+	return output, "audio/ogg"
+}
+
+// isOgg reports whether audio is in an OGG container. http.DetectContentType
+// names OGG application/ogg, so the "OggS" page header is checked as well.
+func isOgg(data []byte, mimeType string) bool {
+	switch strings.TrimSpace(strings.Split(mimeType, ";")[0]) {
+	case "audio/ogg", "application/ogg":
+		return true
+	}
+	return bytes.HasPrefix(data, []byte("OggS"))
+}
+
+// TranscodeVideo converts video to an H.264/AAC MP4 that plays on every
+// WhatsApp client, at a bitrate fitting config.WhatsappTranscodeVideoMaxBytes.
+// MP4 videos within the cap are assumed to play and are left alone. Video is
+// returned unchanged when transcoding is disabled, or ffmpeg is not installed
+// or fails.
+func TranscodeVideo(ctx context.Context, data []byte, mimeType string) ([]byte, string) {
+	if !config.WhatsappTranscodeMedia || !needsVideoTranscode(mimeType, len(data), config.WhatsappTranscodeVideoMaxBytes) {
+		return data, mimeType
+	}
+	ctx, cancel := context.WithTimeout(ctx, videoTranscodeTimeout)
+	defer cancel()
+	output, err := runFFmpeg(ctx, data, "video.mp4", func(input, output string) []string {
+		args := []string{"-y", "-i", input,
+			"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main", "-pix_fmt", "yuv420p",
+			"-vf", "scale=w='min(1280,iw)':h=-2",
+			"-c:a", "aac", "-b:a", strconv.Itoa(transcodedAudioBitrate),
+			"-movflags", "+faststart"}
+		if bitrate := videoBitrate(probeDuration(ctx, input), config.WhatsappTranscodeVideoMaxBytes); bitrate > 0 {
+			rate := strconv.Itoa(bitrate)
+			args = append(args, "-b:v", rate, "-maxrate", rate, "-bufsize", strconv.Itoa(2*bitrate))
+		} else {
+			args = append(args, "-crf", "28")
+		}
+		return append(args, output)
+	})
+	if err != nil {
+		logrus.Warnf("Sending %s video as is, transcoding failed: %v", mimeType, err)
+		return data, mimeType
+	}
+	logrus.Infof("Transcoded %s video of %d bytes to MP4 of %d bytes", mimeType, len(data), len(output))
+	return output, "video/mp4"
+}
+
+// needsVideoTranscode reports whether a video is in another container than MP4
+// or larger than maxBytes.
+func needsVideoTranscode(mimeType string, size int, maxBytes int64) bool {
+	return mimeType != "video/mp4" || (maxBytes > 0 && int64(size) > maxBytes)
+}
+
+// videoBitrate returns the video bitrate that makes a video of the given
+// duration fit maxBytes with some headroom for the container, or 0 when there
+// is no cap or the duration is unknown.
+func videoBitrate(duration time.Duration, maxBytes int64) int {
+	if maxBytes <= 0 || duration <= 0 {
+		return 0
+	}
+	total := float64(maxBytes*8) * 0.95 / duration.Seconds()
+	return max(int(total)-transcodedAudioBitrate, minVideoBitrate)
+}
+
+// probeDuration returns the duration of a media file with ffprobe, or 0 when
+// it cannot be read.
+func probeDuration(ctx context.Context, path string) time.Duration {
+	probe, err := exec.LookPath("ffprobe")
+	if err != nil {
+		return 0
+	}
+	output, err := exec.CommandContext(ctx, probe, "-v", "error", "-show_entries", "format=duration", "-of", "default=noprint_wrappers=1:nokey=1", path).Output()
+	if err != nil {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// runFFmpeg writes data to a work directory, runs ffmpeg with the arguments
+// built for the input and output paths and returns the output file.
+func runFFmpeg(ctx context.Context, data []byte, outputName string, args func(input, output string) []string) ([]byte, error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not installed")
+	}
+	workDir, err := os.MkdirTemp("", "transcode")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+
+	input := filepath.Join(workDir, "input")
+	if err := os.WriteFile(input, data, 0600); err != nil {
+		return nil, err
+	}
+	output := filepath.Join(workDir, outputName)
+	if out, err := exec.CommandContext(ctx, ffmpeg, args(input, output)...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, lastLine(out))
+	}
+	return os.ReadFile(output)
+}
+
+// lastLine returns the last non-empty line of command output, where ffmpeg
+// prints the reason it failed.
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return lines[len(lines)-1]
+}
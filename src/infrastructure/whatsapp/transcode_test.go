@@ -0,0 +1,79 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func TestNeedsVideoTranscode(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		size     int
+		maxBytes int64
+		want     bool
+	}{
+		{"video/mp4", 1000, 16000000, false},
+		{"video/mp4", 20000000, 16000000, true},
+		{"video/mp4", 20000000, 0, false},
+		{"video/quicktime", 1000, 16000000, true},
+		{"video/webm", 1000, 0, true},
+	}
+	for _, tt := range tests {
+		if got := needsVideoTranscode(tt.mimeType, tt.size, tt.maxBytes); got != tt.want {
+			t.Errorf("needsVideoTranscode(%q, %d, %d) = %v, want %v", tt.mimeType, tt.size, tt.maxBytes, got, tt.want)
+		}
+	}
+}
+
+func TestVideoBitrate(t *testing.T) {
+	// 16 MB over 100 seconds leaves about 1.2 Mbit/s for the picture.
+	if got := videoBitrate(100*time.Second, 16000000); got < 1000000 || got > 1100000 {
+		t.Errorf("videoBitrate(100s, 16MB) = %d, want about 1.09 Mbit/s", got)
+	}
+	if got := videoBitrate(10*time.Hour, 16000000); got != minVideoBitrate {
+		t.Errorf("videoBitrate(10h, 16MB) = %d, want the %d floor", got, minVideoBitrate)
+	}
+	if got := videoBitrate(0, 16000000); got != 0 {
+		t.Errorf("videoBitrate with an unknown duration = %d, want 0", got)
+	}
+	if got := videoBitrate(time.Minute, 0); got != 0 {
+		t.Errorf("videoBitrate without a cap = %d, want 0", got)
+	}
+}
+
+func TestIsOgg(t *testing.T) {
+	tests := []struct {
+		data     string
+		mimeType string
+		want     bool
+	}{
+		{"....", "audio/ogg", true},
+		{"....", "audio/ogg; codecs=opus", true},
+		{"....", "application/ogg", true},
+		{"OggS\x00\x02", "application/octet-stream", true},
+		{"RIFF....WAVE", "audio/wav", false},
+		{"ID3\x04", "audio/mpeg", false},
+	}
+	for _, tt := range tests {
+		if got := isOgg([]byte(tt.data), tt.mimeType); got != tt.want {
+			t.Errorf("isOgg(%q, %q) = %v, want %v", tt.data, tt.mimeType, got, tt.want)
+		}
+	}
+}
+
+func TestTranscodeDisabled(t *testing.T) {
+	enabled := config.WhatsappTranscodeMedia
+	config.WhatsappTranscodeMedia = false
+	defer func() { config.WhatsappTranscodeMedia = enabled }()
+
+	data := []byte("RIFF....WAVE")
+	if got, mimeType := TranscodeAudio(context.Background(), data, "audio/wav"); string(got) != string(data) || mimeType != "audio/wav" {
+		t.Errorf("TranscodeAudio() while disabled = %q, %q; want the input", got, mimeType)
+	}
+	if got, mimeType := TranscodeVideo(context.Background(), data, "video/webm"); string(got) != string(data) || mimeType != "video/webm" {
+		t.Errorf("TranscodeVideo() while disabled = %q, %q; want the input", got, mimeType)
+	}
+}
@@ -0,0 +1,48 @@
+package whatsapp
+
+import (
+	"sort"
+
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// unknownMessagePayload describes a message of a type the webhook does not
+// recognize, so it can be reported and supported: the fields set on it, by
+// their proto name, and the numbers of fields too new for the bundled proto
+// definitions, which are the likely new message type.
+func unknownMessagePayload(msg *waProto.Message) map[string]interface{} {
+	fields := []string{}
+	reflected := msg.ProtoReflect()
+	reflected.Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, string(field.Name()))
+		return true
+	})
+	sort.Strings(fields)
+
+	payload := map[string]interface{}{"fields": fields}
+	if numbers := unknownFieldNumbers(reflected.GetUnknown()); len(numbers) > 0 {
+		payload["unknown_field_numbers"] = numbers
+	}
+	return payload
+}
+
+// unknownFieldNumbers lists the distinct field numbers in raw proto bytes, in
+// order of appearance.
+func unknownFieldNumbers(raw []byte) []int32 {
+	var numbers []int32
+	seen := make(map[protowire.Number]bool)
+	for len(raw) > 0 {
+		number, _, n := protowire.ConsumeField(raw)
+		if n < 0 {
+			break
+		}
+		if !seen[number] {
+			seen[number] = true
+			numbers = append(numbers, int32(number))
+		}
+		raw = raw[n:]
+	}
+	return numbers
+}
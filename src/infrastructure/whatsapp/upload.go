@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+)
+
+// uploadCacheTTL bounds how long an uploaded blob is reused. WhatsApp keeps
+// media on its CDN for a while, but direct paths eventually stop resolving.
+const uploadCacheTTL = time.Hour
+
+// uploadCache remembers upload results by content hash and media type, so the
+// same file sent to many recipients is encrypted and uploaded only once.
+var uploadCache = &mediaUploadCache{entries: map[string]cachedUpload{}}
+
+type cachedUpload struct {
+	response whatsmeow.UploadResponse
+	expires  time.Time
+}
+
+type mediaUploadCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedUpload
+}
+
+func (c *mediaUploadCache) get(key string) (whatsmeow.UploadResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return whatsmeow.UploadResponse{}, false
+	}
+	return entry.response, true
+}
+
+func (c *mediaUploadCache) put(key string, response whatsmeow.UploadResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedUpload{response: response, expires: now.Add(uploadCacheTTL)}
+}
+
+// UploadMedia uploads data to WhatsApp, reusing the previous upload of identical
+// content of the same media type while it is still fresh.
+func UploadMedia(ctx context.Context, waCli *whatsmeow.Client, data []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+	sum := sha256.Sum256(data)
+	key := string(mediaType) + ":" + hex.EncodeToString(sum[:])
+	if uploaded, ok := uploadCache.get(key); ok {
+		logrus.Debugf("Reusing uploaded %s media %s", mediaType, key)
+		return uploaded, nil
+	}
+
+	uploaded, err := waCli.Upload(ctx, data, mediaType)
+	if err != nil {
+		return uploaded, err
+	}
+	uploadCache.put(key, uploaded)
+	return uploaded, nil
+}
@@ -5,14 +5,13 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"mime"
 	"os"
 	"regexp"
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	waProto "go.mau.fi/whatsmeow/proto/waE2E"
@@ -21,8 +20,23 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 )
 
+// MediaTooLargeError is returned by ExtractMedia when the declared size of the
+// media exceeds config.WhatsappMaxInboundMediaSize; nothing is downloaded.
+type MediaTooLargeError struct {
+	Size uint64
+}
+
+func (e *MediaTooLargeError) Error() string {
+	return fmt.Sprintf("media size %d exceeds the inbound limit of %d bytes", e.Size, config.WhatsappMaxInboundMediaSize)
+}
+
+// ErrMediaKeyMissing is returned by ExtractMedia for media without a media key,
+// which is how WhatsApp delivers view-once media that was already opened or expired.
+var ErrMediaKeyMissing = errors.New("media key missing, the media was already opened or has expired")
+
 func ExtractMedia(ctx context.Context, storageLocation string, mediaFile whatsmeow.DownloadableMessage) (ExtractedMedia, error) {
 	var extractedMedia ExtractedMedia
 	if mediaFile == nil {
@@ -30,8 +44,17 @@ func ExtractMedia(ctx context.Context, storageLocation string, mediaFile whatsme
 		return extractedMedia, nil
 	}
 
-	waCli := GetWaCli()
-	data, err := waCli.Download(ctx, mediaFile)
+	if sized, ok := mediaFile.(interface{ GetFileLength() uint64 }); ok && config.WhatsappMaxInboundMediaSize > 0 {
+		if size := sized.GetFileLength(); size > uint64(config.WhatsappMaxInboundMediaSize) {
+			return extractedMedia, &MediaTooLargeError{Size: size}
+		}
+	}
+
+	if keyed, ok := mediaFile.(interface{ GetMediaKey() []byte }); ok && len(keyed.GetMediaKey()) == 0 {
+		return extractedMedia, ErrMediaKeyMissing
+	}
+
+	data, err := downloadWithRetry(ctx, mediaFile)
 	if err != nil {
 		return extractedMedia, err
 	}
@@ -57,14 +80,10 @@ func ExtractMedia(ctx context.Context, storageLocation string, mediaFile whatsme
 		extractedMedia.Caption = media.GetCaption()
 	}
 
-	var extension string
-	if ext, err := mime.ExtensionsByType(extractedMedia.MimeType); err == nil && len(ext) > 0 {
-		extension = ext[0]
-	} else if parts := strings.Split(extractedMedia.MimeType, "/"); len(parts) > 1 {
-		extension = "." + parts[len(parts)-1]
-	}
-
-	extractedMedia.MediaPath = fmt.Sprintf("%s/%d-%s%s", storageLocation, time.Now().Unix(), uuid.NewString(), extension)
+	// Files are named after their content hash, so the same media is stored once
+	// and the extension always matches the mime type.
+	sum := sha256.Sum256(data)
+	extractedMedia.MediaPath = fmt.Sprintf("%s/%s%s", storageLocation, hex.EncodeToString(sum[:]), utils.MediaExtension(extractedMedia.MimeType))
 	err = os.WriteFile(extractedMedia.MediaPath, data, 0600)
 	if err != nil {
 		return extractedMedia, err
@@ -72,8 +91,104 @@ func ExtractMedia(ctx context.Context, storageLocation string, mediaFile whatsme
 	return extractedMedia, nil
 }
 
+const mediaDownloadAttempts = 3
+
+// downloadWithRetry retries transient download failures with exponential backoff.
+func downloadWithRetry(ctx context.Context, mediaFile whatsmeow.DownloadableMessage) ([]byte, error) {
+	waCli := GetWaCli()
+	if waCli == nil {
+		return nil, fmt.Errorf("WhatsApp client not initialized")
+	}
+	backoff := 500 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		data, err := waCli.Download(ctx, mediaFile)
+		if err == nil || attempt == mediaDownloadAttempts || !isTransientDownloadError(err) {
+			return data, err
+		}
+
+		logrus.Warnf("Media download attempt %d failed, retrying in %s: %v", attempt, backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientDownloadError reports whether retrying the download may succeed.
+// Expired URLs and integrity errors will fail the same way every time.
+func isTransientDownloadError(err error) bool {
+	permanent := []error{
+		context.Canceled,
+		whatsmeow.ErrMediaDownloadFailedWith403,
+		whatsmeow.ErrMediaDownloadFailedWith404,
+		whatsmeow.ErrMediaDownloadFailedWith410,
+		whatsmeow.ErrNoURLPresent,
+		whatsmeow.ErrFileLengthMismatch,
+		whatsmeow.ErrInvalidMediaHMAC,
+		whatsmeow.ErrInvalidMediaEncSHA256,
+		whatsmeow.ErrInvalidMediaSHA256,
+		whatsmeow.ErrUnknownMediaType,
+		whatsmeow.ErrNothingDownloadableFound,
+	}
+	for _, target := range permanent {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// unwrapViewOnce removes view-once and ephemeral containers left in the message.
+// whatsmeow unwraps them in a fixed order, so a view-once v2 wrapped around an
+// ephemeral message keeps its media hidden from the Get*Message accessors.
+func unwrapViewOnce(evt *events.Message) {
+	for {
+		switch msg := evt.Message; {
+		case msg.GetEphemeralMessage().GetMessage() != nil:
+			evt.Message = msg.GetEphemeralMessage().GetMessage()
+			evt.IsEphemeral = true
+		case msg.GetViewOnceMessage().GetMessage() != nil:
+			evt.Message = msg.GetViewOnceMessage().GetMessage()
+			evt.IsViewOnce = true
+		case msg.GetViewOnceMessageV2().GetMessage() != nil:
+			evt.Message = msg.GetViewOnceMessageV2().GetMessage()
+			evt.IsViewOnce = true
+			evt.IsViewOnceV2 = true
+		case msg.GetViewOnceMessageV2Extension().GetMessage() != nil:
+			evt.Message = msg.GetViewOnceMessageV2Extension().GetMessage()
+			evt.IsViewOnce = true
+			evt.IsViewOnceV2 = true
+			evt.IsViewOnceV2Extension = true
+		default:
+			return
+		}
+	}
+}
+
+// viewOnceType names the view-once container of a message: "v1", "v2" or
+// "v2_extension" (used for voice notes), or "" for regular messages.
+func viewOnceType(evt *events.Message) string {
+	switch {
+	case evt.IsViewOnceV2Extension:
+		return "v2_extension"
+	case evt.IsViewOnceV2:
+		return "v2"
+	case evt.IsViewOnce:
+		return "v1"
+	}
+	return ""
+}
+
+// SanitizePhone completes a bare recipient into a JID string, see ResolveRecipientJID.
+// Input it can not resolve falls back to appending the user or group suffix.
 func SanitizePhone(phone *string) {
 	if phone != nil && len(*phone) > 0 && !strings.Contains(*phone, "@") {
+		if jid, err := ResolveRecipientJID(*phone); err == nil {
+			*phone = jid.String()
+			return
+		}
 		if len(*phone) <= 15 {
 			*phone = fmt.Sprintf("%s%s", *phone, config.WhatsappTypeUser)
 		} else {
@@ -136,6 +251,60 @@ func ParseJID(arg string) (types.JID, error) {
 	return recipient, nil
 }
 
+// legacyGroupID matches the "<creator>-<timestamp>" IDs of groups created
+// before the 120363... format: the creator's full phone number and a 10-digit
+// unix timestamp, so a phone number written with a dash is not taken for one.
+var legacyGroupID = regexp.MustCompile(`^[0-9]{8,15}-[0-9]{10}$`)
+
+// ResolveRecipientJID turns what clients send as a recipient into a JID: a bare
+// phone number (with or without "+" and separators), a user JID (the legacy
+// "@c.us" included), a LID, a group JID, bare group IDs, newsletters and
+// broadcast lists. Device parts of user JIDs are dropped since messages are
+// addressed to the account.
+func ResolveRecipientJID(input string) (types.JID, error) {
+	input = strings.TrimPrefix(strings.TrimSpace(input), "+")
+	if input == "" {
+		return types.JID{}, pkgError.ErrInvalidJID
+	}
+
+	if !strings.ContainsRune(input, '@') {
+		if legacyGroupID.MatchString(input) || (len(input) > 15 && isDigits(input)) {
+			return types.NewJID(input, types.GroupServer), nil
+		}
+		phone, err := utils.NormalizePhone(input)
+		if err != nil {
+			return types.JID{}, pkgError.InvalidJID(err.Error())
+		}
+		return types.NewJID(phone, types.DefaultUserServer), nil
+	}
+
+	jid, err := types.ParseJID(input)
+	if err != nil || jid.User == "" {
+		return types.JID{}, pkgError.ErrInvalidJID
+	}
+	switch jid.Server {
+	case types.LegacyUserServer, types.DefaultUserServer:
+		if !isDigits(jid.User) {
+			return types.JID{}, pkgError.ErrInvalidJID
+		}
+		return types.NewJID(jid.User, types.DefaultUserServer), nil
+	case types.HiddenUserServer:
+		return jid.ToNonAD(), nil
+	case types.GroupServer, types.NewsletterServer, types.BroadcastServer:
+		return jid, nil
+	}
+	return types.JID{}, pkgError.InvalidJID(fmt.Sprintf("unsupported recipient server %q", jid.Server))
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
+
 func IsOnWhatsapp(waCli *whatsmeow.Client, jid string) bool {
 	if strings.Contains(jid, "@s.whatsapp.net") {
 		data, err := waCli.IsOnWhatsApp([]string{jid})
@@ -155,10 +324,14 @@ func IsOnWhatsapp(waCli *whatsmeow.Client, jid string) bool {
 
 func ValidateJidWithLogin(waCli *whatsmeow.Client, jid string) (types.JID, error) {
 	MustLogin(waCli)
-	if config.WhatsappAccountValidation && !IsOnWhatsapp(waCli, jid) {
+	recipient, err := ResolveRecipientJID(jid)
+	if err != nil {
+		return types.JID{}, err
+	}
+	if config.WhatsappAccountValidation && !IsOnWhatsapp(waCli, recipient.String()) {
 		return types.JID{}, pkgError.InvalidJID(fmt.Sprintf("Phone %s is not on WhatsApp", jid))
 	}
-	return ParseJID(jid)
+	return recipient, nil
 }
 
 func MustLogin(waCli *whatsmeow.Client) {
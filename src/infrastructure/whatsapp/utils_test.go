@@ -0,0 +1,45 @@
+package whatsapp
+
+import "testing"
+
+func TestResolveRecipientJID(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"bare number", "5511999999999", "5511999999999@s.whatsapp.net"},
+		{"leading plus", "+5511999999999", "5511999999999@s.whatsapp.net"},
+		{"formatted number", "+55 (11) 99999-9999", "5511999999999@s.whatsapp.net"},
+		{"user JID", "5511999999999@s.whatsapp.net", "5511999999999@s.whatsapp.net"},
+		{"user JID with plus", "+5511999999999@s.whatsapp.net", "5511999999999@s.whatsapp.net"},
+		{"user JID with device", "5511999999999:12@s.whatsapp.net", "5511999999999@s.whatsapp.net"},
+		{"legacy c.us JID", "5511999999999@c.us", "5511999999999@s.whatsapp.net"},
+		{"LID", "123456789012345@lid", "123456789012345@lid"},
+		{"LID with device", "123456789012345:3@lid", "123456789012345@lid"},
+		{"group JID", "120363025246125486@g.us", "120363025246125486@g.us"},
+		{"bare group ID", "120363025246125486", "120363025246125486@g.us"},
+		{"legacy group JID", "5511999999999-1600000000@g.us", "5511999999999-1600000000@g.us"},
+		{"bare legacy group ID", "5511999999999-1600000000", "5511999999999-1600000000@g.us"},
+		{"phone with a dash", "62812-3456789", "628123456789@s.whatsapp.net"},
+		{"newsletter", "120363144038483540@newsletter", "120363144038483540@newsletter"},
+		{"surrounding spaces", "  5511999999999  ", "5511999999999@s.whatsapp.net"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jid, err := ResolveRecipientJID(tt.input)
+			if err != nil {
+				t.Fatalf("ResolveRecipientJID(%q) failed: %v", tt.input, err)
+			}
+			if jid.String() != tt.want {
+				t.Fatalf("ResolveRecipientJID(%q) = %s, want %s", tt.input, jid.String(), tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "+", "@s.whatsapp.net", "55abc@s.whatsapp.net", "011999", "5511999999999@example.com", "not a number"} {
+		if jid, err := ResolveRecipientJID(input); err == nil {
+			t.Errorf("ResolveRecipientJID(%q) = %s, want an error", input, jid.String())
+		}
+	}
+}
@@ -3,28 +3,57 @@ package whatsapp
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func forwardToWebhook(ctx context.Context, evt *events.Message) error {
-	logrus.Info("Forwarding event to webhook:", config.WhatsappWebhook)
-	payload, err := createPayload(ctx, evt)
+	if webhookIgnored(evt) {
+		logrus.Debugf("Not forwarding message %s from %s in %s: ignored sender or chat", evt.Info.ID, evt.Info.Sender, evt.Info.Chat)
+		return nil
+	}
+	urls := utils.WebhookURLsForChat(evt.Info.Chat.String())
+	if len(urls) == 0 {
+		return nil
+	}
+
+	// Events can arrive before login completes (e.g. during history sync), in
+	// which case the own JID is unknown and self-detection is skipped.
+	var self *types.JID
+	if waCli := GetWaCli(); waCli != nil && waCli.Store != nil && waCli.Store.ID != nil {
+		self = waCli.Store.ID
+	} else {
+		logrus.Warnf("Forwarding message %s before the client is logged in", evt.Info.ID)
+	}
+
+	logrus.Info("Forwarding event to webhook:", urls)
+	payload, err := createPayload(ctx, evt, self)
 	if err != nil {
 		return err
 	}
 
-	for _, url := range config.WhatsappWebhook {
+	for _, url := range urls {
 		if err = SubmitWebhook(payload, url); err != nil {
 			return err
 		}
@@ -34,7 +63,22 @@ func forwardToWebhook(ctx context.Context, evt *events.Message) error {
 	return nil
 }
 
-func createPayload(ctx context.Context, evt *events.Message) (map[string]interface{}, error) {
+// webhookIgnored reports whether the sender or the chat of a message matches
+// config.WhatsappWebhookIgnore.
+func webhookIgnored(evt *events.Message) bool {
+	if len(config.WhatsappWebhookIgnore) == 0 {
+		return false
+	}
+	candidates := []types.JID{evt.Info.Sender, evt.Info.SenderAlt, evt.Info.Chat}
+	for _, jid := range candidates {
+		if !jid.IsEmpty() && utils.MatchesJIDPattern(jid.ToNonAD().String(), config.WhatsappWebhookIgnore) {
+			return true
+		}
+	}
+	return false
+}
+
+func createPayload(ctx context.Context, evt *events.Message, self *types.JID) (map[string]interface{}, error) {
 	message := buildEventMessage(evt)
 	waReaction := buildEventReaction(evt)
 	forwarded := buildForwarded(evt)
@@ -90,10 +134,17 @@ func createPayload(ctx context.Context, evt *events.Message) (map[string]interfa
 	}
 	if evt.IsViewOnce {
 		body["view_once"] = evt.IsViewOnce
+		body["view_once_type"] = viewOnceType(evt)
 	}
 	if forwarded {
 		body["forwarded"] = forwarded
 	}
+	if adReferral := buildAdReferral(evt); adReferral != nil {
+		body["ad_referral"] = adReferral
+	}
+	if request, ok := requestResponse(evt.Info.ID); ok {
+		body["request_id"] = request.messageID
+	}
 	if timestamp := evt.Info.Timestamp.Format(time.RFC3339); timestamp != "" {
 		body["timestamp"] = timestamp
 	}
@@ -104,7 +155,7 @@ func createPayload(ctx context.Context, evt *events.Message) (map[string]interfa
 	}
 	IsGroup := strings.Contains(evt.Info.Chat.String(), "@g.us")
 	body["IsGroup"] = IsGroup
-	if IsGroup {
+	if IsGroup && self != nil {
 		GroupName, err := GetGroupName(ctx, jid)
 		if err != nil {
 			logrus.Errorf("Failed to get group name: %v", err)
@@ -113,14 +164,20 @@ func createPayload(ctx context.Context, evt *events.Message) (map[string]interfa
 		}
 	}
 
-	waCli := GetWaCli()
 	MyNumber := false
-	if waCli != nil && waCli.Store.ID != nil {
-		MyNumber = extractPhoneNumber(evt.Info.SourceString()) == extractPhoneNumber(waCli.Store.ID.String())
+	if self != nil {
+		MyNumber = extractPhoneNumber(evt.Info.SourceString()) == extractPhoneNumber(self.String())
+	} else {
+		body["logged_in"] = false
 	}
 	body["MyNumber"] = MyNumber
 
 	body["Type"] = determineMessageType(evt, message.Text)
+	if body["Type"] == "unknown" {
+		unknown := unknownMessagePayload(evt.Message)
+		logrus.Infof("Message %s has an unrecognized type, fields: %v", evt.Info.ID, unknown["fields"])
+		body["unknown"] = unknown
+	}
 
 	body["Port"] = config.AppPort
 
@@ -158,28 +215,13 @@ func createPayload(ctx context.Context, evt *events.Message) (map[string]interfa
 	}
 
 	if audioMedia := evt.Message.GetAudioMessage(); audioMedia != nil {
-		path, err := ExtractMedia(ctx, config.PathMedia, audioMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download audio: %v", err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download audio: %v", err))
-		}
-		body["audio"] = path
+		body["audio"] = webhookMedia(ctx, evt, "audio", audioMedia)
 	}
 	if documentMessage := evt.Message.GetDocumentMessage(); documentMessage != nil {
-		path, err := ExtractMedia(ctx, config.PathMedia, documentMessage)
-		if err != nil {
-			logrus.Errorf("Failed to download document: %v", err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download document: %v", err))
-		}
-		body["document"] = path
+		body["document"] = webhookMedia(ctx, evt, "document", documentMessage)
 	}
 	if imageMedia := evt.Message.GetImageMessage(); imageMedia != nil {
-		path, err := ExtractMedia(ctx, config.PathMedia, imageMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download image: %v", err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download image: %v", err))
-		}
-		body["image"] = path
+		body["image"] = webhookMedia(ctx, evt, "image", imageMedia)
 	}
 	if listMessage := evt.Message.GetListMessage(); listMessage != nil {
 		body["list"] = listMessage
@@ -191,41 +233,229 @@ func createPayload(ctx context.Context, evt *events.Message) (map[string]interfa
 		body["location"] = locationMessage
 	}
 	if orderMessage := evt.Message.GetOrderMessage(); orderMessage != nil {
-		body["order"] = orderMessage
+		body["order"] = orderPayload(orderMessage)
+	}
+	if response := flowResponse(evt.Message); response != nil {
+		body["flow_response"] = flowResponsePayload(response)
+	}
+	if response := buttonResponse(evt.Message); response != nil {
+		body["button_response"] = buttonResponsePayload(response)
+	}
+	if paymentInvite := evt.Message.GetPaymentInviteMessage(); paymentInvite != nil {
+		body["payment"] = paymentInvitePayload(paymentInvite)
+	}
+	if paymentRequest := evt.Message.GetRequestPaymentMessage(); paymentRequest != nil {
+		body["payment"] = requestPaymentPayload(paymentRequest)
 	}
 	if stickerMedia := evt.Message.GetStickerMessage(); stickerMedia != nil {
-		path, err := ExtractMedia(ctx, config.PathMedia, stickerMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download sticker: %v", err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download sticker: %v", err))
-		}
-		body["sticker"] = path
+		body["sticker"] = webhookMedia(ctx, evt, "sticker", stickerMedia)
 	}
 	if videoMedia := evt.Message.GetVideoMessage(); videoMedia != nil {
-		path, err := ExtractMedia(ctx, config.PathMedia, videoMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download video: %v", err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download video: %v", err))
-		}
-		body["video"] = path
+		body["video"] = webhookMedia(ctx, evt, "video", videoMedia)
 	}
 	if ptvMedia := evt.Message.GetPtvMessage(); ptvMedia != nil {
-		path, err := ExtractMedia(ctx, config.PathMedia, ptvMedia)
-		if err != nil {
-			logrus.Errorf("Failed to download PTV video: %v", err)
-			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download PTV video: %v", err))
-		}
-		body["video"] = path
+		body["video"] = webhookMedia(ctx, evt, "PTV video", ptvMedia)
+	}
+
+	if config.WhatsappWebhookIncludeRaw {
+		body["raw"] = rawWebhookPayload(evt)
 	}
 
 	return body, nil
 }
 
+// rawWebhookPayload exposes the untouched message proto and info for consumers
+// needing fields the curated payload leaves out.
+func rawWebhookPayload(evt *events.Message) map[string]interface{} {
+	raw := map[string]interface{}{"info": evt.Info}
+	message, err := protojson.Marshal(evt.Message)
+	if err != nil {
+		logrus.Warnf("Failed to marshal raw message %s: %v", evt.Info.ID, err)
+		return raw
+	}
+	raw["message"] = json.RawMessage(message)
+	return raw
+}
+
+// mediaDownloadEnabled reports whether inbound media with the given label is
+// downloaded for webhooks.
+func mediaDownloadEnabled(label string) bool {
+	switch label {
+	case "image":
+		return config.WhatsappDownloadImage
+	case "video", "PTV video":
+		return config.WhatsappDownloadVideo
+	case "audio":
+		return config.WhatsappDownloadAudio
+	case "document":
+		return config.WhatsappDownloadDocument
+	case "sticker":
+		return config.WhatsappDownloadSticker
+	}
+	return true
+}
+
+// webhookMedia downloads a media attachment for the webhook payload, or only
+// describes it when downloads of its type are disabled. The media can still be
+// fetched later through media_url while chat storage keeps its reference.
+func webhookMedia(ctx context.Context, evt *events.Message, label string, media whatsmeow.DownloadableMessage) any {
+	if mediaDownloadEnabled(label) {
+		return extractWebhookMedia(ctx, label, media)
+	}
+	skipped := map[string]any{
+		"skipped": true,
+		"reason":  "download_disabled",
+	}
+	if sized, ok := media.(interface{ GetFileLength() uint64 }); ok {
+		skipped["size"] = sized.GetFileLength()
+	}
+	if typed, ok := media.(interface{ GetMimetype() string }); ok {
+		skipped["mime_type"] = typed.GetMimetype()
+	}
+	if config.WhatsappChatStorage && evt.Info.ID != "" {
+		skipped["media_url"] = fmt.Sprintf("/chat/%s/message/%s/media", evt.Info.Chat.String(), evt.Info.ID)
+	}
+	return skipped
+}
+
+// extractWebhookMedia downloads a media attachment for the webhook payload.
+// Media above the inbound size cap is reported as skipped instead of downloaded,
+// and a failed download is flagged so the notification is still delivered. The
+// download is abandoned after config.WhatsappMediaDownloadTimeoutSeconds so one
+// slow attachment cannot hold up the event loop.
+func extractWebhookMedia(ctx context.Context, label string, media whatsmeow.DownloadableMessage) any {
+	if timeout := config.WhatsappMediaDownloadTimeoutSeconds; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+	path, err := ExtractMedia(ctx, config.PathMedia, media)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logrus.Warnf("Abandoned %s download after %d seconds: %v", label, config.WhatsappMediaDownloadTimeoutSeconds, err)
+			return map[string]any{
+				"download_failed": true,
+				"reason":          "download_timeout",
+			}
+		}
+		var tooLarge *MediaTooLargeError
+		if errors.As(err, &tooLarge) {
+			logrus.Infof("Skipping %s download: %v", label, err)
+			return map[string]any{
+				"skipped": true,
+				"reason":  "too_large",
+				"size":    tooLarge.Size,
+			}
+		}
+		if errors.Is(err, ErrMediaKeyMissing) {
+			logrus.Infof("Skipping %s download: %v", label, err)
+			return map[string]any{
+				"skipped": true,
+				"reason":  "view_once_unavailable",
+			}
+		}
+		logrus.Errorf("Failed to download %s: %v", label, err)
+		return map[string]any{
+			"download_failed": true,
+			"error":           err.Error(),
+		}
+	}
+	inlineWebhookMedia(&path)
+	return path
+}
+
+// inlineWebhookMedia adds the downloaded file as a base64 data URI when it is no
+// larger than the configured inline limit, sparing consumers a second request.
+func inlineWebhookMedia(media *ExtractedMedia) {
+	maxBytes := config.WhatsappWebhookInlineMediaMaxBytes
+	if maxBytes <= 0 || media.MediaPath == "" {
+		return
+	}
+	info, err := os.Stat(media.MediaPath)
+	if err != nil || info.Size() > maxBytes {
+		return
+	}
+	data, err := os.ReadFile(media.MediaPath)
+	if err != nil {
+		logrus.Warnf("Failed to read %s for inlining: %v", media.MediaPath, err)
+		return
+	}
+	mimeType := strings.ReplaceAll(media.MimeType, " ", "")
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	media.Base64 = "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+func orderPayload(order *waProto.OrderMessage) map[string]interface{} {
+	return map[string]interface{}{
+		"order_id":   order.GetOrderID(),
+		"title":      order.GetOrderTitle(),
+		"item_count": order.GetItemCount(),
+		"total":      float64(order.GetTotalAmount1000()) / 1000,
+		"currency":   order.GetTotalCurrencyCode(),
+		"status":     strings.ToLower(order.GetStatus().String()),
+		"seller_jid": order.GetSellerJID(),
+		"message":    order.GetMessage(),
+	}
+}
+
+// paymentInvitePayload covers invites to set up payments; they carry no amount.
+func paymentInvitePayload(invite *waProto.PaymentInviteMessage) map[string]interface{} {
+	return map[string]interface{}{
+		"amount":       nil,
+		"currency":     "",
+		"note":         "",
+		"expiry":       paymentExpiry(invite.GetExpiryTimestamp()),
+		"service_type": strings.ToLower(invite.GetServiceType().String()),
+	}
+}
+
+func requestPaymentPayload(request *waProto.RequestPaymentMessage) map[string]interface{} {
+	amount := float64(request.GetAmount1000()) / 1000
+	currency := request.GetCurrencyCodeIso4217()
+	if money := request.GetAmount(); money != nil {
+		amount = float64(money.GetValue()) / math.Pow10(int(money.GetOffset()))
+		if money.GetCurrencyCode() != "" {
+			currency = money.GetCurrencyCode()
+		}
+	}
+
+	note := request.GetNoteMessage().GetConversation()
+	if note == "" {
+		note = request.GetNoteMessage().GetExtendedTextMessage().GetText()
+	}
+
+	return map[string]interface{}{
+		"amount":       amount,
+		"currency":     currency,
+		"note":         note,
+		"expiry":       paymentExpiry(request.GetExpiryTimestamp()),
+		"request_from": request.GetRequestFrom(),
+	}
+}
+
+func paymentExpiry(timestamp int64) string {
+	if timestamp <= 0 {
+		return ""
+	}
+	return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+}
+
 func getPollOptionTitle(ctx context.Context, evt *events.Message, option []byte) string {
 	return fmt.Sprintf("Option_%x", option)
 }
 
 func determineMessageType(evt *events.Message, text string) string {
+	if request, ok := requestResponse(evt.Info.ID); ok {
+		return request.kind + "_request_response"
+	}
+	if flowResponse(evt.Message) != nil {
+		return "flow_response"
+	}
+	if buttonResponse(evt.Message) != nil {
+		return "button_response"
+	}
 	if evt.Message.GetPtvMessage() != nil {
 		return "video_snapshot_message"
 	}
@@ -239,6 +469,9 @@ func determineMessageType(evt *events.Message, text string) string {
 		return "image_message"
 	}
 	if evt.Message.GetVideoMessage() != nil {
+		if evt.Message.GetVideoMessage().GetGifPlayback() {
+			return "gif_message"
+		}
 		return "video_message"
 	}
 	if evt.Message.GetDocumentMessage() != nil {
@@ -265,7 +498,7 @@ func determineMessageType(evt *events.Message, text string) string {
 	if evt.Message.GetOrderMessage() != nil {
 		return "order"
 	}
-	if evt.Message.GetPaymentInviteMessage() != nil {
+	if evt.Message.GetPaymentInviteMessage() != nil || evt.Message.GetRequestPaymentMessage() != nil {
 		return "payment"
 	}
 	if evt.Message.GetPollCreationMessageV3() != nil || evt.Message.GetPollCreationMessageV4() != nil || evt.Message.GetPollCreationMessageV5() != nil {
@@ -277,6 +510,9 @@ func determineMessageType(evt *events.Message, text string) string {
 	if evt.Message.GetReactionMessage() != nil {
 		return "reaction_message"
 	}
+	if _, edited := editedContent(evt); edited != nil {
+		return "edit_message"
+	}
 	if evt.Message.GetConversation() != "" || evt.Message.GetExtendedTextMessage() != nil {
 		urlRegex := regexp.MustCompile(`https?://[^\s]+`)
 		if urlRegex.MatchString(text) {
@@ -287,34 +523,269 @@ func determineMessageType(evt *events.Message, text string) string {
 	return "unknown"
 }
 
-func SubmitWebhook(payload map[string]interface{}, url string) error {
-	client := &http.Client{Timeout: 10 * time.Second}
+var (
+	webhookClient     *http.Client
+	webhookClientOnce sync.Once
+)
+
+// getWebhookClient returns the HTTP client shared by every webhook delivery so
+// connections to the same host are pooled. It is built on first use because the
+// configuration is only loaded once the command starts.
+func getWebhookClient() *http.Client {
+	webhookClientOnce.Do(func() {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConns = 100
+		transport.MaxIdleConnsPerHost = config.WhatsappWebhookMaxConnsPerHost
+		transport.MaxConnsPerHost = config.WhatsappWebhookMaxConnsPerHost
+		transport.IdleConnTimeout = 90 * time.Second
+
+		webhookClient = &http.Client{
+			Timeout:   time.Duration(config.WhatsappWebhookTimeoutSeconds) * time.Second,
+			Transport: transport,
+		}
+	})
+	return webhookClient
+}
 
+// SubmitWebhook delivers the payload with retries. A delivery that still fails
+// is kept in the dead-letter store so it can be replayed with ReplayWebhooks.
+func SubmitWebhook(payload map[string]interface{}, url string) error {
+	createdAt := time.Now()
+	payload = versionWebhookPayload(payload, config.WhatsappWebhookPayloadVersion)
 	postBody, err := json.Marshal(payload)
 	if err != nil {
 		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
 	}
+	if postBody, err = limitWebhookPayload(postBody, config.WhatsappWebhookMaxPayloadSize); err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("Failed to shrink body: %v", err))
+	}
+
+	if err := deliverWebhook(postBody, url); err != nil {
+		deadLetter := utils.DeadLetter{URL: url, Payload: postBody, CreatedAt: createdAt, Error: err.Error()}
+		if recordErr := utils.RecordDeadLetter(deadLetter); recordErr != nil {
+			logrus.Errorf("Failed to store undelivered webhook for %s: %v", url, recordErr)
+		}
+		return err
+	}
+	return nil
+}
+
+const (
+	webhookMaxTextLength = 1024
+	webhookMaxListLength = 20
+)
+
+// limitWebhookPayload shrinks a JSON body larger than maxBytes so consumers with
+// a body limit still get a valid event: inline base64 media is dropped (the media
+// path stays), long strings are cut and long lists trimmed. The body is flagged
+// with "truncated": true. A maxBytes of 0 disables the limit.
+func limitWebhookPayload(postBody []byte, maxBytes int) ([]byte, error) {
+	if maxBytes <= 0 || len(postBody) <= maxBytes {
+		return postBody, nil
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(postBody, &payload); err != nil {
+		return nil, err
+	}
+
+	steps := []func(any) any{dropInlineMedia, truncateWebhookText}
+	for _, step := range steps {
+		payload = step(payload).(map[string]any)
+		payload["truncated"] = true
+		shrunk, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		if len(shrunk) <= maxBytes {
+			return shrunk, nil
+		}
+		postBody = shrunk
+	}
+
+	logrus.Warnf("Webhook body is still %d bytes after truncation, limit is %d", len(postBody), maxBytes)
+	return postBody, nil
+}
+
+// dropInlineMedia removes base64 encoded media data from every object.
+func dropInlineMedia(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			if _, isString := nested.(string); isString && key == "base64" {
+				delete(typed, key)
+				continue
+			}
+			typed[key] = dropInlineMedia(nested)
+		}
+	case []any:
+		for i, nested := range typed {
+			typed[i] = dropInlineMedia(nested)
+		}
+	}
+	return value
+}
+
+// truncateWebhookText cuts long strings and trims long lists.
+func truncateWebhookText(value any) any {
+	switch typed := value.(type) {
+	case string:
+		if runes := []rune(typed); len(runes) > webhookMaxTextLength {
+			return string(runes[:webhookMaxTextLength]) + "…"
+		}
+	case map[string]any:
+		for key, nested := range typed {
+			typed[key] = truncateWebhookText(nested)
+		}
+	case []any:
+		if len(typed) > webhookMaxListLength {
+			typed = typed[:webhookMaxListLength]
+		}
+		for i, nested := range typed {
+			typed[i] = truncateWebhookText(nested)
+		}
+		return typed
+	}
+	return value
+}
 
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(postBody))
+// WebhookReplaySummary reports the outcome of ReplayWebhooks.
+type WebhookReplaySummary struct {
+	Total     int      `json:"total"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// ReplayWebhooks re-submits the dead letters created within [from, to] in their
+// original order, with the usual retry and backoff. Delivered letters are removed;
+// failed ones stay in the store for a later replay.
+func ReplayWebhooks(from, to time.Time) (WebhookReplaySummary, error) {
+	summary := WebhookReplaySummary{}
+	letters, err := utils.FindDeadLetters(from, to)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("Error when creating HTTP request: %v", err))
+		return summary, err
+	}
+
+	var delivered []string
+	for _, letter := range letters {
+		summary.Total++
+		if err := deliverWebhook(letter.Payload, letter.URL); err != nil {
+			summary.Failed++
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", letter.ID, err))
+			continue
+		}
+		summary.Succeeded++
+		delivered = append(delivered, letter.ID)
+	}
+
+	if err := utils.RemoveDeadLetters(delivered); err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// encodeWebhookBody converts the JSON body to the configured webhook format
+// and returns the bytes to send along with their content type.
+func encodeWebhookBody(postBody []byte, format string) ([]byte, string, error) {
+	switch format {
+	case "", "json":
+		return postBody, "application/json", nil
+	case "form":
+		decoder := json.NewDecoder(bytes.NewReader(postBody))
+		decoder.UseNumber()
+		var payload map[string]any
+		if err := decoder.Decode(&payload); err != nil {
+			return nil, "", err
+		}
+		values := url.Values{}
+		for key, value := range payload {
+			flattenFormValue(values, key, value)
+		}
+		return []byte(values.Encode()), "application/x-www-form-urlencoded", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported webhook format %q", format)
 	}
+}
 
-	secretKey := []byte(config.WhatsappWebhookSecret)
-	signature, err := getMessageDigestOrSignature(postBody, secretKey)
+// flattenFormValue adds value under key using bracket notation for nested
+// objects and lists, e.g. message[ID]=... and contact[0][vcard]=...
+func flattenFormValue(values url.Values, key string, value any) {
+	switch typed := value.(type) {
+	case map[string]any:
+		for child, childValue := range typed {
+			flattenFormValue(values, key+"["+child+"]", childValue)
+		}
+	case []any:
+		for i, item := range typed {
+			flattenFormValue(values, fmt.Sprintf("%s[%d]", key, i), item)
+		}
+	case nil:
+		values.Add(key, "")
+	default:
+		values.Add(key, fmt.Sprint(typed))
+	}
+}
+
+// webhookOwnHeaders are set by deliverWebhook itself and never taken from the
+// configured extra headers.
+var webhookOwnHeaders = map[string]struct{}{
+	"Content-Type":                 {},
+	"Content-Length":               {},
+	"X-Hub-Signature-256":          {},
+	"X-Hub-Signature-256-Previous": {},
+}
+
+func deliverWebhook(postBody []byte, url string) error {
+	client := getWebhookClient()
+
+	postBody, contentType, err := encodeWebhookBody(postBody, config.WhatsappWebhookFormat)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("Error when creating signature: %v", err))
+		return pkgError.WebhookError(fmt.Sprintf("Failed to encode body: %v", err))
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
+	secret, previousSecret := webhookSecrets()
+	// The signature covers the exact bytes sent, whatever the format.
+	signature, err := getMessageDigestOrSignature(postBody, []byte(secret))
+	if err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("Error when creating signature: %v", err))
+	}
+	// While a rotated secret is in its grace period, receivers still holding
+	// it can verify this signature instead.
+	var previousSignature string
+	if previousSecret != "" {
+		if previousSignature, err = getMessageDigestOrSignature(postBody, []byte(previousSecret)); err != nil {
+			return pkgError.WebhookError(fmt.Sprintf("Error when creating signature: %v", err))
+		}
+	}
 
 	var attempt int
 	var maxAttempts = 5
 	var sleepDuration = 1 * time.Second
 
 	for attempt = 0; attempt < maxAttempts; attempt++ {
-		if _, err = client.Do(req); err == nil {
+		// The request body is consumed by each attempt, so build a fresh request.
+		req, reqErr := http.NewRequest(http.MethodPost, url, bytes.NewReader(postBody))
+		if reqErr != nil {
+			return pkgError.WebhookError(fmt.Sprintf("Error when creating HTTP request: %v", reqErr))
+		}
+		for name, values := range utils.WebhookHeadersForURL(url) {
+			if _, protected := webhookOwnHeaders[name]; protected {
+				continue
+			}
+			req.Header[name] = values
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
+		if previousSignature != "" {
+			req.Header.Set("X-Hub-Signature-256-Previous", fmt.Sprintf("sha256=%s", previousSignature))
+		}
+
+		var resp *http.Response
+		if resp, err = client.Do(req); err == nil {
+			// Drain the body so the connection goes back to the pool.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
 			logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
 			return nil
 		}
@@ -0,0 +1,109 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNoWebhookForChat is returned by ResendMessageWebhook when no webhook
+// receives the events of the chat.
+var ErrNoWebhookForChat = errors.New("no webhook is configured for the chat")
+
+// WebhookResendSummary reports the outcome of ResendMessageWebhook.
+type WebhookResendSummary struct {
+	MessageID string   `json:"message_id"`
+	Total     int      `json:"total"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// ResendMessageWebhook submits the webhook of a stored message again, e.g.
+// after the consumer missed it while down. The payload is rebuilt from chat
+// storage, downloading the media again when the message has any, and is
+// flagged with "resent": true so consumers can tell it from a live event.
+// Failed deliveries go to the dead-letter store like any other webhook.
+func ResendMessageWebhook(ctx context.Context, chat types.JID, id types.MessageID) (WebhookResendSummary, error) {
+	summary := WebhookResendSummary{MessageID: id}
+	stored, err := utils.FindChatHistoryMessage(chat.String(), id)
+	if err != nil {
+		return summary, err
+	}
+	urls := utils.WebhookURLsForChat(chat.String())
+	if len(urls) == 0 {
+		return summary, ErrNoWebhookForChat
+	}
+
+	evt, err := storedMessageEvent(stored)
+	if err != nil {
+		return summary, err
+	}
+	var self *types.JID
+	if waCli := GetWaCli(); waCli != nil && waCli.Store != nil && waCli.Store.ID != nil {
+		self = waCli.Store.ID
+	}
+	payload, err := createPayload(ctx, evt, self)
+	if err != nil {
+		return summary, err
+	}
+	payload["resent"] = true
+
+	for _, url := range urls {
+		summary.Total++
+		if err := SubmitWebhook(payload, url); err != nil {
+			summary.Failed++
+			summary.Errors = append(summary.Errors, fmt.Sprintf("%s: %v", url, err))
+			continue
+		}
+		summary.Succeeded++
+	}
+	logrus.Infof("Resent webhook of message %s to %d of %d URLs", id, summary.Succeeded, summary.Total)
+	return summary, nil
+}
+
+// storedMessageEvent rebuilds the event of a stored message. Chat storage keeps
+// the text and the media reference only, so other contents, such as polls or
+// locations, come back as their text rendering.
+func storedMessageEvent(stored utils.ChatHistoryMessage) (*events.Message, error) {
+	chat, err := types.ParseJID(stored.ChatJID)
+	if err != nil {
+		return nil, fmt.Errorf("stored chat %q: %w", stored.ChatJID, err)
+	}
+	sender, err := types.ParseJID(stored.SenderJID)
+	if err != nil {
+		return nil, fmt.Errorf("stored sender %q: %w", stored.SenderJID, err)
+	}
+
+	msg := &waProto.Message{Conversation: proto.String(stored.Content)}
+	reference, err := utils.FindMediaReference(stored.ChatJID, stored.MessageID)
+	switch {
+	case err == nil:
+		if msg, err = decodeMediaReference(reference); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, utils.ErrRecordNotFound):
+		return nil, err
+	}
+
+	return &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:     chat,
+				Sender:   sender,
+				IsFromMe: stored.FromMe,
+				IsGroup:  chat.Server == types.GroupServer,
+			},
+			ID:        stored.MessageID,
+			Timestamp: stored.Timestamp,
+		},
+		Message: msg,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStoredMessageEvent(t *testing.T) {
+	storage, path := config.WhatsappChatStorage, config.PathMediaKeys
+	config.WhatsappChatStorage, config.PathMediaKeys = true, filepath.Join(t.TempDir(), "chat_media.csv")
+	defer func() { config.WhatsappChatStorage, config.PathMediaKeys = storage, path }()
+
+	chat := types.NewJID("120363025246125486", types.GroupServer)
+	sender := types.NewJID("6281234567890", types.DefaultUserServer)
+	stored := utils.ChatHistoryMessage{
+		ChatJID:   chat.String(),
+		MessageID: "3EB0TEXT",
+		SenderJID: sender.String(),
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Content:   "hello",
+	}
+
+	evt, err := storedMessageEvent(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evt.Info.ID != "3EB0TEXT" || evt.Info.Chat != chat || evt.Info.Sender != sender || !evt.Info.IsGroup {
+		t.Errorf("info = %+v, want the stored IDs of a group message", evt.Info)
+	}
+	if !evt.Info.Timestamp.Equal(stored.Timestamp) || evt.Message.GetConversation() != "hello" {
+		t.Errorf("event = %v at %s, want the stored text and time", evt.Message, evt.Info.Timestamp)
+	}
+
+	image := &waProto.Message{ImageMessage: &waProto.ImageMessage{
+		Mimetype:   proto.String("image/jpeg"),
+		DirectPath: proto.String("/v/t62.7118-24/abc"),
+		Caption:    proto.String("a caption"),
+	}}
+	reference, _ := mediaReferenceOf(chat, "3EB0IMAGE", image)
+	if err := utils.RecordMediaReferences([]utils.MediaReference{reference}); err != nil {
+		t.Fatal(err)
+	}
+	stored.MessageID, stored.Content = "3EB0IMAGE", "a caption"
+	evt, err = storedMessageEvent(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := evt.Message.GetImageMessage(); got.GetDirectPath() != "/v/t62.7118-24/abc" || got.GetCaption() != "a caption" {
+		t.Errorf("image = %v, want the stored media reference", got)
+	}
+}
@@ -0,0 +1,142 @@
+package whatsapp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MinWebhookSecretLength is the shortest secret accepted when rotating.
+	MinWebhookSecretLength = 16
+	// MaxWebhookSecretGrace is the longest time the previous secret stays valid.
+	MaxWebhookSecretGrace = 7 * 24 * time.Hour
+)
+
+// WebhookSecretRotation is the outcome of RotateWebhookSecret, and what is kept
+// in config.PathWebhookSecret.
+type WebhookSecretRotation struct {
+	Secret        string     `json:"secret"`
+	Previous      string     `json:"previous,omitempty"`
+	PreviousUntil *time.Time `json:"previous_until,omitempty"` // previous secret still signs until then
+	RotatedAt     time.Time  `json:"rotated_at"`
+}
+
+var (
+	webhookSecretMu       sync.RWMutex
+	previousWebhookSecret string
+	previousSecretUntil   time.Time
+)
+
+// webhookSecrets returns the secret signing webhooks, and the previous one while
+// its grace period lasts, or "" after it.
+func webhookSecrets() (current, previous string) {
+	webhookSecretMu.RLock()
+	defer webhookSecretMu.RUnlock()
+	if previousWebhookSecret != "" && time.Now().Before(previousSecretUntil) {
+		previous = previousWebhookSecret
+	}
+	return config.WhatsappWebhookSecret, previous
+}
+
+// ValidateWebhookSecretRotation checks that a new secret is empty or long
+// enough, and that the grace period is between 0 and MaxWebhookSecretGrace.
+func ValidateWebhookSecretRotation(secret string, grace time.Duration) error {
+	if secret != "" && len(secret) < MinWebhookSecretLength {
+		return errors.New(utils.T("webhook_secret_too_short", MinWebhookSecretLength))
+	}
+	if grace < 0 || grace > MaxWebhookSecretGrace {
+		return errors.New(utils.T("webhook_secret_grace_invalid", int(MaxWebhookSecretGrace.Seconds())))
+	}
+	return nil
+}
+
+// RotateWebhookSecret replaces the webhook signing secret without a restart.
+// An empty secret generates a random one. During the grace period webhooks are
+// also signed with the previous secret, so receivers can switch over at their
+// own pace. The new secret is stored and takes precedence over the configured
+// one on the next start.
+func RotateWebhookSecret(secret string, grace time.Duration) (WebhookSecretRotation, error) {
+	if err := ValidateWebhookSecretRotation(secret, grace); err != nil {
+		return WebhookSecretRotation{}, err
+	}
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return WebhookSecretRotation{}, err
+		}
+		secret = hex.EncodeToString(random)
+	}
+
+	webhookSecretMu.Lock()
+	defer webhookSecretMu.Unlock()
+	rotation := WebhookSecretRotation{Secret: secret, RotatedAt: time.Now()}
+	if grace > 0 && config.WhatsappWebhookSecret != secret {
+		until := rotation.RotatedAt.Add(grace)
+		rotation.Previous, rotation.PreviousUntil = config.WhatsappWebhookSecret, &until
+	}
+	if err := saveWebhookSecret(rotation); err != nil {
+		return WebhookSecretRotation{}, err
+	}
+	applyWebhookSecret(rotation)
+	logrus.Infof("Webhook secret rotated, previous secret valid for %s", grace)
+	return rotation, nil
+}
+
+// LoadWebhookSecret restores the secret last set through RotateWebhookSecret,
+// replacing the configured one. Delete config.PathWebhookSecret to go back to
+// the configured secret.
+func LoadWebhookSecret() error {
+	data, err := os.ReadFile(config.PathWebhookSecret)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var rotation WebhookSecretRotation
+	if err := json.Unmarshal(data, &rotation); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", config.PathWebhookSecret, err)
+	}
+	if rotation.Secret == "" {
+		return fmt.Errorf("%s holds no secret", config.PathWebhookSecret)
+	}
+
+	webhookSecretMu.Lock()
+	defer webhookSecretMu.Unlock()
+	applyWebhookSecret(rotation)
+	return nil
+}
+
+func applyWebhookSecret(rotation WebhookSecretRotation) {
+	config.WhatsappWebhookSecret = rotation.Secret
+	previousWebhookSecret, previousSecretUntil = "", time.Time{}
+	if rotation.PreviousUntil != nil {
+		previousWebhookSecret, previousSecretUntil = rotation.Previous, *rotation.PreviousUntil
+	}
+}
+
+// saveWebhookSecret writes the rotation to a temporary file first, so a crash
+// never leaves a truncated secret behind.
+func saveWebhookSecret(rotation WebhookSecretRotation) error {
+	data, err := json.Marshal(rotation)
+	if err != nil {
+		return err
+	}
+	tmp := config.PathWebhookSecret + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to store webhook secret: %w", err)
+	}
+	if err := os.Rename(tmp, config.PathWebhookSecret); err != nil {
+		return fmt.Errorf("failed to store webhook secret: %w", err)
+	}
+	return nil
+}
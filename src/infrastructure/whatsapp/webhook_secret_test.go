@@ -0,0 +1,75 @@
+package whatsapp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func TestRotateWebhookSecret(t *testing.T) {
+	originalSecret, originalPath, originalFormat := config.WhatsappWebhookSecret, config.PathWebhookSecret, config.WhatsappWebhookFormat
+	t.Cleanup(func() {
+		config.WhatsappWebhookSecret, config.PathWebhookSecret, config.WhatsappWebhookFormat = originalSecret, originalPath, originalFormat
+		applyWebhookSecret(WebhookSecretRotation{Secret: originalSecret})
+	})
+	config.WhatsappWebhookSecret = "old-secret"
+	config.PathWebhookSecret = filepath.Join(t.TempDir(), "webhook_secret.json")
+	config.WhatsappWebhookFormat = "json"
+
+	if _, err := RotateWebhookSecret("short", 0); err == nil {
+		t.Error("a short secret was accepted")
+	}
+	if _, err := RotateWebhookSecret("", MaxWebhookSecretGrace+time.Second); err == nil {
+		t.Error("a grace period over the maximum was accepted")
+	}
+
+	rotation, err := RotateWebhookSecret("new-secret-0123456789", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotation.Previous != "old-secret" || rotation.PreviousUntil == nil {
+		t.Errorf("rotation = %+v, want the old secret kept for an hour", rotation)
+	}
+	if current, previous := webhookSecrets(); current != "new-secret-0123456789" || previous != "old-secret" {
+		t.Errorf("webhookSecrets() = %q, %q", current, previous)
+	}
+
+	var signature, previousSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature, previousSignature = r.Header.Get("X-Hub-Signature-256"), r.Header.Get("X-Hub-Signature-256-Previous")
+	}))
+	defer server.Close()
+	body := []byte(`{"event":"test"}`)
+	if err := deliverWebhook(body, server.URL); err != nil {
+		t.Fatal(err)
+	}
+	wantNew, _ := getMessageDigestOrSignature(body, []byte("new-secret-0123456789"))
+	wantOld, _ := getMessageDigestOrSignature(body, []byte("old-secret"))
+	if signature != "sha256="+wantNew || previousSignature != "sha256="+wantOld {
+		t.Errorf("signatures = %q, %q, want both secrets during the grace period", signature, previousSignature)
+	}
+
+	// A restart starts from the configured secret and picks up the stored one.
+	applyWebhookSecret(WebhookSecretRotation{Secret: "old-secret"})
+	if err := LoadWebhookSecret(); err != nil {
+		t.Fatal(err)
+	}
+	if current, previous := webhookSecrets(); current != "new-secret-0123456789" || previous != "old-secret" {
+		t.Errorf("after LoadWebhookSecret, webhookSecrets() = %q, %q", current, previous)
+	}
+
+	generated, err := RotateWebhookSecret("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(generated.Secret) != 64 || generated.PreviousUntil != nil {
+		t.Errorf("rotation = %+v, want a generated secret without grace period", generated)
+	}
+	if _, previous := webhookSecrets(); previous != "" {
+		t.Errorf("previous secret %q still signs without a grace period", previous)
+	}
+}
@@ -0,0 +1,439 @@
+package whatsapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	waCommon "go.mau.fi/whatsmeow/proto/waCommon"
+	waProto "go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreatePayloadBeforeLogin(t *testing.T) {
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:    types.NewJID("120363000000000000", types.GroupServer),
+				Sender:  types.NewJID("6281234567890", types.DefaultUserServer),
+				IsGroup: true,
+			},
+			ID:        "3EB0C0FFEE",
+			Timestamp: time.Now(),
+		},
+		Message: &waProto.Message{Conversation: proto.String("hello")},
+	}
+
+	payload, err := createPayload(context.Background(), evt, nil)
+	if err != nil {
+		t.Fatalf("createPayload() error = %v", err)
+	}
+	if payload["MyNumber"] != false {
+		t.Errorf("MyNumber = %v, want false", payload["MyNumber"])
+	}
+	if payload["logged_in"] != false {
+		t.Errorf("logged_in = %v, want false", payload["logged_in"])
+	}
+	if _, ok := payload["GroupName"]; ok {
+		t.Errorf("GroupName should be skipped before login, got %v", payload["GroupName"])
+	}
+}
+
+func TestCreatePayloadSelfDetection(t *testing.T) {
+	self := types.NewJID("6281234567890", types.DefaultUserServer)
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:     types.NewJID("6289876543210", types.DefaultUserServer),
+				Sender:   self,
+				IsFromMe: true,
+			},
+			ID:        "3EB0C0FFEF",
+			Timestamp: time.Now(),
+		},
+		Message: &waProto.Message{Conversation: proto.String("hello")},
+	}
+
+	payload, err := createPayload(context.Background(), evt, &self)
+	if err != nil {
+		t.Fatalf("createPayload() error = %v", err)
+	}
+	if payload["MyNumber"] != true {
+		t.Errorf("MyNumber = %v, want true", payload["MyNumber"])
+	}
+	if _, ok := payload["logged_in"]; ok {
+		t.Errorf("logged_in should only be set before login")
+	}
+}
+
+func TestEncodeWebhookBodyForm(t *testing.T) {
+	body := []byte(`{"PushName":"Ana","IsGroup":false,"message":{"ID":"ABC","RepliedId":null},"contact":[{"vcard":"BEGIN:VCARD"}],"Port":3000}`)
+
+	encoded, contentType, err := encodeWebhookBody(body, "form")
+	if err != nil {
+		t.Fatalf("encodeWebhookBody() error = %v", err)
+	}
+	if contentType != "application/x-www-form-urlencoded" {
+		t.Errorf("content type = %q", contentType)
+	}
+	values, err := url.ParseQuery(string(encoded))
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	want := map[string]string{
+		"PushName":           "Ana",
+		"IsGroup":            "false",
+		"message[ID]":        "ABC",
+		"message[RepliedId]": "",
+		"contact[0][vcard]":  "BEGIN:VCARD",
+		"Port":               "3000",
+	}
+	for key, value := range want {
+		if got := values.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestEncodeWebhookBodyJSON(t *testing.T) {
+	body := []byte(`{"PushName":"Ana"}`)
+	encoded, contentType, err := encodeWebhookBody(body, "json")
+	if err != nil {
+		t.Fatalf("encodeWebhookBody() error = %v", err)
+	}
+	if contentType != "application/json" || string(encoded) != string(body) {
+		t.Errorf("got %q (%s), want the body unchanged as application/json", encoded, contentType)
+	}
+	if _, _, err := encodeWebhookBody(body, "xml"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestEditedContent(t *testing.T) {
+	edit := &events.Message{
+		Message: &waProto.Message{ProtocolMessage: &waProto.ProtocolMessage{
+			Type:          waProto.ProtocolMessage_MESSAGE_EDIT.Enum(),
+			Key:           &waCommon.MessageKey{ID: proto.String("3EB0ORIGINAL")},
+			EditedMessage: &waProto.Message{Conversation: proto.String("fixed typo")},
+		}},
+	}
+	id, edited := editedContent(edit)
+	if id != "3EB0ORIGINAL" || messageText(edited) != "fixed typo" {
+		t.Fatalf("unexpected edit %q %q", id, messageText(edited))
+	}
+	if got := determineMessageType(edit, ""); got != "edit_message" {
+		t.Fatalf("expected edit_message, got %q", got)
+	}
+
+	revoke := &events.Message{
+		Message: &waProto.Message{ProtocolMessage: &waProto.ProtocolMessage{
+			Type: waProto.ProtocolMessage_REVOKE.Enum(),
+			Key:  &waCommon.MessageKey{ID: proto.String("3EB0ORIGINAL")},
+		}},
+	}
+	if _, edited := editedContent(revoke); edited != nil {
+		t.Fatal("a revoke is not an edit")
+	}
+}
+
+func TestLinkRequestResponse(t *testing.T) {
+	chat := types.NewJID("6281234567890", types.DefaultUserServer)
+	pendingRequests.Store(chat.String(), requestPrompt{kind: requestKindLocation, messageID: "REQ1", sentAt: time.Now()})
+	t.Cleanup(func() { pendingRequests.Delete(chat.String()) })
+
+	reply := func(id types.MessageID, msg *waProto.Message) *events.Message {
+		return &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{Chat: chat, Sender: chat},
+				ID:            id,
+			},
+			Message: msg,
+		}
+	}
+
+	contact := reply("ANS0", &waProto.Message{ContactMessage: &waProto.ContactMessage{DisplayName: proto.String("me")}})
+	linkRequestResponse(contact)
+	if got := determineMessageType(contact, ""); got == "phone_request_response" {
+		t.Errorf("contact answering a location request classified as %q", got)
+	}
+
+	location := reply("ANS1", &waProto.Message{LocationMessage: &waProto.LocationMessage{}})
+	linkRequestResponse(location)
+	t.Cleanup(func() { requestResponses.Delete("ANS1") })
+	if got := determineMessageType(location, ""); got != "location_request_response" {
+		t.Errorf("determineMessageType() = %q, want location_request_response", got)
+	}
+	if request, ok := requestResponse("ANS1"); !ok || request.messageID != "REQ1" {
+		t.Errorf("requestResponse() = %+v, %v, want REQ1", request, ok)
+	}
+	if _, ok := pendingRequests.Load(chat.String()); ok {
+		t.Error("pending request kept after being answered")
+	}
+}
+
+func TestVersionWebhookPayload(t *testing.T) {
+	payload := map[string]interface{}{
+		"SenderNumber": "6281234567890",
+		"Type":         "text",
+		"message": map[string]interface{}{
+			"ID":          "3EB0C0FFEE",
+			"TextMessage": "hello",
+			"PollUpdate":  map[string]interface{}{"PollID": "POLL1"},
+		},
+		"contact":   []interface{}{map[string]interface{}{"displayName": "Ana", "vcard": "BEGIN:VCARD"}},
+		"timestamp": "2025-01-01T00:00:00Z",
+	}
+
+	v1 := versionWebhookPayload(payload, WebhookPayloadV1)
+	if v1["payload_version"] != WebhookPayloadV1 || v1["SenderNumber"] != "6281234567890" {
+		t.Errorf("v1 payload = %v, want the original keys", v1)
+	}
+	if _, ok := payload["payload_version"]; ok {
+		t.Error("versionWebhookPayload() modified its input")
+	}
+
+	v2 := versionWebhookPayload(payload, WebhookPayloadV2)
+	if v2["payload_version"] != WebhookPayloadV2 || v2["sender"] != "6281234567890" || v2["type"] != "text" {
+		t.Errorf("v2 payload = %v, want snake_case top-level keys", v2)
+	}
+	message := v2["message"].(map[string]interface{})
+	if message["id"] != "3EB0C0FFEE" || message["text"] != "hello" {
+		t.Errorf("v2 message = %v, want snake_case keys", message)
+	}
+	if poll := message["poll_update"].(map[string]interface{}); poll["poll_id"] != "POLL1" {
+		t.Errorf("v2 poll_update = %v, want poll_id", poll)
+	}
+	if contact := v2["contact"].([]interface{})[0].(map[string]interface{}); contact["display_name"] != "Ana" {
+		t.Errorf("v2 contact = %v, want display_name", contact)
+	}
+	if _, ok := v2["SenderNumber"]; ok {
+		t.Error("v2 payload kept the v1 key SenderNumber")
+	}
+
+	if got := versionWebhookPayload(payload, 7)["payload_version"]; got != WebhookPayloadV1 {
+		t.Errorf("unknown version produced payload_version %v, want 1", got)
+	}
+}
+
+func TestWebhookIgnored(t *testing.T) {
+	original := config.WhatsappWebhookIgnore
+	t.Cleanup(func() { config.WhatsappWebhookIgnore = original })
+	config.WhatsappWebhookIgnore = []string{"6281111111111@s.whatsapp.net", "*@broadcast"}
+
+	message := func(sender, chat types.JID) *events.Message {
+		return &events.Message{Info: types.MessageInfo{MessageSource: types.MessageSource{Sender: sender, Chat: chat}}}
+	}
+	noisy := types.NewADJID("6281111111111", 0, 2)
+	other := types.NewJID("6282222222222", types.DefaultUserServer)
+	group := types.NewJID("120363000000000000", types.GroupServer)
+
+	if !webhookIgnored(message(noisy, group)) {
+		t.Error("message from an ignored sender was forwarded")
+	}
+	if !webhookIgnored(message(other, types.StatusBroadcastJID)) {
+		t.Error("message in an ignored chat was forwarded")
+	}
+	if webhookIgnored(message(other, group)) {
+		t.Error("message from a regular sender was ignored")
+	}
+}
+
+func TestInlineWebhookMedia(t *testing.T) {
+	original := config.WhatsappWebhookInlineMediaMaxBytes
+	t.Cleanup(func() { config.WhatsappWebhookInlineMediaMaxBytes = original })
+
+	path := filepath.Join(t.TempDir(), "voice.ogg")
+	if err := os.WriteFile(path, []byte("OggS-voice"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config.WhatsappWebhookInlineMediaMaxBytes = 0
+	media := ExtractedMedia{MediaPath: path, MimeType: "audio/ogg; codecs=opus"}
+	inlineWebhookMedia(&media)
+	if media.Base64 != "" {
+		t.Error("media was inlined with inlining disabled")
+	}
+
+	config.WhatsappWebhookInlineMediaMaxBytes = 4
+	inlineWebhookMedia(&media)
+	if media.Base64 != "" {
+		t.Error("media above the inline limit was inlined")
+	}
+
+	config.WhatsappWebhookInlineMediaMaxBytes = 1024
+	inlineWebhookMedia(&media)
+	if want := "data:audio/ogg;codecs=opus;base64,T2dnUy12b2ljZQ=="; media.Base64 != want {
+		t.Errorf("Base64 = %q, want %q", media.Base64, want)
+	}
+}
+
+func TestExtractWebhookMediaTimeout(t *testing.T) {
+	original := config.WhatsappMediaDownloadTimeoutSeconds
+	t.Cleanup(func() { config.WhatsappMediaDownloadTimeoutSeconds = original })
+	config.WhatsappMediaDownloadTimeoutSeconds = 1
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	image := &waProto.ImageMessage{MediaKey: []byte("key"), DirectPath: proto.String("/v/t62/abc")}
+	got, ok := extractWebhookMedia(ctx, "image", image).(map[string]any)
+	if !ok || got["reason"] != "download_timeout" || got["download_failed"] != true {
+		t.Errorf("extractWebhookMedia() past the deadline = %v, want a download_timeout marker", got)
+	}
+}
+
+func TestSentMessagePayload(t *testing.T) {
+	sent := utils.ChatHistoryMessage{
+		ChatJID:   "6281234567890@s.whatsapp.net",
+		MessageID: "3EB0SENT",
+		FromMe:    true,
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Content:   "Your order shipped",
+		Type:      "text_message",
+	}
+	payload := sentMessagePayload(sent)
+	want := map[string]interface{}{
+		"Type":         "message_sent",
+		"recipient":    "6281234567890@s.whatsapp.net",
+		"message_id":   "3EB0SENT",
+		"message_type": "text_message",
+		"content":      "Your order shipped",
+		"timestamp":    "2025-01-02T03:04:05Z",
+	}
+	if len(payload) != len(want) {
+		t.Errorf("payload = %v, want %v", payload, want)
+	}
+	for key, value := range want {
+		if payload[key] != value {
+			t.Errorf("payload[%q] = %v, want %v", key, payload[key], value)
+		}
+	}
+}
+
+func TestForwardSentMessageIsTracked(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer server.Close()
+	original := config.WhatsappWebhookOutbound
+	t.Cleanup(func() { config.WhatsappWebhookOutbound = original })
+	config.WhatsappWebhookOutbound = []string{server.URL}
+
+	forwardSentMessage(utils.ChatHistoryMessage{ChatJID: "6281234567890@s.whatsapp.net", MessageID: "3EB0TRACKED"})
+	<-received
+
+	tracked := func() bool {
+		for _, task := range utils.DefaultTasks.List() {
+			if task.Description == "forwards sent message 3EB0TRACKED" {
+				return true
+			}
+		}
+		return false
+	}
+	if !tracked() {
+		t.Error("forward in flight is not listed in the task registry")
+	}
+	close(release)
+	for deadline := time.Now().Add(5 * time.Second); tracked(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("finished forward is still listed in the task registry")
+		}
+	}
+}
+
+func TestUnknownMessagePayload(t *testing.T) {
+	known, err := proto.Marshal(&waProto.Message{
+		KeepInChatMessage:  &waProto.KeepInChatMessage{},
+		MessageContextInfo: &waProto.MessageContextInfo{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Field 9999 stands for a message type newer than the bundled definitions.
+	raw := protowire.AppendVarint(protowire.AppendTag(known, 9999, protowire.VarintType), 1)
+	msg := &waProto.Message{}
+	if err := proto.Unmarshal(raw, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:   types.NewJID("6281234567890", types.DefaultUserServer),
+				Sender: types.NewJID("6281234567890", types.DefaultUserServer),
+			},
+			ID:        "3EB0UNKNOWN",
+			Timestamp: time.Now(),
+		},
+		Message: msg,
+	}
+	payload, err := createPayload(context.Background(), evt, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload["Type"] != "unknown" {
+		t.Fatalf("Type = %v, want unknown", payload["Type"])
+	}
+	unknown, _ := payload["unknown"].(map[string]interface{})
+	fields, _ := unknown["fields"].([]string)
+	if len(fields) != 2 || fields[0] != "keepInChatMessage" || fields[1] != "messageContextInfo" {
+		t.Errorf("fields = %v", unknown["fields"])
+	}
+	if numbers, _ := unknown["unknown_field_numbers"].([]int32); len(numbers) != 1 || numbers[0] != 9999 {
+		t.Errorf("unknown_field_numbers = %v, want [9999]", unknown["unknown_field_numbers"])
+	}
+
+	evt.Message = &waProto.Message{Conversation: proto.String("hello")}
+	if payload, _ := createPayload(context.Background(), evt, nil); payload["unknown"] != nil {
+		t.Errorf("a text message got an unknown block: %v", payload["unknown"])
+	}
+}
+
+func TestWebhookMediaDownloadDisabled(t *testing.T) {
+	originalVideo, originalStorage := config.WhatsappDownloadVideo, config.WhatsappChatStorage
+	t.Cleanup(func() { config.WhatsappDownloadVideo, config.WhatsappChatStorage = originalVideo, originalStorage })
+	config.WhatsappDownloadVideo = false
+	config.WhatsappChatStorage = true
+
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:   types.NewJID("6281234567890", types.DefaultUserServer),
+				Sender: types.NewJID("6281234567890", types.DefaultUserServer),
+			},
+			ID:        "3EB0VIDEO",
+			Timestamp: time.Now(),
+		},
+		Message: &waProto.Message{PtvMessage: &waProto.VideoMessage{
+			Mimetype:   proto.String("video/mp4"),
+			FileLength: proto.Uint64(2048),
+		}},
+	}
+	payload, err := createPayload(context.Background(), evt, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	video, _ := payload["video"].(map[string]any)
+	if video["reason"] != "download_disabled" || video["size"] != uint64(2048) || video["mime_type"] != "video/mp4" {
+		t.Errorf("video = %v, want its metadata only", payload["video"])
+	}
+	if video["media_url"] != "/chat/6281234567890@s.whatsapp.net/message/3EB0VIDEO/media" {
+		t.Errorf("media_url = %v", video["media_url"])
+	}
+
+	if !mediaDownloadEnabled("image") || mediaDownloadEnabled("PTV video") {
+		t.Error("only video downloads should be disabled")
+	}
+}
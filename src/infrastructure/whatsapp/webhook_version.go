@@ -0,0 +1,96 @@
+package whatsapp
+
+// Webhook payload versions. Every body carries "payload_version" so consumers
+// can tell which layout they receive; --webhook-payload-version pins it.
+//
+// Version 1 is the original layout, kept for existing consumers. It mixes
+// PascalCase and snake_case keys:
+//
+//	{"SenderNumber", "PushName", "IsGroup", "GroupName", "MyNumber", "Type", "Port",
+//	 "message": {"ID", "MessageOrigin", "RepliedId", "TextMessage", "TitleLink",
+//	             "LinkDescription", "PollUpdate": {"PollID", "SelectedOptions"}},
+//	 "contact": [{"displayName", "vcard"}], "timestamp", "reaction", ...}
+//
+// Version 2 is the same event with every key in snake_case:
+//
+//	{"sender", "push_name", "is_group", "group_name", "from_me", "type", "port",
+//	 "message": {"id", "quoted_message", "replied_id", "text", "link_title",
+//	             "link_description", "poll_update": {"poll_id", "selected_options"}},
+//	 "contact": [{"display_name", "vcard"}], "timestamp", "reaction", ...}
+//
+// New layouts are introduced as a new version so the older ones stay stable.
+const (
+	WebhookPayloadV1 = 1
+	WebhookPayloadV2 = 2
+)
+
+var (
+	webhookV2Keys = map[string]string{
+		"SenderNumber": "sender",
+		"PushName":     "push_name",
+		"IsGroup":      "is_group",
+		"GroupName":    "group_name",
+		"MyNumber":     "from_me",
+		"Type":         "type",
+		"Port":         "port",
+	}
+	webhookV2MessageKeys = map[string]string{
+		"ID":              "id",
+		"MessageOrigin":   "quoted_message",
+		"RepliedId":       "replied_id",
+		"TextMessage":     "text",
+		"TitleLink":       "link_title",
+		"LinkDescription": "link_description",
+		"PollUpdate":      "poll_update",
+		"PollID":          "poll_id",
+		"SelectedOptions": "selected_options",
+	}
+)
+
+// versionWebhookPayload returns payload in the requested layout with its
+// "payload_version" set. The original map is left untouched since the same
+// payload may be sent to several webhooks.
+func versionWebhookPayload(payload map[string]interface{}, version int) map[string]interface{} {
+	if version != WebhookPayloadV2 {
+		version = WebhookPayloadV1
+	}
+	result := make(map[string]interface{}, len(payload)+1)
+	for key, value := range payload {
+		if version == WebhookPayloadV2 {
+			if renamed, ok := webhookV2Keys[key]; ok {
+				key = renamed
+			}
+			switch key {
+			case "message":
+				value = renameWebhookKeys(value, webhookV2MessageKeys)
+			case "contact":
+				value = renameWebhookKeys(value, map[string]string{"displayName": "display_name"})
+			}
+		}
+		result[key] = value
+	}
+	result["payload_version"] = version
+	return result
+}
+
+// renameWebhookKeys copies value renaming the keys of its maps, recursively.
+func renameWebhookKeys(value interface{}, renames map[string]string) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			if name, ok := renames[key]; ok {
+				key = name
+			}
+			renamed[key] = renameWebhookKeys(child, renames)
+		}
+		return renamed
+	case []interface{}:
+		renamed := make([]interface{}, len(typed))
+		for i, child := range typed {
+			renamed[i] = renameWebhookKeys(child, renames)
+		}
+		return renamed
+	}
+	return value
+}
@@ -70,6 +70,57 @@ func (e WaUploadMediaError) StatusCode() int {
 	return http.StatusInternalServerError
 }
 
+type InviteLinkInvalid string
+
+// Error for complying the error interface
+func (e InviteLinkInvalid) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e InviteLinkInvalid) ErrCode() string {
+	return "INVITE_LINK_INVALID"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e InviteLinkInvalid) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+type InviteLinkRevoked string
+
+// Error for complying the error interface
+func (e InviteLinkRevoked) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e InviteLinkRevoked) ErrCode() string {
+	return "INVITE_LINK_REVOKED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e InviteLinkRevoked) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+type RecipientNotAllowed string
+
+// Error for complying the error interface
+func (e RecipientNotAllowed) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e RecipientNotAllowed) ErrCode() string {
+	return "RECIPIENT_NOT_ALLOWED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e RecipientNotAllowed) StatusCode() int {
+	return http.StatusForbidden
+}
+
 const (
 	ErrInvalidJID        = InvalidJID("your JID is invalid")
 	ErrUserNotRegistered = InvalidJID("user is not registered")
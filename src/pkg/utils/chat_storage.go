@@ -2,9 +2,14 @@ package utils
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 )
@@ -98,3 +103,666 @@ func RecordMessage(messageID string, senderJID string, messageContent string) er
 
 	return nil
 }
+
+const (
+	ReceiptStatusSent      = "sent"
+	ReceiptStatusDelivered = "delivered"
+	ReceiptStatusRead      = "read"
+	ReceiptStatusPlayed    = "played"
+)
+
+// receiptStatusRank orders ack states so a late "delivered" never overwrites "read"
+var receiptStatusRank = map[string]int{
+	ReceiptStatusSent:      0,
+	ReceiptStatusDelivered: 1,
+	ReceiptStatusRead:      2,
+	ReceiptStatusPlayed:    3,
+}
+
+// ErrRecordNotFound is returned when a message ID is not present in storage
+var ErrRecordNotFound = errors.New("record not found in storage")
+
+type MessageReceipt struct {
+	MessageID string    `json:"message_id"`
+	ChatJID   string    `json:"chat_jid"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// mutex to prevent concurrent receipt file access
+var receiptMutex sync.Mutex
+
+func readReceipts() ([][]string, error) {
+	file, err := os.OpenFile(config.PathChatReceipts, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open receipt storage file: %w", err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt records: %w", err)
+	}
+	return records, nil
+}
+
+func writeReceipts(records [][]string) error {
+	file, err := os.OpenFile(config.PathChatReceipts, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open receipt file for writing: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write receipt records: %w", err)
+	}
+	return nil
+}
+
+// RecordSentMessage registers a message sent by the bot so later receipts can be tracked against it
+func RecordSentMessage(messageID, chatJID string, timestamp time.Time) error {
+	if !config.WhatsappChatStorage {
+		return nil
+	}
+
+	receiptMutex.Lock()
+	defer receiptMutex.Unlock()
+
+	records, err := readReceipts()
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if len(record) == 4 && record[0] == messageID {
+			return nil
+		}
+	}
+
+	newRecord := []string{messageID, chatJID, ReceiptStatusSent, timestamp.UTC().Format(time.RFC3339)}
+	return writeReceipts(append([][]string{newRecord}, records...))
+}
+
+// UpdateMessageReceipt moves a sent message to a newer ack state. It returns false when the
+// message is unknown or the status would not advance the stored one.
+func UpdateMessageReceipt(messageID, status string, timestamp time.Time) (bool, error) {
+	if !config.WhatsappChatStorage {
+		return false, nil
+	}
+	rank, ok := receiptStatusRank[status]
+	if !ok {
+		return false, fmt.Errorf("unknown receipt status %s", status)
+	}
+
+	receiptMutex.Lock()
+	defer receiptMutex.Unlock()
+
+	records, err := readReceipts()
+	if err != nil {
+		return false, err
+	}
+	for _, record := range records {
+		if len(record) == 4 && record[0] == messageID {
+			if rank <= receiptStatusRank[record[2]] {
+				return false, nil
+			}
+			record[2] = status
+			record[3] = timestamp.UTC().Format(time.RFC3339)
+			return true, writeReceipts(records)
+		}
+	}
+	return false, nil
+}
+
+// FindMessageReceipt returns the latest ack state of a message sent by the bot
+func FindMessageReceipt(messageID string) (MessageReceipt, error) {
+	receiptMutex.Lock()
+	defer receiptMutex.Unlock()
+
+	records, err := readReceipts()
+	if err != nil {
+		return MessageReceipt{}, err
+	}
+	for _, record := range records {
+		if len(record) == 4 && record[0] == messageID {
+			timestamp, _ := time.Parse(time.RFC3339, record[3])
+			return MessageReceipt{
+				MessageID: record[0],
+				ChatJID:   record[1],
+				Status:    record[2],
+				Timestamp: timestamp,
+			}, nil
+		}
+	}
+	return MessageReceipt{}, fmt.Errorf("message ID %s: %w", messageID, ErrRecordNotFound)
+}
+
+type ChatHistoryMessage struct {
+	ChatJID   string    `json:"chat_jid"`
+	MessageID string    `json:"message_id"`
+	SenderJID string    `json:"sender_jid"`
+	FromMe    bool      `json:"from_me"`
+	Timestamp time.Time `json:"timestamp"`
+	Content   string    `json:"content"`
+	Type      string    `json:"type,omitempty"`
+	MediaType string    `json:"media_type,omitempty"` // mimetype of the attachment
+	MediaPath string    `json:"media_path,omitempty"` // WhatsApp direct path of the attachment
+
+	Reactions map[string]int `json:"reactions,omitempty"` // current reaction counts by emoji, not stored in the row
+}
+
+// chat history rows written before type and media were recorded have 6 columns
+const (
+	chatHistoryLegacyColumns = 6
+	chatHistoryColumns       = 9
+)
+
+// mutex to prevent concurrent chat history file access
+var historyMutex sync.Mutex
+
+// RecordChatHistory appends messages to the per-chat history, skipping IDs that
+// are already stored in the same chat. It returns how many messages were added.
+func RecordChatHistory(messages []ChatHistoryMessage) (int, error) {
+	if !config.WhatsappChatStorage || len(messages) == 0 {
+		return 0, nil
+	}
+
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	records, err := readChatHistory()
+	if err != nil {
+		return 0, err
+	}
+	known := make(map[string]bool, len(records))
+	for _, record := range records {
+		if len(record) >= chatHistoryLegacyColumns {
+			known[record[0]+"/"+record[1]] = true
+		}
+	}
+
+	var newRecords [][]string
+	for _, message := range messages {
+		key := message.ChatJID + "/" + message.MessageID
+		if message.MessageID == "" || known[key] {
+			continue
+		}
+		known[key] = true
+		newRecords = append(newRecords, []string{
+			message.ChatJID,
+			message.MessageID,
+			message.SenderJID,
+			strconv.FormatBool(message.FromMe),
+			message.Timestamp.UTC().Format(time.RFC3339),
+			message.Content,
+			message.Type,
+			message.MediaType,
+			message.MediaPath,
+		})
+	}
+	if len(newRecords) == 0 {
+		return 0, nil
+	}
+
+	file, err := os.OpenFile(config.PathChatHistory, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open chat history file for writing: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(newRecords); err != nil {
+		return 0, fmt.Errorf("failed to write chat history records: %w", err)
+	}
+	return len(newRecords), nil
+}
+
+// GetChatHistory returns up to limit stored messages of a chat, newest first.
+// A limit of 0 or less returns every message.
+func GetChatHistory(chatJID string, limit int) ([]ChatHistoryMessage, error) {
+	historyMutex.Lock()
+	records, err := readChatHistory()
+	historyMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []ChatHistoryMessage{}
+	for _, record := range records {
+		if message, ok := parseChatHistoryRecord(record); ok && message.ChatJID == chatJID {
+			messages = append(messages, message)
+		}
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp.After(messages[j].Timestamp)
+	})
+	if limit > 0 && len(messages) > limit {
+		messages = messages[:limit]
+	}
+
+	reactions, err := ReactionCounts(chatJID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range messages {
+		messages[i].Reactions = reactions[messages[i].MessageID]
+	}
+	return messages, nil
+}
+
+// FindChatHistoryMessage returns a stored message of a chat, or ErrRecordNotFound.
+func FindChatHistoryMessage(chatJID, messageID string) (ChatHistoryMessage, error) {
+	messages, err := GetChatHistory(chatJID, 0)
+	if err != nil {
+		return ChatHistoryMessage{}, err
+	}
+	for _, message := range messages {
+		if message.MessageID == messageID {
+			return message, nil
+		}
+	}
+	return ChatHistoryMessage{}, fmt.Errorf("message ID %s: %w", messageID, ErrRecordNotFound)
+}
+
+// UpdateChatHistoryContent replaces the stored text of a message, e.g. after it
+// was edited. It reports whether the message was found.
+func UpdateChatHistoryContent(chatJID, messageID, content string) (bool, error) {
+	if !config.WhatsappChatStorage {
+		return false, nil
+	}
+
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	records, err := readChatHistory()
+	if err != nil {
+		return false, err
+	}
+	found := false
+	for _, record := range records {
+		if len(record) >= chatHistoryLegacyColumns && record[0] == chatJID && record[1] == messageID {
+			record[5] = content
+			found = true
+		}
+	}
+	if !found {
+		return false, nil
+	}
+
+	file, err := os.OpenFile(config.PathChatHistory, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return false, fmt.Errorf("failed to open chat history file for writing: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(records); err != nil {
+		return false, fmt.Errorf("failed to write chat history records: %w", err)
+	}
+	return true, nil
+}
+
+// FindChatHistoryMessages looks up stored messages of a chat by ID, or of every
+// chat when chatJID is empty. IDs that are not stored there are left out of the
+// result.
+func FindChatHistoryMessages(chatJID string, messageIDs []string) (map[string]ChatHistoryMessage, error) {
+	historyMutex.Lock()
+	records, err := readChatHistory()
+	historyMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+	found := make(map[string]ChatHistoryMessage)
+	for _, record := range records {
+		if message, ok := parseChatHistoryRecord(record); ok && (chatJID == "" || message.ChatJID == chatJID) && wanted[message.MessageID] {
+			found[message.MessageID] = message
+		}
+	}
+	return found, nil
+}
+
+func parseChatHistoryRecord(record []string) (ChatHistoryMessage, bool) {
+	if len(record) != chatHistoryLegacyColumns && len(record) != chatHistoryColumns {
+		return ChatHistoryMessage{}, false
+	}
+	fromMe, _ := strconv.ParseBool(record[3])
+	timestamp, _ := time.Parse(time.RFC3339, record[4])
+	message := ChatHistoryMessage{
+		ChatJID:   record[0],
+		MessageID: record[1],
+		SenderJID: record[2],
+		FromMe:    fromMe,
+		Timestamp: timestamp,
+		Content:   record[5],
+	}
+	if len(record) == chatHistoryColumns {
+		message.Type = record[6]
+		message.MediaType = record[7]
+		message.MediaPath = record[8]
+	}
+	return message, true
+}
+
+func readChatHistory() ([][]string, error) {
+	file, err := os.OpenFile(config.PathChatHistory, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open chat history file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chat history records: %w", err)
+	}
+	return records, nil
+}
+
+// mutex to prevent concurrent reaction file access
+var reactionMutex sync.Mutex
+
+// RecordReaction stores a reaction of senderJID to a message of a chat. An empty
+// emoji records the removal of the sender's previous reaction.
+func RecordReaction(chatJID, messageID, senderJID, emoji string, timestamp time.Time) error {
+	if !config.WhatsappChatStorage || messageID == "" {
+		return nil
+	}
+
+	reactionMutex.Lock()
+	defer reactionMutex.Unlock()
+
+	file, err := os.OpenFile(config.PathReactions, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open reactions file for writing: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	record := []string{chatJID, messageID, senderJID, emoji, timestamp.UTC().Format(time.RFC3339)}
+	if err := writer.WriteAll([][]string{record}); err != nil {
+		return fmt.Errorf("failed to write reaction record: %w", err)
+	}
+	return nil
+}
+
+// ReactionCounts returns, per message ID of a chat, how many senders currently
+// react with each emoji. Only the latest reaction of every sender counts.
+func ReactionCounts(chatJID string) (map[string]map[string]int, error) {
+	reactionMutex.Lock()
+	file, err := os.OpenFile(config.PathReactions, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		reactionMutex.Unlock()
+		return nil, fmt.Errorf("failed to open reactions file: %w", err)
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	file.Close()
+	reactionMutex.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reaction records: %w", err)
+	}
+
+	type latestReaction struct {
+		emoji     string
+		timestamp time.Time
+	}
+	latest := make(map[[2]string]latestReaction)
+	for _, record := range records {
+		if len(record) != 5 || record[0] != chatJID {
+			continue
+		}
+		timestamp, _ := time.Parse(time.RFC3339, record[4])
+		key := [2]string{record[1], record[2]}
+		if previous, ok := latest[key]; ok && timestamp.Before(previous.timestamp) {
+			continue
+		}
+		latest[key] = latestReaction{emoji: record[3], timestamp: timestamp}
+	}
+
+	counts := make(map[string]map[string]int)
+	for key, reaction := range latest {
+		if reaction.emoji == "" {
+			continue
+		}
+		if counts[key[0]] == nil {
+			counts[key[0]] = make(map[string]int)
+		}
+		counts[key[0]][reaction.emoji]++
+	}
+	return counts, nil
+}
+
+// PollVote is the current vote of a voter in a poll. Options holds the hex
+// encoded SHA-256 hashes of the selected option names, as votes carry them.
+type PollVote struct {
+	VoterJID  string
+	Options   []string
+	Timestamp time.Time
+}
+
+// mutex to prevent concurrent poll vote file access
+var pollVoteMutex sync.Mutex
+
+// RecordPollVote stores the vote of voterJID in a poll of a chat. A vote
+// replaces the previous vote of the same voter; an empty selection records its
+// withdrawal.
+func RecordPollVote(chatJID, pollID, voterJID string, optionHashes []string, timestamp time.Time) error {
+	if !config.WhatsappChatStorage || pollID == "" {
+		return nil
+	}
+
+	pollVoteMutex.Lock()
+	defer pollVoteMutex.Unlock()
+
+	file, err := os.OpenFile(config.PathPollVotes, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open poll votes file for writing: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	record := []string{chatJID, pollID, voterJID, strings.Join(optionHashes, " "), timestamp.UTC().Format(time.RFC3339)}
+	if err := writer.WriteAll([][]string{record}); err != nil {
+		return fmt.Errorf("failed to write poll vote record: %w", err)
+	}
+	return nil
+}
+
+// PollVotes returns the current votes in a poll of a chat, sorted by voter.
+// Only the latest vote of every voter counts and withdrawn votes are left out.
+func PollVotes(chatJID, pollID string) ([]PollVote, error) {
+	pollVoteMutex.Lock()
+	file, err := os.OpenFile(config.PathPollVotes, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		pollVoteMutex.Unlock()
+		return nil, fmt.Errorf("failed to open poll votes file: %w", err)
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	file.Close()
+	pollVoteMutex.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read poll vote records: %w", err)
+	}
+
+	latest := make(map[string]PollVote)
+	for _, record := range records {
+		if len(record) != 5 || record[0] != chatJID || record[1] != pollID {
+			continue
+		}
+		timestamp, _ := time.Parse(time.RFC3339, record[4])
+		if previous, ok := latest[record[2]]; ok && timestamp.Before(previous.Timestamp) {
+			continue
+		}
+		latest[record[2]] = PollVote{VoterJID: record[2], Options: strings.Fields(record[3]), Timestamp: timestamp}
+	}
+
+	votes := make([]PollVote, 0, len(latest))
+	for _, vote := range latest {
+		if len(vote.Options) > 0 {
+			votes = append(votes, vote)
+		}
+	}
+	sort.Slice(votes, func(i, j int) bool { return votes[i].VoterJID < votes[j].VoterJID })
+	return votes, nil
+}
+
+// mutex to prevent concurrent poll file access
+var pollMutex sync.Mutex
+
+// StoredPoll is a poll as kept by RecordPoll: what names the options of its
+// votes, which only carry option hashes, and what is needed to vote in it.
+type StoredPoll struct {
+	ChatJID         string
+	PollID          string
+	SenderJID       string
+	FromMe          bool
+	SelectableCount int // 0 means any number of options
+	Options         []string
+}
+
+// pollColumns is the number of columns of a poll record before its options.
+const pollColumns = 5
+
+// RecordPoll stores a sent or received poll, so it can be tallied and voted in
+// after a restart.
+func RecordPoll(poll StoredPoll) error {
+	if !config.WhatsappChatStorage || poll.PollID == "" {
+		return nil
+	}
+
+	pollMutex.Lock()
+	defer pollMutex.Unlock()
+
+	file, err := os.OpenFile(config.PathPolls, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open polls file for writing: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	record := append([]string{
+		poll.ChatJID,
+		poll.PollID,
+		poll.SenderJID,
+		strconv.FormatBool(poll.FromMe),
+		strconv.Itoa(poll.SelectableCount),
+	}, poll.Options...)
+	if err := writer.WriteAll([][]string{record}); err != nil {
+		return fmt.Errorf("failed to write poll record: %w", err)
+	}
+	return nil
+}
+
+// FindStoredPoll returns a poll stored by RecordPoll, looking in every chat when
+// chatJID is empty.
+func FindStoredPoll(chatJID, pollID string) (StoredPoll, error) {
+	pollMutex.Lock()
+	file, err := os.OpenFile(config.PathPolls, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		pollMutex.Unlock()
+		return StoredPoll{}, fmt.Errorf("failed to open polls file: %w", err)
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	file.Close()
+	pollMutex.Unlock()
+	if err != nil {
+		return StoredPoll{}, fmt.Errorf("failed to read poll records: %w", err)
+	}
+
+	// The latest record wins, a poll stored twice is the same poll.
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if len(record) < pollColumns || record[1] != pollID || (chatJID != "" && record[0] != chatJID) {
+			continue
+		}
+		fromMe, _ := strconv.ParseBool(record[3])
+		selectable, _ := strconv.Atoi(record[4])
+		return StoredPoll{
+			ChatJID:         record[0],
+			PollID:          record[1],
+			SenderJID:       record[2],
+			FromMe:          fromMe,
+			SelectableCount: selectable,
+			Options:         record[pollColumns:],
+		}, nil
+	}
+	return StoredPoll{}, ErrRecordNotFound
+}
+
+// MediaReference is what is needed to download the media of a stored message
+// later: the serialized media message, holding its direct path and keys.
+type MediaReference struct {
+	ChatJID   string
+	MessageID string
+	Message   string // base64 encoded waE2E.Message with only the media content
+}
+
+// mutex to prevent concurrent media reference file access
+var mediaKeysMutex sync.Mutex
+
+// RecordMediaReferences appends media references to storage.
+func RecordMediaReferences(references []MediaReference) error {
+	if !config.WhatsappChatStorage || len(references) == 0 {
+		return nil
+	}
+
+	mediaKeysMutex.Lock()
+	defer mediaKeysMutex.Unlock()
+
+	file, err := os.OpenFile(config.PathMediaKeys, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open media reference file for writing: %w", err)
+	}
+	defer file.Close()
+
+	records := make([][]string, 0, len(references))
+	for _, reference := range references {
+		records = append(records, []string{reference.ChatJID, reference.MessageID, reference.Message})
+	}
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write media reference records: %w", err)
+	}
+	return nil
+}
+
+// FindMediaReference returns the latest media reference of a message of a chat,
+// or ErrRecordNotFound.
+func FindMediaReference(chatJID, messageID string) (MediaReference, error) {
+	mediaKeysMutex.Lock()
+	file, err := os.OpenFile(config.PathMediaKeys, os.O_RDONLY|os.O_CREATE, 0600)
+	if err != nil {
+		mediaKeysMutex.Unlock()
+		return MediaReference{}, fmt.Errorf("failed to open media reference file: %w", err)
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	file.Close()
+	mediaKeysMutex.Unlock()
+	if err != nil {
+		return MediaReference{}, fmt.Errorf("failed to read media reference records: %w", err)
+	}
+
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if len(record) == 3 && record[0] == chatJID && record[1] == messageID {
+			return MediaReference{ChatJID: record[0], MessageID: record[1], Message: record[2]}, nil
+		}
+	}
+	return MediaReference{}, fmt.Errorf("message ID %s: %w", messageID, ErrRecordNotFound)
+}
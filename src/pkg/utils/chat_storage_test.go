@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	. "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -17,6 +18,12 @@ type ChatStorageTestSuite struct {
 	tempDir     string
 	origStorage bool
 	origPath    string
+	origReceipt string
+	origHistory string
+	origReacts  string
+	origMedia   string
+	origPolls   string
+	origOptions string
 }
 
 func (suite *ChatStorageTestSuite) SetupTest() {
@@ -28,16 +35,34 @@ func (suite *ChatStorageTestSuite) SetupTest() {
 	// Save original config values
 	suite.origStorage = config.WhatsappChatStorage
 	suite.origPath = config.PathChatStorage
+	suite.origReceipt = config.PathChatReceipts
+	suite.origHistory = config.PathChatHistory
+	suite.origReacts = config.PathReactions
+	suite.origMedia = config.PathMediaKeys
+	suite.origPolls = config.PathPollVotes
+	suite.origOptions = config.PathPolls
 
 	// Set test config values
 	config.WhatsappChatStorage = true
 	config.PathChatStorage = filepath.Join(tempDir, "chat_storage.csv")
+	config.PathChatReceipts = filepath.Join(tempDir, "chat_receipts.csv")
+	config.PathChatHistory = filepath.Join(tempDir, "chat_history.csv")
+	config.PathReactions = filepath.Join(tempDir, "chat_reactions.csv")
+	config.PathMediaKeys = filepath.Join(tempDir, "chat_media.csv")
+	config.PathPollVotes = filepath.Join(tempDir, "chat_poll_votes.csv")
+	config.PathPolls = filepath.Join(tempDir, "chat_polls.csv")
 }
 
 func (suite *ChatStorageTestSuite) TearDownTest() {
 	// Restore original config values
 	config.WhatsappChatStorage = suite.origStorage
 	config.PathChatStorage = suite.origPath
+	config.PathChatReceipts = suite.origReceipt
+	config.PathChatHistory = suite.origHistory
+	config.PathReactions = suite.origReacts
+	config.PathMediaKeys = suite.origMedia
+	config.PathPollVotes = suite.origPolls
+	config.PathPolls = suite.origOptions
 
 	// Clean up temp directory
 	os.RemoveAll(suite.tempDir)
@@ -150,6 +175,256 @@ func (suite *ChatStorageTestSuite) TestRecordMessage() {
 	config.PathChatStorage = origPath
 }
 
+func (suite *ChatStorageTestSuite) TestMessageReceipts() {
+	sentAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// Test case: Unknown message
+	_, err := FindMessageReceipt("unknown")
+	assert.ErrorIs(suite.T(), err, ErrRecordNotFound)
+
+	// Test case: Receipt for a message the bot never sent is ignored
+	updated, err := UpdateMessageReceipt("unknown", ReceiptStatusDelivered, sentAt)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), updated)
+
+	// Test case: Sent message starts as "sent"
+	err = RecordSentMessage("sentMsg", "628123@s.whatsapp.net", sentAt)
+	assert.NoError(suite.T(), err)
+	receipt, err := FindMessageReceipt("sentMsg")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ReceiptStatusSent, receipt.Status)
+	assert.Equal(suite.T(), "628123@s.whatsapp.net", receipt.ChatJID)
+	assert.True(suite.T(), sentAt.Equal(receipt.Timestamp))
+
+	// Test case: Status advances
+	readAt := sentAt.Add(time.Minute)
+	updated, err = UpdateMessageReceipt("sentMsg", ReceiptStatusRead, readAt)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), updated)
+
+	// Test case: A late delivered receipt does not downgrade the status
+	updated, err = UpdateMessageReceipt("sentMsg", ReceiptStatusDelivered, readAt.Add(time.Minute))
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), updated)
+
+	receipt, err = FindMessageReceipt("sentMsg")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), ReceiptStatusRead, receipt.Status)
+	assert.True(suite.T(), readAt.Equal(receipt.Timestamp))
+}
+
+func (suite *ChatStorageTestSuite) TestChatHistory() {
+	chatJID := "628123@s.whatsapp.net"
+	first := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// Test case: Empty storage
+	messages, err := GetChatHistory(chatJID, 10)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), messages)
+
+	// Test case: Messages are stored and duplicates in the same chat skipped
+	added, err := RecordChatHistory([]ChatHistoryMessage{
+		{ChatJID: chatJID, MessageID: "h1", SenderJID: chatJID, Timestamp: first, Content: "first"},
+		{ChatJID: chatJID, MessageID: "h2", FromMe: true, Timestamp: first.Add(time.Minute), Content: "second, with comma", Type: "image_message", MediaType: "image/jpeg", MediaPath: "/v/t62/abc"},
+		{ChatJID: "other@g.us", MessageID: "h1", Timestamp: first, Content: "other chat"},
+		{ChatJID: chatJID, MessageID: "h1", Timestamp: first, Content: "duplicate"},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, added)
+
+	added, err = RecordChatHistory([]ChatHistoryMessage{{ChatJID: chatJID, MessageID: "h2", Content: "again"}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, added)
+
+	// Test case: Newest first, filtered by chat
+	messages, err = GetChatHistory(chatJID, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), messages, 2)
+	assert.Equal(suite.T(), "h2", messages[0].MessageID)
+	assert.True(suite.T(), messages[0].FromMe)
+	assert.Equal(suite.T(), "second, with comma", messages[0].Content)
+	assert.Equal(suite.T(), "image/jpeg", messages[0].MediaType)
+	assert.Equal(suite.T(), "/v/t62/abc", messages[0].MediaPath)
+	assert.True(suite.T(), first.Equal(messages[1].Timestamp))
+
+	// Test case: Rows stored before type and media were recorded are still read
+	file, err := os.OpenFile(config.PathChatHistory, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(suite.T(), err)
+	_, err = file.WriteString(chatJID + ",legacy,,false,2024-12-31T00:00:00Z,old\n")
+	assert.NoError(suite.T(), err)
+	file.Close()
+	messages, err = GetChatHistory(chatJID, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), messages, 3)
+	assert.Equal(suite.T(), "legacy", messages[2].MessageID)
+
+	// Test case: Limit
+	messages, err = GetChatHistory(chatJID, 1)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), messages, 1)
+
+	// Test case: Storage disabled
+	config.WhatsappChatStorage = false
+	added, err = RecordChatHistory([]ChatHistoryMessage{{ChatJID: chatJID, MessageID: "h3"}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, added)
+}
+
+func (suite *ChatStorageTestSuite) TestFindChatHistoryMessage() {
+	chatJID := "120363@g.us"
+	_, err := RecordChatHistory([]ChatHistoryMessage{
+		{ChatJID: chatJID, MessageID: "g1", SenderJID: "628123@s.whatsapp.net", Content: "hello group", Type: "text_message"},
+	})
+	assert.NoError(suite.T(), err)
+
+	// Test case: Found
+	message, err := FindChatHistoryMessage(chatJID, "g1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "628123@s.whatsapp.net", message.SenderJID)
+	assert.Equal(suite.T(), "text_message", message.Type)
+
+	// Test case: Same ID in another chat or unknown ID
+	_, err = FindChatHistoryMessage("other@g.us", "g1")
+	assert.ErrorIs(suite.T(), err, ErrRecordNotFound)
+	_, err = FindChatHistoryMessage(chatJID, "unknown")
+	assert.ErrorIs(suite.T(), err, ErrRecordNotFound)
+}
+
+func (suite *ChatStorageTestSuite) TestFindChatHistoryMessages() {
+	_, err := RecordChatHistory([]ChatHistoryMessage{
+		{ChatJID: "628123@s.whatsapp.net", MessageID: "m1", SenderJID: "628123@s.whatsapp.net", Content: "one"},
+		{ChatJID: "120363@g.us", MessageID: "m2", SenderJID: "628456@s.whatsapp.net", Content: "two"},
+		{ChatJID: "120363@g.us", MessageID: "m3", SenderJID: "628789@s.whatsapp.net", Content: "three"},
+	})
+	assert.NoError(suite.T(), err)
+
+	// Messages of other chats are left out, even when their ID is asked for
+	found, err := FindChatHistoryMessages("120363@g.us", []string{"m1", "m2", "unknown"})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), found, 1)
+	assert.Equal(suite.T(), "120363@g.us", found["m2"].ChatJID)
+	assert.Equal(suite.T(), "628456@s.whatsapp.net", found["m2"].SenderJID)
+
+	// An empty chat looks the IDs up in every chat
+	found, err = FindChatHistoryMessages("", []string{"m1", "m2", "unknown"})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), found, 2)
+	assert.Equal(suite.T(), "628123@s.whatsapp.net", found["m1"].ChatJID)
+}
+
+func (suite *ChatStorageTestSuite) TestUpdateChatHistoryContent() {
+	chatJID := "628123@s.whatsapp.net"
+	_, err := RecordChatHistory([]ChatHistoryMessage{
+		{ChatJID: chatJID, MessageID: "e1", SenderJID: chatJID, Content: "helo", Type: "text_message"},
+		{ChatJID: chatJID, MessageID: "e2", SenderJID: chatJID, Content: "other"},
+	})
+	assert.NoError(suite.T(), err)
+
+	updated, err := UpdateChatHistoryContent(chatJID, "e1", "hello")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), updated)
+
+	message, err := FindChatHistoryMessage(chatJID, "e1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "hello", message.Content)
+	assert.Equal(suite.T(), "text_message", message.Type)
+	message, err = FindChatHistoryMessage(chatJID, "e2")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "other", message.Content)
+
+	updated, err = UpdateChatHistoryContent("other@g.us", "e1", "nope")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), updated)
+}
+
+func (suite *ChatStorageTestSuite) TestReactionCounts() {
+	chatJID := "120363@g.us"
+	now := time.Now()
+	_, err := RecordChatHistory([]ChatHistoryMessage{
+		{ChatJID: chatJID, MessageID: "r1", SenderJID: "628123@s.whatsapp.net", Content: "hello", Timestamp: now},
+	})
+	assert.NoError(suite.T(), err)
+
+	assert.NoError(suite.T(), RecordReaction(chatJID, "r1", "628111@s.whatsapp.net", "👍", now))
+	assert.NoError(suite.T(), RecordReaction(chatJID, "r1", "628222@s.whatsapp.net", "👍", now))
+	assert.NoError(suite.T(), RecordReaction(chatJID, "r1", "628333@s.whatsapp.net", "👍", now))
+	// A changed reaction replaces the previous one, a removal drops it
+	assert.NoError(suite.T(), RecordReaction(chatJID, "r1", "628333@s.whatsapp.net", "❤️", now.Add(time.Second)))
+	assert.NoError(suite.T(), RecordReaction(chatJID, "r1", "628222@s.whatsapp.net", "", now.Add(time.Second)))
+	// Reactions of another chat are not counted
+	assert.NoError(suite.T(), RecordReaction("other@g.us", "r1", "628444@s.whatsapp.net", "👍", now))
+
+	message, err := FindChatHistoryMessage(chatJID, "r1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), map[string]int{"👍": 1, "❤️": 1}, message.Reactions)
+}
+
+func (suite *ChatStorageTestSuite) TestFindMediaReference() {
+	chatJID := "628123@s.whatsapp.net"
+	err := RecordMediaReferences([]MediaReference{
+		{ChatJID: chatJID, MessageID: "img1", Message: "first"},
+		{ChatJID: "120363@g.us", MessageID: "img1", Message: "group"},
+		{ChatJID: chatJID, MessageID: "img1", Message: "refreshed"},
+	})
+	assert.NoError(suite.T(), err)
+
+	reference, err := FindMediaReference(chatJID, "img1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "refreshed", reference.Message)
+
+	_, err = FindMediaReference(chatJID, "unknown")
+	assert.ErrorIs(suite.T(), err, ErrRecordNotFound)
+}
+
+func (suite *ChatStorageTestSuite) TestPollVotes() {
+	chatJID := "120363@g.us"
+	now := time.Now().Truncate(time.Second)
+	assert.NoError(suite.T(), RecordPollVote(chatJID, "poll1", "628111@s.whatsapp.net", []string{"aa"}, now))
+	assert.NoError(suite.T(), RecordPollVote(chatJID, "poll1", "628222@s.whatsapp.net", []string{"aa", "bb"}, now))
+	assert.NoError(suite.T(), RecordPollVote(chatJID, "poll1", "628333@s.whatsapp.net", []string{"bb"}, now))
+	// A new vote replaces the previous one, an empty one withdraws it
+	assert.NoError(suite.T(), RecordPollVote(chatJID, "poll1", "628111@s.whatsapp.net", []string{"bb"}, now.Add(time.Second)))
+	assert.NoError(suite.T(), RecordPollVote(chatJID, "poll1", "628333@s.whatsapp.net", nil, now.Add(time.Second)))
+	// Votes in other polls are not counted
+	assert.NoError(suite.T(), RecordPollVote(chatJID, "poll2", "628444@s.whatsapp.net", []string{"aa"}, now))
+
+	votes, err := PollVotes(chatJID, "poll1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []PollVote{
+		{VoterJID: "628111@s.whatsapp.net", Options: []string{"bb"}, Timestamp: now.Add(time.Second).UTC()},
+		{VoterJID: "628222@s.whatsapp.net", Options: []string{"aa", "bb"}, Timestamp: now.UTC()},
+	}, votes)
+}
+
+func (suite *ChatStorageTestSuite) TestFindStoredPoll() {
+	chatJID := "120363@g.us"
+	assert.NoError(suite.T(), RecordPoll(StoredPoll{
+		ChatJID: chatJID, PollID: "poll1", SenderJID: "628123@s.whatsapp.net", SelectableCount: 1,
+		Options: []string{"Yes", "No, thanks"},
+	}))
+	assert.NoError(suite.T(), RecordPoll(StoredPoll{
+		ChatJID: chatJID, PollID: "poll2", SenderJID: "628456@s.whatsapp.net", FromMe: true,
+		Options: []string{"Red", "Blue"},
+	}))
+
+	poll, err := FindStoredPoll(chatJID, "poll1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "628123@s.whatsapp.net", poll.SenderJID)
+	assert.False(suite.T(), poll.FromMe)
+	assert.Equal(suite.T(), 1, poll.SelectableCount)
+	assert.Equal(suite.T(), []string{"Yes", "No, thanks"}, poll.Options)
+
+	// Without a chat, the poll is looked up in every chat
+	poll, err = FindStoredPoll("", "poll2")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), chatJID, poll.ChatJID)
+	assert.True(suite.T(), poll.FromMe)
+	assert.Equal(suite.T(), []string{"Red", "Blue"}, poll.Options)
+
+	_, err = FindStoredPoll("628111@s.whatsapp.net", "poll1")
+	assert.ErrorIs(suite.T(), err, ErrRecordNotFound)
+}
+
 func TestChatStorageTestSuite(t *testing.T) {
 	suite.Run(t, new(ChatStorageTestSuite))
 }
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/google/uuid"
+)
+
+// DeadLetter is a webhook delivery that still failed after every retry.
+type DeadLetter struct {
+	ID        string          `json:"id"`
+	URL       string          `json:"url"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	Error     string          `json:"error"`
+}
+
+// mutex to prevent concurrent dead letter file access
+var deadLetterMutex sync.Mutex
+
+// RecordDeadLetter persists a failed webhook so it can be replayed later.
+func RecordDeadLetter(letter DeadLetter) error {
+	deadLetterMutex.Lock()
+	defer deadLetterMutex.Unlock()
+
+	if letter.ID == "" {
+		letter.ID = uuid.NewString()
+	}
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+
+	file, err := os.OpenFile(config.PathDeadLetters, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead letter file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	return nil
+}
+
+// FindDeadLetters returns the dead letters created within [from, to], oldest
+// first. A zero from or to leaves that side of the range open.
+func FindDeadLetters(from, to time.Time) ([]DeadLetter, error) {
+	deadLetterMutex.Lock()
+	defer deadLetterMutex.Unlock()
+
+	letters, err := readDeadLetters()
+	if err != nil {
+		return nil, err
+	}
+
+	inRange := []DeadLetter{}
+	for _, letter := range letters {
+		if (!from.IsZero() && letter.CreatedAt.Before(from)) || (!to.IsZero() && letter.CreatedAt.After(to)) {
+			continue
+		}
+		inRange = append(inRange, letter)
+	}
+	sort.SliceStable(inRange, func(i, j int) bool {
+		return inRange[i].CreatedAt.Before(inRange[j].CreatedAt)
+	})
+	return inRange, nil
+}
+
+// RemoveDeadLetters deletes the dead letters with the given IDs.
+func RemoveDeadLetters(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	deadLetterMutex.Lock()
+	defer deadLetterMutex.Unlock()
+
+	letters, err := readDeadLetters()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(config.PathDeadLetters, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead letter file for writing: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, letter := range letters {
+		if slices.Contains(ids, letter.ID) {
+			continue
+		}
+		if err := encoder.Encode(letter); err != nil {
+			return fmt.Errorf("failed to write dead letter: %w", err)
+		}
+	}
+	return nil
+}
+
+func readDeadLetters() ([]DeadLetter, error) {
+	file, err := os.OpenFile(config.PathDeadLetters, os.O_RDONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dead letter file: %w", err)
+	}
+	defer file.Close()
+
+	var letters []DeadLetter
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var letter DeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			return nil, fmt.Errorf("failed to read dead letter: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dead letters: %w", err)
+	}
+	return letters, nil
+}
@@ -0,0 +1,86 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	. "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type DeadLetterTestSuite struct {
+	suite.Suite
+	tempDir  string
+	origPath string
+}
+
+func (suite *DeadLetterTestSuite) SetupTest() {
+	tempDir, err := os.MkdirTemp("", "dead_letter_test")
+	assert.NoError(suite.T(), err)
+	suite.tempDir = tempDir
+	suite.origPath = config.PathDeadLetters
+	config.PathDeadLetters = filepath.Join(tempDir, "dead_letters.jsonl")
+}
+
+func (suite *DeadLetterTestSuite) TearDownTest() {
+	config.PathDeadLetters = suite.origPath
+	os.RemoveAll(suite.tempDir)
+}
+
+func (suite *DeadLetterTestSuite) TestDeadLetters() {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// Test case: Empty store
+	letters, err := FindDeadLetters(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), letters)
+
+	// Test case: Letters come back oldest first with their payload intact
+	for i, offset := range []time.Duration{2 * time.Hour, 0, time.Hour} {
+		err = RecordDeadLetter(DeadLetter{
+			ID:        string(rune('a' + i)),
+			URL:       "https://example.com/hook",
+			Payload:   json.RawMessage(`{"Type":"text_message"}`),
+			CreatedAt: base.Add(offset),
+			Error:     "connection refused",
+		})
+		assert.NoError(suite.T(), err)
+	}
+	letters, err = FindDeadLetters(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), letters, 3)
+	assert.Equal(suite.T(), []string{"b", "c", "a"}, []string{letters[0].ID, letters[1].ID, letters[2].ID})
+	assert.JSONEq(suite.T(), `{"Type":"text_message"}`, string(letters[0].Payload))
+
+	// Test case: Range filter is inclusive
+	letters, err = FindDeadLetters(base.Add(time.Hour), base.Add(2*time.Hour))
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), letters, 2)
+	assert.Equal(suite.T(), "c", letters[0].ID)
+
+	// Test case: Generated ID
+	err = RecordDeadLetter(DeadLetter{URL: "https://example.com/hook", CreatedAt: base.Add(3 * time.Hour)})
+	assert.NoError(suite.T(), err)
+	letters, err = FindDeadLetters(base.Add(3*time.Hour), time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), letters, 1)
+	assert.NotEmpty(suite.T(), letters[0].ID)
+
+	// Test case: Removal keeps the other letters
+	err = RemoveDeadLetters([]string{"a", letters[0].ID})
+	assert.NoError(suite.T(), err)
+	letters, err = FindDeadLetters(time.Time{}, time.Time{})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), letters, 2)
+	assert.Equal(suite.T(), "b", letters[0].ID)
+	assert.Equal(suite.T(), "c", letters[1].ID)
+}
+
+func TestDeadLetterTestSuite(t *testing.T) {
+	suite.Run(t, new(DeadLetterTestSuite))
+}
@@ -8,18 +8,25 @@ import (
 	_ "image/jpeg" // For JPEG encoding
 	_ "image/png"  // For PNG encoding
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/sirupsen/logrus"
+	"github.com/skip2/go-qrcode"
 	_ "golang.org/x/image/webp" // Register WebP format
 )
 
@@ -231,6 +238,298 @@ func ContainsMention(message string) []string {
 	return phoneNumbers
 }
 
+// AppendSignature appends the configured message signature to an outbound text or caption.
+// Appending at the end keeps mention offsets and reply context untouched.
+func AppendSignature(text string, skip bool) string {
+	if skip || config.WhatsappMessageSignature == "" {
+		return text
+	}
+	if text == "" {
+		return config.WhatsappMessageSignature
+	}
+	return text + "\n\n" + config.WhatsappMessageSignature
+}
+
+// MaxCaptionLength is the longest media caption, in characters, WhatsApp clients show.
+const MaxCaptionLength = 1024
+
+// AppendCaptionSignature is AppendSignature for media captions. When the result
+// would exceed MaxCaptionLength the caption is cut, marked with an ellipsis, so
+// that the signature, which takes precedence, is kept whole.
+func AppendCaptionSignature(caption string, skip bool) string {
+	signed := AppendSignature(caption, skip)
+	if utf8.RuneCountInString(signed) <= MaxCaptionLength || skip || config.WhatsappMessageSignature == "" {
+		return signed
+	}
+	suffix := "…\n\n" + config.WhatsappMessageSignature
+	room := MaxCaptionLength - utf8.RuneCountInString(suffix)
+	if room <= 0 {
+		return config.WhatsappMessageSignature
+	}
+	return strings.TrimRightFunc(string([]rune(caption)[:room]), unicode.IsSpace) + suffix
+}
+
+// SendDelay returns the humanization delay to wait before sending a message of
+// textLength characters. Longer texts wait closer to the configured maximum;
+// jitter is a value in [0, 1) that spreads the delay by ±20%.
+func SendDelay(textLength int, jitter float64) time.Duration {
+	maxDelay := config.WhatsappSendMaxDelayMs
+	minDelay := min(config.WhatsappSendMinDelayMs, maxDelay)
+	if maxDelay <= 0 {
+		return 0
+	}
+
+	const fullLength = 280
+	proportion := float64(min(textLength, fullLength)) / fullLength
+	delay := (float64(minDelay) + float64(maxDelay-minDelay)*proportion) * (0.8 + 0.4*jitter)
+	delay = max(float64(minDelay), min(float64(maxDelay), delay))
+	return time.Duration(delay) * time.Millisecond
+}
+
+// WebhookURLsForChat returns the webhook URLs for a chat. Every route in
+// config.WhatsappWebhookRoutes whose pattern matches the chat JID contributes its
+// URL; patterns use path.Match syntax such as "*@g.us". Without a match the
+// global config.WhatsappWebhook list is used.
+func WebhookURLsForChat(chatJID string) []string {
+	var urls []string
+	for _, route := range config.WhatsappWebhookRoutes {
+		pattern, target, ok := strings.Cut(strings.TrimSpace(route), "=")
+		if !ok || pattern == "" || target == "" {
+			logrus.Warnf("Ignoring invalid webhook route %q, expected <pattern>=<url>", route)
+			continue
+		}
+		matched, err := path.Match(pattern, chatJID)
+		if err != nil {
+			logrus.Warnf("Ignoring webhook route %q: %v", route, err)
+			continue
+		}
+		if matched && !slices.Contains(urls, target) {
+			urls = append(urls, target)
+		}
+	}
+	if len(urls) == 0 {
+		return config.WhatsappWebhook
+	}
+	return urls
+}
+
+// WebhookHeadersForURL returns the configured extra headers for a webhook URL.
+// Entries of config.WhatsappWebhookHeaders are "Name: value", sent to every
+// webhook, or "<url>|Name: value", sent only to that URL.
+func WebhookHeadersForURL(webhookURL string) http.Header {
+	headers := http.Header{}
+	for _, entry := range config.WhatsappWebhookHeaders {
+		entry = strings.TrimSpace(entry)
+		if target, header, ok := strings.Cut(entry, "|"); ok {
+			if strings.TrimSpace(target) != webhookURL {
+				continue
+			}
+			entry = header
+		}
+		name, value, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			logrus.Warnf("Ignoring invalid webhook header %q, expected [<url>|]<name>: <value>", entry)
+			continue
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers
+}
+
+// MatchesJIDPattern reports whether the JID matches any of the path.Match
+// patterns, e.g. "*@g.us" for every group. Invalid patterns never match.
+func MatchesJIDPattern(jid string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(strings.TrimSpace(pattern), jid); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// RecipientAllowed applies the configured recipient policy to a JID. A match in
+// the deny list always blocks; a non-empty allow list blocks everything it does
+// not match. With both lists empty every recipient is allowed.
+func RecipientAllowed(jid string) bool {
+	if MatchesJIDPattern(jid, config.WhatsappRecipientDeny) {
+		return false
+	}
+	return len(config.WhatsappRecipientAllow) == 0 || MatchesJIDPattern(jid, config.WhatsappRecipientAllow)
+}
+
+// phoneSeparators are the formatting characters people put in phone numbers.
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// NormalizePhone turns a phone number as typed by a user, e.g. "+62 (812) 3456-7890",
+// "0062812..." or "62812...@s.whatsapp.net", into its international digits. It
+// does not add a country code, so local numbers with a leading 0 are rejected.
+func NormalizePhone(phone string) (string, error) {
+	digits := strings.TrimSuffix(strings.TrimSpace(phone), config.WhatsappTypeUser)
+	digits = phoneSeparators.Replace(digits)
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits = digits[1:]
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	}
+
+	if digits == "" {
+		return "", fmt.Errorf("phone is empty")
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("phone %q contains %q, only digits are allowed", phone, r)
+		}
+	}
+	if digits[0] == '0' {
+		return "", fmt.Errorf("phone %q must start with the country code", phone)
+	}
+	if len(digits) < 7 || len(digits) > 15 {
+		return "", fmt.Errorf("phone %q must have between 7 and 15 digits", phone)
+	}
+	return digits, nil
+}
+
+// WaMeLink builds a click-to-chat link for a phone, with optional prefilled text.
+func WaMeLink(phone, text string) (string, error) {
+	digits, err := NormalizePhone(phone)
+	if err != nil {
+		return "", err
+	}
+	link := "https://wa.me/" + digits
+	if text != "" {
+		// wa.me wants spaces as %20, QueryEscape turns them into "+"
+		link += "?text=" + strings.ReplaceAll(url.QueryEscape(text), "+", "%20")
+	}
+	return link, nil
+}
+
+// TerminalQR renders a QR code with Unicode half blocks, two modules per
+// character row, so it fits a terminal and scans from the screen. Terminals
+// with a dark background need invert to show dark modules on a light quiet zone.
+func TerminalQR(content string, invert bool) (string, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+	return qr.ToSmallString(invert), nil
+}
+
+// RedactURL hides credentials in a URL so it can be shown in diagnostics: the
+// userinfo password and every query value are replaced with "xxxxx".
+func RedactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "xxxxx"
+	}
+	if parsed.User != nil {
+		if _, hasPassword := parsed.User.Password(); hasPassword {
+			parsed.User = url.UserPassword(parsed.User.Username(), "xxxxx")
+		}
+	}
+	if parsed.RawQuery != "" {
+		query := parsed.Query()
+		for key := range query {
+			query[key] = []string{"xxxxx"}
+		}
+		parsed.RawQuery = query.Encode()
+	}
+	return parsed.String()
+}
+
+// SafeJoin joins a client-provided path onto base and verifies the result stays
+// inside base, including after resolving symlinks of an existing file. It is
+// the only way request values should become filesystem paths.
+func SafeJoin(base, userPath string) (string, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+	joined := filepath.Join(absBase, filepath.FromSlash(userPath))
+	if !isWithin(absBase, joined) {
+		return "", fmt.Errorf("path %q escapes %s", userPath, base)
+	}
+
+	resolved, err := filepath.EvalSymlinks(joined)
+	if os.IsNotExist(err) {
+		return joined, nil
+	} else if err != nil {
+		return "", err
+	}
+	if realBase, err := filepath.EvalSymlinks(absBase); err == nil {
+		absBase = realBase
+	}
+	if !isWithin(absBase, resolved) {
+		return "", fmt.Errorf("path %q escapes %s through a symlink", userPath, base)
+	}
+	return resolved, nil
+}
+
+func isWithin(base, target string) bool {
+	rel, err := filepath.Rel(base, target)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+var templatePlaceholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// escapeFormattingMarkers inserts a zero-width space after the WhatsApp
+// formatting markers of value that could open or close a run: those starting a
+// word, followed by text, or ending one, followed by a space or the end. The
+// value boundaries count as spaces, the template decides what surrounds them.
+// Markers inside a word, like the underscores of an ID or URL, are left alone.
+func escapeFormattingMarkers(value string) string {
+	runes := []rune(value)
+	var escaped strings.Builder
+	for i, r := range runes {
+		escaped.WriteRune(r)
+		if !strings.ContainsRune("*_~`", r) {
+			continue
+		}
+		spaceBefore := i == 0 || unicode.IsSpace(runes[i-1])
+		spaceAfter := i == len(runes)-1 || unicode.IsSpace(runes[i+1])
+		if spaceBefore != spaceAfter {
+			escaped.WriteRune('\u200b')
+		}
+	}
+	return escaped.String()
+}
+
+// RenderTemplate renders a message template such as "Hi {{name}}" with Go's
+// text/template. Variable values are escaped so they cannot toggle WhatsApp
+// bold/italic/strike/monospace formatting, see escapeFormattingMarkers. When strict is set, referencing a
+// variable that was not provided is an error; otherwise it renders empty.
+func RenderTemplate(text string, variables map[string]string, strict bool) (string, error) {
+	// Accept the {{name}} shorthand next to the native {{.name}} syntax.
+	text = templatePlaceholderRegex.ReplaceAllStringFunc(text, func(match string) string {
+		name := templatePlaceholderRegex.FindStringSubmatch(match)[1]
+		if slices.Contains([]string{"end", "else", "nil", "true", "false"}, name) {
+			return match
+		}
+		return "{{." + name + "}}"
+	})
+
+	missingKey := "missingkey=zero"
+	if strict {
+		missingKey = "missingkey=error"
+	}
+	tmpl, err := template.New("message").Option(missingKey).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	escaped := make(map[string]string, len(variables))
+	for key, value := range variables {
+		escaped[key] = escapeFormattingMarkers(value)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, escaped); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return rendered.String(), nil
+}
+
 func DownloadImageFromURL(url string) ([]byte, string, error) {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
@@ -277,3 +576,65 @@ func DownloadImageFromURL(url string) ([]byte, string, error) {
 	}
 	return imageData, fileName, nil
 }
+
+// preferredMediaExtensions pins the extension of common WhatsApp mime types,
+// where the system mime table may list an unusual one first (e.g. ".jfif").
+var preferredMediaExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"image/gif":       ".gif",
+	"audio/ogg":       ".ogg",
+	"audio/mpeg":      ".mp3",
+	"audio/mp4":       ".m4a",
+	"audio/aac":       ".aac",
+	"audio/amr":       ".amr",
+	"video/mp4":       ".mp4",
+	"video/3gpp":      ".3gp",
+	"video/quicktime": ".mov",
+	"application/pdf": ".pdf",
+}
+
+var unsafeExtensionChars = regexp.MustCompile(`[^a-z0-9]`)
+
+// SniffAudioMimeType recognizes OGG (Opus included), WAV, MP3 and ADTS AAC data
+// by its header bytes, for files whose extension and http.DetectContentType say
+// nothing useful. It returns "" for anything else.
+func SniffAudioMimeType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return "audio/ogg"
+	case len(data) >= 12 && bytes.HasPrefix(data, []byte("RIFF")) && string(data[8:12]) == "WAVE":
+		return "audio/wav"
+	case bytes.HasPrefix(data, []byte("ID3")):
+		return "audio/mpeg"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xF6 == 0xF0:
+		// 12 sync bits followed by layer 00 is an ADTS header.
+		return "audio/aac"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0 && data[1]&0x06 != 0:
+		// 11 sync bits and a valid layer is an MPEG audio frame.
+		return "audio/mpeg"
+	}
+	return ""
+}
+
+// MediaExtension returns the file extension, with a leading dot, for a mime
+// type such as "audio/ogg; codecs=opus". Unknown types get ".bin".
+func MediaExtension(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	}
+	if extension, ok := preferredMediaExtensions[mediaType]; ok {
+		return extension
+	}
+	if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+	if parts := strings.Split(mediaType, "/"); len(parts) == 2 {
+		if subtype := unsafeExtensionChars.ReplaceAllString(strings.TrimPrefix(parts[1], "x-"), ""); subtype != "" {
+			return "." + subtype
+		}
+	}
+	return ".bin"
+}
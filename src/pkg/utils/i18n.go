@@ -0,0 +1,575 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+const DefaultLanguage = "en"
+
+// messageCatalog holds every user-facing response string by language and key.
+// Entries are fmt format strings; T fills in the arguments.
+var messageCatalog = map[string]map[string]string{
+	"en": {
+		"invalid_request_body":                       "Invalid request body",
+		"phone_or_jid_required":                      "Phone or Jid is required",
+		"phone_required":                             "Phone is required",
+		"message_required":                           "message is required",
+		"client_not_initialized":                     "WhatsApp client not initialized",
+		"client_not_connected":                       "WhatsApp client not connected or logged in",
+		"invalid_phone":                              "Invalid Phone: %v",
+		"invalid_jid":                                "Invalid JID: %v",
+		"invalid_mention":                            "Invalid mention %q: %v",
+		"invalid_sender_jid":                         "Invalid sender JID: %v",
+		"invalid_reply_participant":                  "Invalid reply_participant: %v",
+		"reply_participant_required":                 "reply_participant is required to quote a group message",
+		"message_sent":                               "Message sent",
+		"text_sent":                                  "Text sent",
+		"send_message_failed":                        "Failed to send message: %v",
+		"phone_and_presence_required":                "Phone and presence are required",
+		"invalid_presence":                           "Invalid presence type, must be 'typing' or 'recording'",
+		"send_presence_failed":                       "Failed to send presence: %v",
+		"presence_sent":                              "Presence %s sent to %s",
+		"call_id_and_phone_required":                 "call_id and Phone are required",
+		"reject_call_failed":                         "Failed to reject call: %v",
+		"invalid_base64":                             "Invalid Base64 format",
+		"decode_base64_failed":                       "Failed to decode Base64: %v",
+		"file_not_found":                             "File not found",
+		"file_not_found_path":                        "File not found: %s",
+		"read_file_failed":                           "Failed to read file: %v",
+		"unsupported_audio_format":                   "Unsupported audio format: %s",
+		"send_audio_failed":                          "Failed to send audio message: %v",
+		"audio_sent":                                 "Audio sent",
+		"phone_and_document_required":                "Phone and DocumentPath are required",
+		"document_too_large":                         "Document size exceeds the maximum limit of %d bytes",
+		"send_document_failed":                       "Failed to send document message: %v",
+		"document_sent":                              "Document sent",
+		"phone_and_video_required":                   "Phone and VideoPath are required",
+		"video_too_large":                            "Video size exceeds the maximum limit of %d bytes",
+		"send_video_failed":                          "Failed to send video message: %v",
+		"video_sent":                                 "Video sent",
+		"phone_and_image_required":                   "Phone and ImagePath are required",
+		"image_too_large":                            "Image size exceeds the maximum limit of %d bytes",
+		"send_image_failed":                          "Failed to send image message: %v",
+		"image_sent":                                 "Image sent",
+		"location_required":                          "Phone, latitude, and longitude are required",
+		"send_location_failed":                       "Failed to send location message: %v",
+		"location_sent":                              "Location sent",
+		"phone_and_message_id_required":              "Phone and message_id are required",
+		"delete_not_allowed":                         "Message deletion not allowed: likely too old or not sent by you",
+		"revoke_failed":                              "Failed to revoke message: %v",
+		"message_deleted":                            "Message %s deleted",
+		"sender_required_for_group":                  "Sender is required for group chats",
+		"mark_read_failed":                           "Failed to mark message as read: %v",
+		"message_marked_read":                        "Message %s marked as read",
+		"message_not_sent_by_device":                 "Message %s was not sent by this device to %s",
+		"read_message_status_failed":                 "Failed to read message status: %v",
+		"limit_out_of_range":                         "limit must be between %d and %d",
+		"read_chat_history_failed":                   "Failed to read chat history: %v",
+		"invalid_export_format":                      "format must be json or csv",
+		"invalid_from":                               "Invalid from: %v",
+		"invalid_to":                                 "Invalid to: %v",
+		"path_required":                              "path is required",
+		"path_outside_media":                         "Path is outside the media directory",
+		"raw_message_disabled":                       "Raw messages are disabled on this server",
+		"raw_message_sent":                           "Raw message sent",
+		"replay_webhooks_failed":                     "Failed to replay webhooks: %v",
+		"message_not_found":                          "Message %s not found in chat %s",
+		"read_message_failed":                        "Failed to read message: %v",
+		"invalid_thumbnail":                          "thumbnail must be a base64 encoded JPEG",
+		"label_name_required":                        "name is required",
+		"fetch_labels_failed":                        "Failed to fetch labels: %v",
+		"create_label_failed":                        "Failed to create label: %v",
+		"phone_and_label_required":                   "Phone and label_id are required",
+		"invalid_label_action":                       "action must be add or remove",
+		"label_chat_failed":                          "Failed to update chat label: %v",
+		"chat_labeled":                               "Label %s added to %s",
+		"chat_unlabeled":                             "Label %s removed from %s",
+		"link_preview_conflict":                      "link_preview and disable_link_preview cannot be used together",
+		"logs_require_auth":                          "recent logs are only available when basic auth is enabled",
+		"message_not_in_chat":                        "message %s belongs to chat %s, not %s",
+		"messages_marked_read":                       "%d messages marked as read",
+		"poll_not_found":                             "poll %s is unknown, only polls sent or received since the server started can be voted",
+		"poll_wrong_chat":                            "poll %s belongs to chat %s",
+		"poll_option_index_out_of_range":             "option index %d is out of range, the poll has %d options",
+		"poll_option_unknown":                        "option %q is not part of the poll",
+		"poll_options_required":                      "at least one option must be selected",
+		"poll_too_many_options":                      "%d options selected but the poll allows at most %d",
+		"poll_vote_failed":                           "Failed to vote in poll: %v",
+		"poll_voted":                                 "Vote sent",
+		"recipient_not_allowed":                      "Recipient %s is not allowed by the recipient policy",
+		"message_media_not_found":                    "Message %s in %s has no stored media",
+		"download_media_failed":                      "Failed to download media: %v",
+		"request_location_default":                   "Please share your location",
+		"request_location_sent":                      "Location request sent",
+		"request_phone_sent":                         "Phone number request sent",
+		"task_not_found":                             "Task %s not found",
+		"task_not_cancellable":                       "Task %s cannot be cancelled",
+		"task_cancelled":                             "Task %s cancelled",
+		"reply_chat_requires_message_id":             "reply_chat requires reply_message_id",
+		"invalid_reply_chat":                         "Invalid reply chat: %v",
+		"reply_message_not_found":                    "Message %s not found in the history of %s",
+		"client_reconnecting":                        "WhatsApp client is reconnecting, retry shortly",
+		"phone_and_product_required":                 "Phone and product_id are required",
+		"product_not_found":                          "Product %s not found in the catalog",
+		"catalog_query_failed":                       "Failed to query the catalog: %v",
+		"product_sent":                               "Product sent",
+		"invalid_max_size":                           "max_size must be a non-negative number of bytes, got %q",
+		"prewarm_phones_required":                    "phones is required, with at most %d numbers",
+		"live_location_duration_invalid":             "duration_seconds must be between 1 and %d",
+		"live_location_sent":                         "Live location sent",
+		"auto_delete_after_invalid":                  "auto_delete_after must be between 0 and %d seconds",
+		"chat_and_id_required":                       "chat and id are required",
+		"no_webhook_for_chat":                        "No webhook is configured for chat %s",
+		"resend_webhook_failed":                      "Failed to resend webhook: %v",
+		"flow_required":                              "Phone, flow_id, cta and body are required",
+		"flow_sent":                                  "Flow %s sent",
+		"set_default_disappearing_failed":            "Failed to set the default disappearing timer: %v",
+		"read_poll_votes_failed":                     "Failed to read poll votes: %v",
+		"poll_results_not_found":                     "No poll %s or votes for it are known in this chat",
+		"webhook_secret_requires_auth":               "the webhook secret can only be rotated when basic auth is enabled",
+		"rotate_webhook_secret_failed":               "failed to rotate the webhook secret: %v",
+		"contact_phone_required":                     "Phone and contact_phone are required",
+		"invalid_contact_phone":                      "Invalid contact_phone: %v",
+		"contact_sent":                               "Contact %s sent",
+		"already_paired":                             "already paired, log out first to pair again",
+		"unsupported_qr_format":                      "format must be png, ascii or text",
+		"qr_failed":                                  "failed to get the pairing QR code: %v",
+		"call_cache_key_not_found":                   "no call dedupe entry for key %s",
+		"duplicate_message_id":                       "message_id %s was already used, pick a new one",
+		"mention_all_failed":                         "Failed to mention the group members: %v",
+		"poll_results_disabled":                      "Poll results need chat storage, which is disabled on this server",
+		"ephemeral_seconds_invalid":                  "ephemeral_seconds must be one of 86400, 604800 or 7776000",
+		"ephemeral_timer_conflict":                   "ephemeral_seconds %d conflicts with the chat disappearing timer of %d seconds",
+		"view_once_audio_invalid":                    "view_once audio must be an OGG/Opus voice note, got %s",
+		"view_once_unsupported":                      "view_once is not supported for %s messages",
+		"message_id_length_invalid":                  "message_id must have %d to %d characters",
+		"message_id_charset_invalid":                 "message_id must only hold uppercase hexadecimal characters, got %q",
+		"default_disappearing_invalid":               "seconds must be one of 0, 86400, 604800 or 7776000",
+		"cta_button_count_invalid":                   "between 1 and %d buttons are required, got %d",
+		"cta_button_text_invalid":                    "button %d: text must have 1 to %d characters",
+		"cta_button_url_invalid":                     "button %d: url must be an http or https URL",
+		"cta_button_phone_invalid":                   "button %d: phone must be a number in international format",
+		"cta_button_id_required":                     "button %d: id is required for reply buttons",
+		"cta_button_type_invalid":                    "button %d: type must be url, call or reply",
+		"webhook_secret_too_short":                   "secret must have at least %d characters",
+		"webhook_secret_grace_invalid":               "grace_seconds must be between 0 and %d",
+		"login_success":                              "Login success",
+		"login_with_code_success":                    "Login with code success",
+		"logout_success":                             "Success logout",
+		"reconnect_success":                          "Reconnect success",
+		"devices_fetched":                            "Fetch device success",
+		"linked_devices_fetched":                     "Fetch linked devices success",
+		"device_logged_out":                          "Device logout success",
+		"info_fetched":                               "Fetch info success",
+		"community_groups_fetched":                   "Success get community groups",
+		"community_group_linked":                     "Success link group to community",
+		"community_group_unlinked":                   "Success unlink group from community",
+		"group_joined":                               "Success joined group",
+		"group_link_info_fetched":                    "Success get group info from link",
+		"group_left":                                 "Success leave group",
+		"group_refreshed":                            "Success refresh group",
+		"group_id_required":                          "Group ID cannot be empty",
+		"group_participant_requests_fetched":         "Success getting list requested participants",
+		"message_deleted_for_me":                     "Message deleted successfully",
+		"message_starred":                            "Starred message successfully",
+		"message_unstarred":                          "Unstarred message successfully",
+		"newsletter_unfollowed":                      "Success unfollow newsletter",
+		"newsletter_messages_fetched":                "Success get newsletter messages",
+		"newsletter_reacted":                         "Success react to newsletter message",
+		"user_info_fetched":                          "Success get user info",
+		"avatar_fetched":                             "Success get avatar",
+		"avatar_changed":                             "Success change avatar",
+		"privacy_fetched":                            "Success get privacy",
+		"groups_listed":                              "Success get list groups",
+		"newsletters_listed":                         "Success get list newsletter",
+		"contacts_listed":                            "Success get list contacts",
+		"push_name_changed":                          "Success change push name",
+		"jid_resolved":                               "Success resolve jid",
+		"user_blocked":                               "Success block user",
+		"user_unblocked":                             "Success unblock user",
+		"blocklist_fetched":                          "Success get blocklist",
+		"business_profile_fetched":                   "Success get business profile",
+		"catalog_fetched":                            "Success get catalog",
+		"contacts_exported":                          "Success export contacts",
+		"user_presence_fetched":                      "Success get user presence",
+		"device_not_linked":                          "device %d is not linked to this account",
+		"primary_device_logout_denied":               "the primary phone can not be logged out from a linked device",
+		"device_logout_primary_only":                 "device %d can only be logged out from the primary phone, WhatsApp does not let a linked device remove other linked devices",
+		"device_id_invalid":                          "device_id %s is neither a device number nor a device of this account",
+		"group_id_not_group":                         "group_id: must be a group JID.",
+		"participant_action_failed":                  "Action %s failed (code %d)",
+		"participant_action_success":                 "Action %s success",
+		"media_too_large":                            "media size exceeds the maximum limit of %d bytes",
+		"upload_media_failed":                        "failed to upload media: %v",
+		"mark_read_success":                          "Mark as read success %s",
+		"reaction_sent_to":                           "Reaction sent to %s (server timestamp: %s)",
+		"revoke_success":                             "Revoke success %s (server timestamp: %s)",
+		"update_message_success":                     "Update message success %s (server timestamp: %s)",
+		"message_sent_to":                            "Message sent to %s (server timestamp: %s)",
+		"download_image_failed":                      "failed to download image from URL %v",
+		"save_downloaded_image_failed":               "failed to save downloaded image %v",
+		"open_image_failed":                          "failed to open image %v",
+		"save_thumbnail_failed":                      "failed to save thumbnail %v",
+		"save_image_failed":                          "failed to save image %v",
+		"read_thumbnail_failed":                      "failed to read thumbnail %v",
+		"document_sent_to":                           "Document sent to %s (server timestamp: %s)",
+		"store_video_failed":                         "failed to store video in server %v",
+		"ffmpeg_not_installed":                       "ffmpeg not installed",
+		"create_thumbnail_failed":                    "failed to create thumbnail %v",
+		"compress_video_failed":                      "failed to compress video",
+		"upload_file_failed":                         "Failed to upload file: %v",
+		"video_sent_to":                              "Video sent to %s (server timestamp: %s)",
+		"contact_sent_to":                            "Contact sent to %s (server timestamp: %s)",
+		"link_sent_to":                               "Link sent to %s (server timestamp: %s)",
+		"location_sent_to":                           "Send location success %s (server timestamp: %s)",
+		"upload_audio_failed":                        "Failed to upload audio: %v",
+		"audio_sent_to":                              "Send audio success %s (server timestamp: %s)",
+		"poll_sent_to":                               "Send poll success %s (server timestamp: %s)",
+		"presence_type_sent":                         "Send presence success %s",
+		"avatar_timeout":                             "Error timeout get avatar !",
+		"read_lid_mapping_failed":                    "failed to read lid mapping: %v",
+		"presence_not_received":                      "no presence received from %s yet, updates arrive once the contact's presence is subscribed",
+		"contact_export_disabled":                    "contact export is disabled on this server",
+		"image_or_url_required":                      "either Image or ImageURL must be provided",
+		"image_type_not_allowed":                     "your image is not allowed. please use jpg/jpeg/png",
+		"image_url_required":                         "ImageURL cannot be empty",
+		"image_url_invalid":                          "ImageURL must be a valid URL",
+		"file_upload_too_large":                      "max file upload is %s, please upload in cloud and send via text if your file is higher than %s",
+		"video_type_not_allowed":                     "your video type is not allowed. please use mp4/mkv/avi",
+		"video_upload_too_large":                     "max video upload is %s, please upload in cloud and send via text if your file is higher than %s",
+		"audio_type_not_allowed":                     "your audio type is not allowed. please use (%s)",
+		"poll_options_not_unique":                    "options should be unique",
+		"required_with_reply_chat":                   "is required with reply_chat",
+		"validation_required":                        "cannot be blank",
+		"validation_min_greater_equal_than_required": "must be no less than {{.threshold}}",
+		"validation_max_less_equal_than_required":    "must be no greater than {{.threshold}}",
+		"validation_in_invalid":                      "must be a valid value",
+		"validation_match_invalid":                   "must be in a valid format",
+		"validation_length_too_long":                 "the length must be no more than {{.max}}",
+		"validation_length_too_short":                "the length must be no less than {{.min}}",
+		"validation_length_invalid":                  "the length must be exactly {{.min}}",
+		"validation_length_out_of_range":             "the length must be between {{.min}} and {{.max}}",
+		"validation_is_url":                          "must be a valid URL",
+		"validation_is_latitude":                     "must be a valid latitude",
+		"validation_is_longitude":                    "must be a valid longitude",
+		"group_created":                              "Success created group with id %s",
+		"participants_added":                         "Success add participants",
+		"participants_removed":                       "Success delete participants",
+		"participants_promoted":                      "Success promote participants",
+		"participants_demoted":                       "Success demote participants",
+		"participant_requests_approved":              "Success approve requested participants",
+		"participant_requests_rejected":              "Success reject requested participants",
+		"group_broadcast_processed":                  "Broadcast processed for %d groups",
+		"broadcast_processed":                        "Broadcast processed for %d recipients",
+		"avatar_not_found":                           "no avatar found",
+		"invite_link_revoked":                        "the group invite link has been revoked",
+		"invite_link_invalid":                        "the group invite link is invalid or expired",
+		"not_group_member":                           "not a member of this group",
+		"group_admins_only":                          "only admins can send messages to this group",
+		"duplicate_recipient":                        "duplicate recipient",
+		"resolve_jid_unsupported":                    "only user or lid jids can be resolved",
+		"participant_add_failed":                     "Failed to add participant",
+		"action_success":                             "Action success",
+	},
+	"pt": {
+		"invalid_request_body":                       "Corpo da requisição inválido",
+		"phone_or_jid_required":                      "Phone ou Jid é obrigatório",
+		"phone_required":                             "Phone é obrigatório",
+		"message_required":                           "message é obrigatório",
+		"client_not_initialized":                     "Cliente WhatsApp não inicializado",
+		"client_not_connected":                       "Cliente WhatsApp não conectado ou sem login",
+		"invalid_phone":                              "Phone inválido: %v",
+		"invalid_jid":                                "JID inválido: %v",
+		"invalid_mention":                            "Menção inválida %q: %v",
+		"invalid_sender_jid":                         "JID do remetente inválido: %v",
+		"invalid_reply_participant":                  "reply_participant inválido: %v",
+		"reply_participant_required":                 "reply_participant é obrigatório para citar uma mensagem de grupo",
+		"message_sent":                               "Mensagem enviada",
+		"text_sent":                                  "Texto enviado",
+		"send_message_failed":                        "Falha ao enviar mensagem: %v",
+		"phone_and_presence_required":                "Phone e presence são obrigatórios",
+		"invalid_presence":                           "Tipo de presença inválido, use 'typing' ou 'recording'",
+		"send_presence_failed":                       "Falha ao enviar presença: %v",
+		"presence_sent":                              "Presença %s enviada para %s",
+		"call_id_and_phone_required":                 "call_id e Phone são obrigatórios",
+		"reject_call_failed":                         "Falha ao rejeitar chamada: %v",
+		"invalid_base64":                             "Formato Base64 inválido",
+		"decode_base64_failed":                       "Falha ao decodificar Base64: %v",
+		"file_not_found":                             "Arquivo não encontrado",
+		"file_not_found_path":                        "Arquivo não encontrado: %s",
+		"read_file_failed":                           "Falha ao ler arquivo: %v",
+		"unsupported_audio_format":                   "Formato de áudio não suportado: %s",
+		"send_audio_failed":                          "Falha ao enviar mensagem de áudio: %v",
+		"audio_sent":                                 "Áudio enviado",
+		"phone_and_document_required":                "Phone e DocumentPath são obrigatórios",
+		"document_too_large":                         "O documento excede o limite máximo de %d bytes",
+		"send_document_failed":                       "Falha ao enviar documento: %v",
+		"document_sent":                              "Documento enviado",
+		"phone_and_video_required":                   "Phone e VideoPath são obrigatórios",
+		"video_too_large":                            "O vídeo excede o limite máximo de %d bytes",
+		"send_video_failed":                          "Falha ao enviar vídeo: %v",
+		"video_sent":                                 "Vídeo enviado",
+		"phone_and_image_required":                   "Phone e ImagePath são obrigatórios",
+		"image_too_large":                            "A imagem excede o limite máximo de %d bytes",
+		"send_image_failed":                          "Falha ao enviar imagem: %v",
+		"image_sent":                                 "Imagem enviada",
+		"location_required":                          "Phone, latitude e longitude são obrigatórios",
+		"send_location_failed":                       "Falha ao enviar localização: %v",
+		"location_sent":                              "Localização enviada",
+		"phone_and_message_id_required":              "Phone e message_id são obrigatórios",
+		"delete_not_allowed":                         "Exclusão não permitida: a mensagem provavelmente é antiga demais ou não foi enviada por você",
+		"revoke_failed":                              "Falha ao apagar mensagem: %v",
+		"message_deleted":                            "Mensagem %s apagada",
+		"sender_required_for_group":                  "Sender é obrigatório para grupos",
+		"mark_read_failed":                           "Falha ao marcar mensagem como lida: %v",
+		"message_marked_read":                        "Mensagem %s marcada como lida",
+		"message_not_sent_by_device":                 "A mensagem %s não foi enviada por este dispositivo para %s",
+		"read_message_status_failed":                 "Falha ao ler status da mensagem: %v",
+		"limit_out_of_range":                         "limit deve estar entre %d e %d",
+		"read_chat_history_failed":                   "Falha ao ler histórico do chat: %v",
+		"invalid_export_format":                      "format deve ser json ou csv",
+		"invalid_from":                               "from inválido: %v",
+		"invalid_to":                                 "to inválido: %v",
+		"path_required":                              "path é obrigatório",
+		"path_outside_media":                         "O caminho está fora do diretório de mídia",
+		"raw_message_disabled":                       "Mensagens raw estão desativadas neste servidor",
+		"raw_message_sent":                           "Mensagem raw enviada",
+		"replay_webhooks_failed":                     "Falha ao reenviar webhooks: %v",
+		"message_not_found":                          "Mensagem %s não encontrada no chat %s",
+		"read_message_failed":                        "Falha ao ler mensagem: %v",
+		"invalid_thumbnail":                          "thumbnail deve ser um JPEG em base64",
+		"label_name_required":                        "name é obrigatório",
+		"fetch_labels_failed":                        "Falha ao buscar etiquetas: %v",
+		"create_label_failed":                        "Falha ao criar etiqueta: %v",
+		"phone_and_label_required":                   "Phone e label_id são obrigatórios",
+		"invalid_label_action":                       "action deve ser add ou remove",
+		"label_chat_failed":                          "Falha ao atualizar etiqueta do chat: %v",
+		"chat_labeled":                               "Etiqueta %s adicionada a %s",
+		"chat_unlabeled":                             "Etiqueta %s removida de %s",
+		"link_preview_conflict":                      "link_preview e disable_link_preview não podem ser usados juntos",
+		"logs_require_auth":                          "os logs recentes só ficam disponíveis com a autenticação básica ativada",
+		"message_not_in_chat":                        "a mensagem %s pertence ao chat %s, não a %s",
+		"messages_marked_read":                       "%d mensagens marcadas como lidas",
+		"poll_not_found":                             "a enquete %s é desconhecida, só é possível votar em enquetes enviadas ou recebidas desde que o servidor iniciou",
+		"poll_wrong_chat":                            "a enquete %s pertence ao chat %s",
+		"poll_option_index_out_of_range":             "o índice de opção %d está fora do intervalo, a enquete tem %d opções",
+		"poll_option_unknown":                        "a opção %q não faz parte da enquete",
+		"poll_options_required":                      "ao menos uma opção deve ser selecionada",
+		"poll_too_many_options":                      "%d opções selecionadas, mas a enquete permite no máximo %d",
+		"poll_vote_failed":                           "Falha ao votar na enquete: %v",
+		"poll_voted":                                 "Voto enviado",
+		"recipient_not_allowed":                      "O destinatário %s não é permitido pela política de destinatários",
+		"message_media_not_found":                    "A mensagem %s em %s não tem mídia armazenada",
+		"download_media_failed":                      "Falha ao baixar a mídia: %v",
+		"request_location_default":                   "Por favor, compartilhe sua localização",
+		"request_location_sent":                      "Solicitação de localização enviada",
+		"request_phone_sent":                         "Solicitação de número de telefone enviada",
+		"task_not_found":                             "Tarefa %s não encontrada",
+		"task_not_cancellable":                       "A tarefa %s não pode ser cancelada",
+		"task_cancelled":                             "Tarefa %s cancelada",
+		"reply_chat_requires_message_id":             "reply_chat requer reply_message_id",
+		"invalid_reply_chat":                         "Chat da resposta inválido: %v",
+		"reply_message_not_found":                    "Mensagem %s não encontrada no histórico de %s",
+		"client_reconnecting":                        "O cliente WhatsApp está reconectando, tente novamente em instantes",
+		"phone_and_product_required":                 "Phone e product_id são obrigatórios",
+		"product_not_found":                          "Produto %s não encontrado no catálogo",
+		"catalog_query_failed":                       "Falha ao consultar o catálogo: %v",
+		"product_sent":                               "Produto enviado",
+		"invalid_max_size":                           "max_size deve ser um número de bytes não negativo, recebido %q",
+		"prewarm_phones_required":                    "phones é obrigatório, com no máximo %d números",
+		"live_location_duration_invalid":             "duration_seconds deve estar entre 1 e %d",
+		"live_location_sent":                         "Localização em tempo real enviada",
+		"auto_delete_after_invalid":                  "auto_delete_after deve estar entre 0 e %d segundos",
+		"chat_and_id_required":                       "chat e id são obrigatórios",
+		"no_webhook_for_chat":                        "Nenhum webhook configurado para o chat %s",
+		"resend_webhook_failed":                      "Falha ao reenviar webhook: %v",
+		"flow_required":                              "Phone, flow_id, cta e body são obrigatórios",
+		"flow_sent":                                  "Flow %s enviado",
+		"set_default_disappearing_failed":            "Falha ao definir o temporizador padrão de mensagens temporárias: %v",
+		"read_poll_votes_failed":                     "Falha ao ler os votos da enquete: %v",
+		"poll_results_not_found":                     "Nenhuma enquete %s ou voto nela é conhecido neste chat",
+		"webhook_secret_requires_auth":               "o segredo do webhook só pode ser trocado com a autenticação básica ativada",
+		"rotate_webhook_secret_failed":               "falha ao trocar o segredo do webhook: %v",
+		"contact_phone_required":                     "Phone e contact_phone são obrigatórios",
+		"invalid_contact_phone":                      "contact_phone inválido: %v",
+		"contact_sent":                               "Contato %s enviado",
+		"already_paired":                             "já pareado, faça logout para parear novamente",
+		"unsupported_qr_format":                      "format deve ser png, ascii ou text",
+		"qr_failed":                                  "falha ao obter o QR code de pareamento: %v",
+		"call_cache_key_not_found":                   "nenhuma entrada de deduplicação de chamada para a chave %s",
+		"duplicate_message_id":                       "message_id %s já foi usado, escolha outro",
+		"mention_all_failed":                         "Falha ao mencionar os membros do grupo: %v",
+		"poll_results_disabled":                      "Os resultados de enquetes precisam do armazenamento de chats, que está desativado neste servidor",
+		"ephemeral_seconds_invalid":                  "ephemeral_seconds deve ser 86400, 604800 ou 7776000",
+		"ephemeral_timer_conflict":                   "ephemeral_seconds %d conflita com o temporizador de mensagens temporárias do chat de %d segundos",
+		"view_once_audio_invalid":                    "áudio view_once deve ser uma mensagem de voz OGG/Opus, recebido %s",
+		"view_once_unsupported":                      "view_once não é suportado para mensagens do tipo %s",
+		"message_id_length_invalid":                  "message_id deve ter de %d a %d caracteres",
+		"message_id_charset_invalid":                 "message_id deve conter apenas caracteres hexadecimais maiúsculos, recebido %q",
+		"default_disappearing_invalid":               "seconds deve ser 0, 86400, 604800 ou 7776000",
+		"cta_button_count_invalid":                   "são necessários entre 1 e %d botões, recebidos %d",
+		"cta_button_text_invalid":                    "botão %d: text deve ter de 1 a %d caracteres",
+		"cta_button_url_invalid":                     "botão %d: url deve ser uma URL http ou https",
+		"cta_button_phone_invalid":                   "botão %d: phone deve ser um número no formato internacional",
+		"cta_button_id_required":                     "botão %d: id é obrigatório para botões de resposta",
+		"cta_button_type_invalid":                    "botão %d: type deve ser url, call ou reply",
+		"webhook_secret_too_short":                   "secret deve ter pelo menos %d caracteres",
+		"webhook_secret_grace_invalid":               "grace_seconds deve estar entre 0 e %d",
+		"login_success":                              "Login realizado com sucesso",
+		"login_with_code_success":                    "Login com código realizado com sucesso",
+		"logout_success":                             "Logout realizado com sucesso",
+		"reconnect_success":                          "Reconexão realizada com sucesso",
+		"devices_fetched":                            "Dispositivos obtidos com sucesso",
+		"linked_devices_fetched":                     "Dispositivos vinculados obtidos com sucesso",
+		"device_logged_out":                          "Dispositivo desconectado com sucesso",
+		"info_fetched":                               "Informações obtidas com sucesso",
+		"community_groups_fetched":                   "Grupos da comunidade obtidos com sucesso",
+		"community_group_linked":                     "Grupo vinculado à comunidade com sucesso",
+		"community_group_unlinked":                   "Grupo desvinculado da comunidade com sucesso",
+		"group_joined":                               "Entrou no grupo com sucesso",
+		"group_link_info_fetched":                    "Informações do grupo obtidas pelo link com sucesso",
+		"group_left":                                 "Saiu do grupo com sucesso",
+		"group_refreshed":                            "Grupo atualizado com sucesso",
+		"group_id_required":                          "O ID do grupo não pode ficar vazio",
+		"group_participant_requests_fetched":         "Lista de pedidos de participação obtida com sucesso",
+		"message_deleted_for_me":                     "Mensagem apagada com sucesso",
+		"message_starred":                            "Mensagem marcada com estrela com sucesso",
+		"message_unstarred":                          "Estrela removida da mensagem com sucesso",
+		"newsletter_unfollowed":                      "Deixou de seguir o canal com sucesso",
+		"newsletter_messages_fetched":                "Mensagens do canal obtidas com sucesso",
+		"newsletter_reacted":                         "Reação à mensagem do canal enviada com sucesso",
+		"user_info_fetched":                          "Informações do usuário obtidas com sucesso",
+		"avatar_fetched":                             "Avatar obtido com sucesso",
+		"avatar_changed":                             "Avatar alterado com sucesso",
+		"privacy_fetched":                            "Configurações de privacidade obtidas com sucesso",
+		"groups_listed":                              "Lista de grupos obtida com sucesso",
+		"newsletters_listed":                         "Lista de canais obtida com sucesso",
+		"contacts_listed":                            "Lista de contatos obtida com sucesso",
+		"push_name_changed":                          "Nome de exibição alterado com sucesso",
+		"jid_resolved":                               "JID resolvido com sucesso",
+		"user_blocked":                               "Usuário bloqueado com sucesso",
+		"user_unblocked":                             "Usuário desbloqueado com sucesso",
+		"blocklist_fetched":                          "Lista de bloqueados obtida com sucesso",
+		"business_profile_fetched":                   "Perfil comercial obtido com sucesso",
+		"catalog_fetched":                            "Catálogo obtido com sucesso",
+		"contacts_exported":                          "Contatos exportados com sucesso",
+		"user_presence_fetched":                      "Presença do usuário obtida com sucesso",
+		"device_not_linked":                          "o dispositivo %d não está vinculado a esta conta",
+		"primary_device_logout_denied":               "o telefone principal não pode ser desconectado a partir de um dispositivo vinculado",
+		"device_logout_primary_only":                 "o dispositivo %d só pode ser desconectado pelo telefone principal, o WhatsApp não permite que um dispositivo vinculado remova outros dispositivos vinculados",
+		"device_id_invalid":                          "device_id %s não é um número de dispositivo nem um dispositivo desta conta",
+		"group_id_not_group":                         "group_id: deve ser o JID de um grupo.",
+		"participant_action_failed":                  "Ação %s falhou (código %d)",
+		"participant_action_success":                 "Ação %s realizada com sucesso",
+		"media_too_large":                            "o tamanho da mídia excede o limite máximo de %d bytes",
+		"upload_media_failed":                        "falha ao enviar a mídia: %v",
+		"mark_read_success":                          "Mensagem %s marcada como lida",
+		"reaction_sent_to":                           "Reação enviada para %s (horário do servidor: %s)",
+		"revoke_success":                             "Mensagem revogada em %s (horário do servidor: %s)",
+		"update_message_success":                     "Mensagem editada em %s (horário do servidor: %s)",
+		"message_sent_to":                            "Mensagem enviada para %s (horário do servidor: %s)",
+		"download_image_failed":                      "falha ao baixar a imagem da URL %v",
+		"save_downloaded_image_failed":               "falha ao salvar a imagem baixada %v",
+		"open_image_failed":                          "falha ao abrir a imagem %v",
+		"save_thumbnail_failed":                      "falha ao salvar a miniatura %v",
+		"save_image_failed":                          "falha ao salvar a imagem %v",
+		"read_thumbnail_failed":                      "falha ao ler a miniatura %v",
+		"document_sent_to":                           "Documento enviado para %s (horário do servidor: %s)",
+		"store_video_failed":                         "falha ao armazenar o vídeo no servidor %v",
+		"ffmpeg_not_installed":                       "ffmpeg não está instalado",
+		"create_thumbnail_failed":                    "falha ao criar a miniatura %v",
+		"compress_video_failed":                      "falha ao comprimir o vídeo",
+		"upload_file_failed":                         "Falha ao enviar o arquivo: %v",
+		"video_sent_to":                              "Vídeo enviado para %s (horário do servidor: %s)",
+		"contact_sent_to":                            "Contato enviado para %s (horário do servidor: %s)",
+		"link_sent_to":                               "Link enviado para %s (horário do servidor: %s)",
+		"location_sent_to":                           "Localização enviada para %s (horário do servidor: %s)",
+		"upload_audio_failed":                        "Falha ao enviar o áudio: %v",
+		"audio_sent_to":                              "Áudio enviado para %s (horário do servidor: %s)",
+		"poll_sent_to":                               "Enquete enviada para %s (horário do servidor: %s)",
+		"presence_type_sent":                         "Presença %s enviada",
+		"avatar_timeout":                             "Tempo esgotado ao obter o avatar",
+		"read_lid_mapping_failed":                    "falha ao ler o mapeamento de LID: %v",
+		"presence_not_received":                      "nenhuma presença recebida de %s ainda, as atualizações chegam depois que a presença do contato é assinada",
+		"contact_export_disabled":                    "a exportação de contatos está desativada neste servidor",
+		"image_or_url_required":                      "é preciso informar Image ou ImageURL",
+		"image_type_not_allowed":                     "sua imagem não é permitida. use jpg/jpeg/png",
+		"image_url_required":                         "ImageURL não pode ficar vazio",
+		"image_url_invalid":                          "ImageURL deve ser uma URL válida",
+		"file_upload_too_large":                      "o envio máximo de arquivo é %s, envie para a nuvem e mande o link por texto se o arquivo for maior que %s",
+		"video_type_not_allowed":                     "seu tipo de vídeo não é permitido. use mp4/mkv/avi",
+		"video_upload_too_large":                     "o envio máximo de vídeo é %s, envie para a nuvem e mande o link por texto se o arquivo for maior que %s",
+		"audio_type_not_allowed":                     "seu tipo de áudio não é permitido. use (%s)",
+		"poll_options_not_unique":                    "as opções devem ser únicas",
+		"required_with_reply_chat":                   "é obrigatório com reply_chat",
+		"validation_required":                        "não pode ficar em branco",
+		"validation_min_greater_equal_than_required": "deve ser no mínimo {{.threshold}}",
+		"validation_max_less_equal_than_required":    "deve ser no máximo {{.threshold}}",
+		"validation_in_invalid":                      "deve ser um valor válido",
+		"validation_match_invalid":                   "deve estar em um formato válido",
+		"validation_length_too_long":                 "o tamanho deve ser de no máximo {{.max}}",
+		"validation_length_too_short":                "o tamanho deve ser de no mínimo {{.min}}",
+		"validation_length_invalid":                  "o tamanho deve ser exatamente {{.min}}",
+		"validation_length_out_of_range":             "o tamanho deve estar entre {{.min}} e {{.max}}",
+		"validation_is_url":                          "deve ser uma URL válida",
+		"validation_is_latitude":                     "deve ser uma latitude válida",
+		"validation_is_longitude":                    "deve ser uma longitude válida",
+		"group_created":                              "Grupo criado com sucesso com o id %s",
+		"participants_added":                         "Participantes adicionados com sucesso",
+		"participants_removed":                       "Participantes removidos com sucesso",
+		"participants_promoted":                      "Participantes promovidos com sucesso",
+		"participants_demoted":                       "Participantes rebaixados com sucesso",
+		"participant_requests_approved":              "Pedidos de participação aprovados com sucesso",
+		"participant_requests_rejected":              "Pedidos de participação rejeitados com sucesso",
+		"group_broadcast_processed":                  "Transmissão processada para %d grupos",
+		"broadcast_processed":                        "Transmissão processada para %d destinatários",
+		"avatar_not_found":                           "nenhum avatar encontrado",
+		"invite_link_revoked":                        "o link de convite do grupo foi revogado",
+		"invite_link_invalid":                        "o link de convite do grupo é inválido ou expirou",
+		"not_group_member":                           "não é membro deste grupo",
+		"group_admins_only":                          "só administradores podem enviar mensagens neste grupo",
+		"duplicate_recipient":                        "destinatário duplicado",
+		"resolve_jid_unsupported":                    "só é possível resolver JIDs de usuário ou LID",
+		"participant_add_failed":                     "Falha ao adicionar o participante",
+		"action_success":                             "Ação realizada com sucesso",
+	},
+}
+
+// T returns the catalog message for key in config.AppLanguage, falling back to
+// English and finally to the key itself so a missing entry is easy to spot.
+func T(key string, args ...any) string {
+	format, ok := messageCatalog[config.AppLanguage][key]
+	if !ok {
+		format, ok = messageCatalog[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// SupportedLanguages lists the languages of the message catalog.
+func SupportedLanguages() []string {
+	languages := make([]string, 0, len(messageCatalog))
+	for language := range messageCatalog {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// TranslationKeys lists the message keys translated for a language.
+func TranslationKeys(language string) []string {
+	keys := make([]string, 0, len(messageCatalog[language]))
+	for key := range messageCatalog[language] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RecentLogCapacity is how many warning and error entries RecentLogs keeps.
+const RecentLogCapacity = 200
+
+// LogEntry is a captured log line.
+type LogEntry struct {
+	Time    time.Time      `json:"time"`
+	Level   string         `json:"level"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// LogBuffer is a logrus hook keeping the latest warning and error entries in a
+// fixed size ring buffer, so they can be read without shell access.
+type LogBuffer struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int
+	full    bool
+}
+
+// RecentLogs is the buffer installed on the standard logger at startup.
+var RecentLogs = NewLogBuffer(RecentLogCapacity)
+
+// NewLogBuffer creates a buffer holding at most capacity entries.
+func NewLogBuffer(capacity int) *LogBuffer {
+	return &LogBuffer{entries: make([]LogEntry, capacity)}
+}
+
+// Levels implements logrus.Hook.
+func (b *LogBuffer) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+}
+
+// Fire implements logrus.Hook.
+func (b *LogBuffer) Fire(entry *logrus.Entry) error {
+	logEntry := LogEntry{Time: entry.Time, Level: entry.Level.String(), Message: entry.Message}
+	if len(entry.Data) > 0 {
+		logEntry.Fields = make(map[string]any, len(entry.Data))
+		for key, value := range entry.Data {
+			if err, ok := value.(error); ok {
+				value = err.Error()
+			}
+			logEntry.Fields[key] = value
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.entries) == 0 {
+		return nil
+	}
+	b.entries[b.next] = logEntry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	return nil
+}
+
+// Recent returns up to limit captured entries, newest first. A limit of 0 or
+// less returns every entry.
+func (b *LogBuffer) Recent(limit int) []LogEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	count := b.next
+	if b.full {
+		count = len(b.entries)
+	}
+	if limit <= 0 || limit > count {
+		limit = count
+	}
+
+	result := make([]LogEntry, 0, limit)
+	for i := 1; i <= limit; i++ {
+		index := (b.next - i + len(b.entries)) % len(b.entries)
+		result = append(result, b.entries[index])
+	}
+	return result
+}
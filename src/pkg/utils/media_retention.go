@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OutboundMediaPrefix marks the temporary copies of sent media written to the
+// media directory; every other file there is downloaded inbound media.
+const OutboundMediaPrefix = "temp_"
+
+// MediaRetention limits how long and how many files of one kind are kept. A zero
+// value disables the limit.
+type MediaRetention struct {
+	MaxAge   time.Duration
+	MaxCount int
+}
+
+func (r MediaRetention) enabled() bool {
+	return r.MaxAge > 0 || r.MaxCount > 0
+}
+
+type mediaFile struct {
+	path    string
+	modTime time.Time
+}
+
+// PruneMedia removes the files of dir exceeding the retention of their kind,
+// oldest first, and reports how many were removed. Hidden files and
+// subdirectories are left alone.
+func PruneMedia(dir string, inbound, outbound MediaRetention, now time.Time) (int, error) {
+	if !inbound.enabled() && !outbound.enabled() {
+		return 0, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list media directory: %w", err)
+	}
+
+	var inboundFiles, outboundFiles []mediaFile
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		file := mediaFile{path: filepath.Join(dir, entry.Name()), modTime: info.ModTime()}
+		if strings.HasPrefix(entry.Name(), OutboundMediaPrefix) {
+			outboundFiles = append(outboundFiles, file)
+		} else {
+			inboundFiles = append(inboundFiles, file)
+		}
+	}
+
+	removed := 0
+	for _, group := range []struct {
+		files     []mediaFile
+		retention MediaRetention
+	}{{inboundFiles, inbound}, {outboundFiles, outbound}} {
+		if !group.retention.enabled() {
+			continue
+		}
+		sort.Slice(group.files, func(i, j int) bool {
+			return group.files[i].modTime.After(group.files[j].modTime)
+		})
+		for i, file := range group.files {
+			tooOld := group.retention.MaxAge > 0 && now.Sub(file.modTime) > group.retention.MaxAge
+			tooMany := group.retention.MaxCount > 0 && i >= group.retention.MaxCount
+			if !tooOld && !tooMany {
+				continue
+			}
+			if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+				return removed, fmt.Errorf("failed to remove %s: %w", file.path, err)
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Scheduler runs delayed callbacks keyed by name. Scheduling a key that is
+// already pending replaces its timer, so repeated requests for the same key
+// never pile up, and pending callbacks can be cancelled all at once.
+type Scheduler struct {
+	mu      sync.Mutex
+	timers  map[string]*scheduledCallback
+	stopped bool
+}
+
+type scheduledCallback struct {
+	timer       *time.Timer
+	scheduledAt time.Time
+	runAt       time.Time
+}
+
+// DefaultScheduler is shared by the REST handlers and the WhatsApp client.
+var DefaultScheduler = NewScheduler()
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{timers: map[string]*scheduledCallback{}}
+}
+
+// Schedule runs fn after delay unless key is scheduled again or cancelled first.
+func (s *Scheduler) Schedule(key string, delay time.Duration, fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	if previous, ok := s.timers[key]; ok {
+		previous.timer.Stop()
+	}
+
+	now := time.Now()
+	callback := &scheduledCallback{scheduledAt: now, runAt: now.Add(delay)}
+	callback.timer = time.AfterFunc(delay, func() {
+		s.mu.Lock()
+		current := s.timers[key] == callback
+		if current {
+			delete(s.timers, key)
+		}
+		s.mu.Unlock()
+		// A replaced timer may fire before Stop takes effect; only the latest runs.
+		if current {
+			fn()
+		}
+	})
+	s.timers[key] = callback
+}
+
+// Cancel drops the pending callback of key, reporting whether there was one.
+func (s *Scheduler) Cancel(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	callback, ok := s.timers[key]
+	if ok {
+		callback.timer.Stop()
+		delete(s.timers, key)
+	}
+	return ok
+}
+
+// Pending returns how many callbacks are waiting to run.
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.timers)
+}
+
+// Tasks lists the pending callbacks as cancellable tasks of type "timer", their
+// ID being the scheduling key.
+func (s *Scheduler) Tasks() []Task {
+	s.mu.Lock()
+	tasks := make([]Task, 0, len(s.timers))
+	for key, callback := range s.timers {
+		tasks = append(tasks, Task{
+			ID:          key,
+			Type:        "timer",
+			StartedAt:   callback.scheduledAt,
+			RunAt:       callback.runAt,
+			Cancellable: true,
+		})
+	}
+	s.mu.Unlock()
+	sortTasks(tasks)
+	return tasks
+}
+
+// Stop cancels every pending callback and ignores later calls to Schedule.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	for key, callback := range s.timers {
+		callback.timer.Stop()
+		delete(s.timers, key)
+	}
+}
@@ -0,0 +1,56 @@
+package utils_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+}
+
+func (suite *SchedulerTestSuite) TestScheduleReplacesPendingTimer() {
+	scheduler := NewScheduler()
+	var runs, last atomic.Int32
+	for i := 1; i <= 50; i++ {
+		value := int32(i)
+		scheduler.Schedule("chat", 20*time.Millisecond, func() {
+			runs.Add(1)
+			last.Store(value)
+		})
+	}
+	assert.Equal(suite.T(), 1, scheduler.Pending())
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(suite.T(), int32(1), runs.Load())
+	assert.Equal(suite.T(), int32(50), last.Load())
+	assert.Equal(suite.T(), 0, scheduler.Pending())
+}
+
+func (suite *SchedulerTestSuite) TestCancelAndStop() {
+	scheduler := NewScheduler()
+	var runs atomic.Int32
+	scheduler.Schedule("a", 20*time.Millisecond, func() { runs.Add(1) })
+	scheduler.Schedule("b", 20*time.Millisecond, func() { runs.Add(1) })
+
+	// Test case: Cancel one key
+	assert.True(suite.T(), scheduler.Cancel("a"))
+	assert.False(suite.T(), scheduler.Cancel("a"))
+
+	// Test case: Stop cancels the rest and rejects new timers
+	scheduler.Stop()
+	scheduler.Schedule("c", time.Millisecond, func() { runs.Add(1) })
+	assert.Equal(suite.T(), 0, scheduler.Pending())
+
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(suite.T(), int32(0), runs.Load())
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrTaskNotFound is returned when cancelling a task that is not running.
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrTaskNotCancellable is returned when cancelling a task that cannot stop early.
+	ErrTaskNotCancellable = errors.New("task cannot be cancelled")
+)
+
+// Task describes a running background goroutine or a pending scheduled callback.
+type Task struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Description string    `json:"description,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	RunAt       time.Time `json:"run_at,omitempty"` // scheduled callbacks only
+	Cancellable bool      `json:"cancellable"`
+}
+
+// TaskRegistry keeps track of the background goroutines started through it, so
+// stuck or leaking ones can be spotted and, when they support it, cancelled.
+type TaskRegistry struct {
+	mu     sync.Mutex
+	tasks  map[string]*runningTask
+	nextID int
+}
+
+type runningTask struct {
+	task   Task
+	cancel context.CancelFunc
+}
+
+// DefaultTasks is shared by the REST server and the WhatsApp client.
+var DefaultTasks = NewTaskRegistry()
+
+func NewTaskRegistry() *TaskRegistry {
+	return &TaskRegistry{tasks: map[string]*runningTask{}}
+}
+
+// Go runs fn in a goroutine registered under taskType. Cancelling the task
+// cancels the context given to fn, which should return soon after.
+func (r *TaskRegistry) Go(taskType, description string, fn func(ctx context.Context)) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	id := r.add(taskType, description, cancel)
+	go func() {
+		defer r.remove(id)
+		defer cancel()
+		fn(ctx)
+	}()
+	return id
+}
+
+// Track registers a goroutine that cannot be cancelled. The caller must call
+// the returned function when the goroutine ends.
+func (r *TaskRegistry) Track(taskType, description string) func() {
+	id := r.add(taskType, description, nil)
+	return func() { r.remove(id) }
+}
+
+func (r *TaskRegistry) add(taskType, description string, cancel context.CancelFunc) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	id := taskType + "-" + strconv.Itoa(r.nextID)
+	r.tasks[id] = &runningTask{
+		task: Task{
+			ID:          id,
+			Type:        taskType,
+			Description: description,
+			StartedAt:   time.Now(),
+			Cancellable: cancel != nil,
+		},
+		cancel: cancel,
+	}
+	return id
+}
+
+func (r *TaskRegistry) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tasks, id)
+}
+
+// List returns the running tasks, oldest first.
+func (r *TaskRegistry) List() []Task {
+	r.mu.Lock()
+	tasks := make([]Task, 0, len(r.tasks))
+	for _, running := range r.tasks {
+		tasks = append(tasks, running.task)
+	}
+	r.mu.Unlock()
+	sortTasks(tasks)
+	return tasks
+}
+
+// Cancel stops a running task. The task leaves the list once its goroutine returns.
+func (r *TaskRegistry) Cancel(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	running, ok := r.tasks[id]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	if running.cancel == nil {
+		return ErrTaskNotCancellable
+	}
+	running.cancel()
+	return nil
+}
+
+// CancelAll stops every cancellable task, e.g. on shutdown.
+func (r *TaskRegistry) CancelAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, running := range r.tasks {
+		if running.cancel != nil {
+			running.cancel()
+		}
+	}
+}
+
+// sortTasks orders tasks by start time, then by ID for a stable listing.
+func sortTasks(tasks []Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].StartedAt.Equal(tasks[j].StartedAt) {
+			return tasks[i].StartedAt.Before(tasks[j].StartedAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+}
@@ -0,0 +1,72 @@
+package utils_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TaskRegistryTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TaskRegistryTestSuite) TestGoAndCancel() {
+	registry := NewTaskRegistry()
+	stopped := make(chan struct{})
+	id := registry.Go("janitor", "prunes media", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+	done := registry.Track("webhook", "")
+
+	tasks := registry.List()
+	assert.Len(suite.T(), tasks, 2)
+	assert.Equal(suite.T(), id, tasks[0].ID)
+	assert.True(suite.T(), tasks[0].Cancellable)
+	assert.False(suite.T(), tasks[1].Cancellable)
+
+	assert.ErrorIs(suite.T(), registry.Cancel(tasks[1].ID), ErrTaskNotCancellable)
+	assert.ErrorIs(suite.T(), registry.Cancel("missing"), ErrTaskNotFound)
+	assert.NoError(suite.T(), registry.Cancel(id))
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		suite.T().Fatal("cancelled task did not stop")
+	}
+	done()
+	assert.Eventually(suite.T(), func() bool { return len(registry.List()) == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func (suite *TaskRegistryTestSuite) TestSchedulerTasks() {
+	scheduler := NewScheduler()
+	defer scheduler.Stop()
+	scheduler.Schedule("request-response:ABC", time.Minute, func() {})
+
+	tasks := scheduler.Tasks()
+	assert.Len(suite.T(), tasks, 1)
+	assert.Equal(suite.T(), "request-response:ABC", tasks[0].ID)
+	assert.Equal(suite.T(), "timer", tasks[0].Type)
+	assert.WithinDuration(suite.T(), time.Now().Add(time.Minute), tasks[0].RunAt, time.Second)
+}
+
+func (suite *TaskRegistryTestSuite) TestCancelAll() {
+	registry := NewTaskRegistry()
+	for i := 0; i < 3; i++ {
+		registry.Go("live_location", "", func(ctx context.Context) { <-ctx.Done() })
+	}
+	done := registry.Track("webhook", "")
+	defer done()
+
+	registry.CancelAll()
+	assert.Eventually(suite.T(), func() bool { return len(registry.List()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(suite.T(), "webhook", registry.List()[0].Type)
+}
+
+func TestTaskRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskRegistryTestSuite))
+}
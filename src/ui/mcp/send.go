@@ -45,6 +45,9 @@ func (s *SendHandler) toolSendText() mcp.Tool {
 		mcp.WithString("reply_message_id",
 			mcp.Description("Message ID to reply to (optional)"),
 		),
+		mcp.WithBoolean("mention_all",
+			mcp.Description("Mention every group member, requires being a group admin (default: false)"),
+		),
 	)
 
 	return sendTextTool
@@ -71,11 +74,17 @@ func (s *SendHandler) handleSendText(ctx context.Context, request mcp.CallToolRe
 		replyMessageId = ""
 	}
 
+	mentionAll, ok := request.GetArguments()["mention_all"].(bool)
+	if !ok {
+		mentionAll = false
+	}
+
 	res, err := s.sendService.SendText(ctx, domainSend.MessageRequest{
 		Phone:          phone,
 		Message:        message,
 		IsForwarded:    isForwarded,
 		ReplyMessageID: &replyMessageId,
+		MentionAll:     mentionAll,
 	})
 
 	if err != nil {
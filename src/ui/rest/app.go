@@ -19,6 +19,9 @@ func InitRestApp(app *fiber.App, service domainApp.IAppUsecase) App {
 	app.Get("/app/logout", rest.Logout)
 	app.Get("/app/reconnect", rest.Reconnect)
 	app.Get("/app/devices", rest.Devices)
+	app.Get("/devices", rest.LinkedDevices)
+	app.Post("/devices/logout", rest.LogoutDevice)
+	app.Get("/info", rest.Info)
 
 	return App{Service: service}
 }
@@ -30,7 +33,7 @@ func (handler *App) Login(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Login success",
+		Message: utils.T("login_success"),
 		Results: map[string]any{
 			"qr_link":     fmt.Sprintf("%s://%s/%s", c.Protocol(), c.Hostname(), response.ImagePath),
 			"qr_duration": response.Duration,
@@ -45,7 +48,7 @@ func (handler *App) LoginWithCode(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Login with code success",
+		Message: utils.T("login_with_code_success"),
 		Results: map[string]any{
 			"pair_code": pairCode,
 		},
@@ -59,7 +62,7 @@ func (handler *App) Logout(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success logout",
+		Message: utils.T("logout_success"),
 		Results: nil,
 	})
 }
@@ -71,7 +74,7 @@ func (handler *App) Reconnect(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Reconnect success",
+		Message: utils.T("reconnect_success"),
 		Results: nil,
 	})
 }
@@ -83,7 +86,47 @@ func (handler *App) Devices(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Fetch device success",
+		Message: utils.T("devices_fetched"),
 		Results: devices,
 	})
 }
+
+func (handler *App) LinkedDevices(c *fiber.Ctx) error {
+	devices, err := handler.Service.LinkedDevices(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("linked_devices_fetched"),
+		Results: devices,
+	})
+}
+
+func (handler *App) LogoutDevice(c *fiber.Ctx) error {
+	var request domainApp.LogoutDeviceRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	devices, err := handler.Service.LogoutDevice(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("device_logged_out"),
+		Results: devices,
+	})
+}
+
+func (handler *App) Info(c *fiber.Ctx) error {
+	info, err := handler.Service.Info(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("info_fetched"),
+		Results: info,
+	})
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	domainCommunity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/community"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Community struct {
+	Service domainCommunity.ICommunityUsecase
+}
+
+func InitRestCommunity(app *fiber.App, service domainCommunity.ICommunityUsecase) Community {
+	rest := Community{Service: service}
+	app.Get("/community/:jid/groups", rest.ListGroups)
+	app.Post("/community/link", rest.LinkGroup)
+	app.Post("/community/unlink", rest.UnlinkGroup)
+	return rest
+}
+
+func (controller *Community) ListGroups(c *fiber.Ctx) error {
+	var request domainCommunity.ListGroupsRequest
+	err := c.ParamsParser(&request)
+	utils.PanicIfNeeded(err)
+
+	whatsapp.SanitizePhone(&request.CommunityID)
+
+	result, err := controller.Service.ListGroups(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("community_groups_fetched"),
+		Results: result,
+	})
+}
+
+func (controller *Community) LinkGroup(c *fiber.Ctx) error {
+	var request domainCommunity.LinkGroupRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	whatsapp.SanitizePhone(&request.CommunityID)
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.LinkGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("community_group_linked"),
+	})
+}
+
+func (controller *Community) UnlinkGroup(c *fiber.Ctx) error {
+	var request domainCommunity.LinkGroupRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	whatsapp.SanitizePhone(&request.CommunityID)
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.UnlinkGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("community_group_unlinked"),
+	})
+}
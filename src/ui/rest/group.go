@@ -1,8 +1,6 @@
 package rest
 
 import (
-	"fmt"
-
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -18,6 +16,7 @@ func InitRestGroup(app *fiber.App, service domainGroup.IGroupUsecase) Group {
 	rest := Group{Service: service}
 	app.Post("/group", rest.CreateGroup)
 	app.Post("/group/join-with-link", rest.JoinGroupWithLink)
+	app.Get("/group/invite-info", rest.GetGroupInfoFromLink)
 	app.Post("/group/leave", rest.LeaveGroup)
 	app.Post("/group/participants", rest.AddParticipants)
 	app.Post("/group/participants/remove", rest.DeleteParticipants)
@@ -26,6 +25,8 @@ func InitRestGroup(app *fiber.App, service domainGroup.IGroupUsecase) Group {
 	app.Get("/group/participant-requests", rest.ListParticipantRequests)
 	app.Post("/group/participant-requests/approve", rest.ApproveParticipantRequests)
 	app.Post("/group/participant-requests/reject", rest.RejectParticipantRequests)
+	app.Post("/group/broadcast", rest.Broadcast)
+	app.Post("/group/:jid/refresh", rest.RefreshGroup)
 	return rest
 }
 
@@ -40,13 +41,29 @@ func (controller *Group) JoinGroupWithLink(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success joined group",
+		Message: utils.T("group_joined"),
 		Results: map[string]string{
 			"group_id": response,
 		},
 	})
 }
 
+func (controller *Group) GetGroupInfoFromLink(c *fiber.Ctx) error {
+	var request domainGroup.GetGroupInfoFromLinkRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.GetGroupInfoFromLink(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("group_link_info_fetched"),
+		Results: response,
+	})
+}
+
 func (controller *Group) LeaveGroup(c *fiber.Ctx) error {
 	var request domainGroup.LeaveGroupRequest
 	err := c.BodyParser(&request)
@@ -60,7 +77,23 @@ func (controller *Group) LeaveGroup(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success leave group",
+		Message: utils.T("group_left"),
+	})
+}
+
+func (controller *Group) RefreshGroup(c *fiber.Ctx) error {
+	var request domainGroup.RefreshGroupRequest
+	err := c.ParamsParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.RefreshGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("group_refreshed"),
+		Results: response,
 	})
 }
 
@@ -75,26 +108,26 @@ func (controller *Group) CreateGroup(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: fmt.Sprintf("Success created group with id %s", groupID),
+		Message: utils.T("group_created", groupID),
 		Results: map[string]string{
 			"group_id": groupID,
 		},
 	})
 }
 func (controller *Group) AddParticipants(c *fiber.Ctx) error {
-	return controller.manageParticipants(c, whatsmeow.ParticipantChangeAdd, "Success add participants")
+	return controller.manageParticipants(c, whatsmeow.ParticipantChangeAdd, utils.T("participants_added"))
 }
 
 func (controller *Group) DeleteParticipants(c *fiber.Ctx) error {
-	return controller.manageParticipants(c, whatsmeow.ParticipantChangeRemove, "Success delete participants")
+	return controller.manageParticipants(c, whatsmeow.ParticipantChangeRemove, utils.T("participants_removed"))
 }
 
 func (controller *Group) PromoteParticipants(c *fiber.Ctx) error {
-	return controller.manageParticipants(c, whatsmeow.ParticipantChangePromote, "Success promote participants")
+	return controller.manageParticipants(c, whatsmeow.ParticipantChangePromote, utils.T("participants_promoted"))
 }
 
 func (controller *Group) DemoteParticipants(c *fiber.Ctx) error {
-	return controller.manageParticipants(c, whatsmeow.ParticipantChangeDemote, "Success demote participants")
+	return controller.manageParticipants(c, whatsmeow.ParticipantChangeDemote, utils.T("participants_demoted"))
 }
 
 func (controller *Group) ListParticipantRequests(c *fiber.Ctx) error {
@@ -106,7 +139,7 @@ func (controller *Group) ListParticipantRequests(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
 			Status:  400,
 			Code:    "INVALID_GROUP_ID",
-			Message: "Group ID cannot be empty",
+			Message: utils.T("group_id_required"),
 		})
 	}
 
@@ -118,17 +151,17 @@ func (controller *Group) ListParticipantRequests(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success getting list requested participants",
+		Message: utils.T("group_participant_requests_fetched"),
 		Results: result,
 	})
 }
 
 func (controller *Group) ApproveParticipantRequests(c *fiber.Ctx) error {
-	return controller.handleRequestedParticipants(c, whatsmeow.ParticipantChangeApprove, "Success approve requested participants")
+	return controller.handleRequestedParticipants(c, whatsmeow.ParticipantChangeApprove, utils.T("participant_requests_approved"))
 }
 
 func (controller *Group) RejectParticipantRequests(c *fiber.Ctx) error {
-	return controller.handleRequestedParticipants(c, whatsmeow.ParticipantChangeReject, "Success reject requested participants")
+	return controller.handleRequestedParticipants(c, whatsmeow.ParticipantChangeReject, utils.T("participant_requests_rejected"))
 }
 
 // Generalized participant management handler
@@ -164,3 +197,26 @@ func (controller *Group) handleRequestedParticipants(c *fiber.Ctx, action whatsm
 		Results: result,
 	})
 }
+
+func (controller *Group) Broadcast(c *fiber.Ctx) error {
+	var request domainGroup.BroadcastRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if media, err := c.FormFile("media"); err == nil {
+		request.Media = media
+	}
+	if request.Filter == "" {
+		request.Filter = domainGroup.BroadcastFilterAll
+	}
+
+	result, err := controller.Service.Broadcast(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("group_broadcast_processed", len(result)),
+		Results: result,
+	})
+}
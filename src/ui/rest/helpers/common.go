@@ -6,6 +6,7 @@ import (
 	"time"
 
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"go.mau.fi/whatsmeow"
 )
 
@@ -16,14 +17,18 @@ func SetAutoConnectAfterBooting(service domainApp.IAppUsecase) {
 
 func SetAutoReconnectChecking(cli *whatsmeow.Client) {
 	// Run every 5 minutes to check if the connection is still alive, if not, reconnect
-	go func() {
+	utils.DefaultTasks.Go("auto-reconnect", "reconnects every 5 minutes when disconnected", func(ctx context.Context) {
 		for {
-			time.Sleep(5 * time.Minute)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Minute):
+			}
 			if !cli.IsConnected() {
 				_ = cli.Connect()
 			}
 		}
-	}()
+	})
 }
 
 func MultipartFormFileHeaderToBytes(fileHeader *multipart.FileHeader) []byte {
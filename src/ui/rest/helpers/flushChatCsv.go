@@ -1,11 +1,13 @@
 package helpers
 
 import (
+	"context"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
@@ -15,12 +17,14 @@ func FlushChatCsv() error {
 	flushMutex.Lock()
 	defer flushMutex.Unlock()
 
-	// Create an empty file (truncating any existing content)
-	file, err := os.OpenFile(config.PathChatStorage, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	if err != nil {
-		return err
+	// Create empty files (truncating any existing content)
+	for _, path := range []string{config.PathChatStorage, config.PathChatReceipts, config.PathChatHistory, config.PathReactions, config.PathMediaKeys} {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		file.Close()
 	}
-	defer file.Close()
 
 	return nil
 }
@@ -29,18 +33,23 @@ func FlushChatCsv() error {
 func StartAutoFlushChatStorage() {
 	interval := time.Duration(config.AppChatFlushIntervalDays) * 24 * time.Hour
 
-	go func() {
+	utils.DefaultTasks.Go("chat-flush", "flushes the chat storage periodically", func(ctx context.Context) {
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
-		for range ticker.C {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
 			if err := FlushChatCsv(); err != nil {
 				logrus.Errorf("Error flushing chat storage: %v", err)
 			} else {
 				logrus.Info("Successfully flushed chat storage")
 			}
 		}
-	}()
+	})
 
 	logrus.Infof("Auto flush for chat storage started (your account chat still safe). Will flush every %d days", config.AppChatFlushIntervalDays)
 }
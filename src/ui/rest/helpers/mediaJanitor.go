@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	"context"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// mediaJanitorInterval is how often the media retention is enforced.
+const mediaJanitorInterval = time.Minute
+
+// StartMediaJanitor starts a goroutine that periodically removes media files
+// exceeding the configured inbound and outbound retention. It does nothing when
+// no retention is configured.
+func StartMediaJanitor() {
+	inbound := utils.MediaRetention{
+		MaxAge:   time.Duration(config.MediaInboundMaxAgeMinutes) * time.Minute,
+		MaxCount: config.MediaInboundMaxCount,
+	}
+	outbound := utils.MediaRetention{
+		MaxAge:   time.Duration(config.MediaOutboundMaxAgeMinutes) * time.Minute,
+		MaxCount: config.MediaOutboundMaxCount,
+	}
+	if inbound == (utils.MediaRetention{}) && outbound == (utils.MediaRetention{}) {
+		return
+	}
+
+	utils.DefaultTasks.Go("media-janitor", "enforces the media retention", func(ctx context.Context) {
+		ticker := time.NewTicker(mediaJanitorInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			removed, err := utils.PruneMedia(config.PathMedia, inbound, outbound, time.Now())
+			if err != nil {
+				logrus.Errorf("Error enforcing media retention: %v", err)
+			} else if removed > 0 {
+				logrus.Infof("Media retention removed %d files", removed)
+			}
+		}
+	})
+
+	logrus.Infof("Media janitor started for %s (inbound %+v, outbound %+v)", config.PathMedia, inbound, outbound)
+}
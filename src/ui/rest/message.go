@@ -56,7 +56,7 @@ func (controller *Message) DeleteMessage(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Message deleted successfully",
+		Message: utils.T("message_deleted_for_me"),
 		Results: nil,
 	})
 }
@@ -133,7 +133,7 @@ func (controller *Message) StarMessage(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Starred message successfully",
+		Message: utils.T("message_starred"),
 		Results: nil,
 	})
 }
@@ -152,7 +152,7 @@ func (controller *Message) UnstarMessage(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Unstarred message successfully",
+		Message: utils.T("message_unstarred"),
 		Results: nil,
 	})
 }
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+const IdempotencyHeader = "Idempotency-Key"
+
+var (
+	idempotencyCache = sync.Map{}
+	idempotencyTTL   = 24 * time.Hour
+	// idempotencyExpiry evicts replayable responses. It is kept apart from
+	// utils.DefaultScheduler so these internal timers are not listed or
+	// cancellable through /tasks.
+	idempotencyExpiry = utils.NewScheduler()
+)
+
+type idempotentResponse struct {
+	done        chan struct{}
+	status      int
+	contentType string
+	body        []byte
+}
+
+// Idempotency replays the original response of a send endpoint when a client
+// retries with the same Idempotency-Key header, instead of sending again.
+// Only successful responses are kept; failed attempts may be retried.
+func Idempotency() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyHeader)
+		if key == "" || c.Method() != fiber.MethodPost || !isSendPath(c.Path()) {
+			return c.Next()
+		}
+
+		cacheKey := c.Path() + ":" + key
+		entry := &idempotentResponse{done: make(chan struct{})}
+		for {
+			existing, loaded := idempotencyCache.LoadOrStore(cacheKey, entry)
+			if !loaded {
+				break
+			}
+			previous := existing.(*idempotentResponse)
+			<-previous.done
+			if previous.status != 0 {
+				c.Set("Idempotent-Replayed", "true")
+				c.Set(fiber.HeaderContentType, previous.contentType)
+				return c.Status(previous.status).Send(previous.body)
+			}
+			// The earlier attempt failed and was evicted, try to claim the key again.
+		}
+
+		succeeded := false
+		defer func() {
+			if !succeeded {
+				idempotencyCache.Delete(cacheKey)
+			}
+			close(entry.done)
+		}()
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status < fiber.StatusOK || status >= fiber.StatusMultipleChoices {
+			return nil
+		}
+
+		entry.status = status
+		entry.contentType = string(c.Response().Header.ContentType())
+		entry.body = append([]byte(nil), c.Response().Body()...)
+		succeeded = true
+
+		idempotencyExpiry.Schedule(cacheKey, idempotencyTTL, func() {
+			idempotencyCache.Delete(cacheKey)
+		})
+		return nil
+	}
+}
+
+func isSendPath(path string) bool {
+	return strings.HasPrefix(path, "/send/") || strings.HasPrefix(path, "/chat/send/")
+}
@@ -13,6 +13,8 @@ type Newsletter struct {
 func InitRestNewsletter(app *fiber.App, service domainNewsletter.INewsletterUsecase) Newsletter {
 	rest := Newsletter{Service: service}
 	app.Post("/newsletter/unfollow", rest.Unfollow)
+	app.Get("/newsletter/:jid/messages", rest.Messages)
+	app.Post("/newsletter/react", rest.React)
 	return rest
 }
 
@@ -27,6 +29,39 @@ func (controller *Newsletter) Unfollow(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success unfollow newsletter",
+		Message: utils.T("newsletter_unfollowed"),
+	})
+}
+
+func (controller *Newsletter) Messages(c *fiber.Ctx) error {
+	var request domainNewsletter.MessagesRequest
+	err := c.ParamsParser(&request)
+	utils.PanicIfNeeded(err)
+	err = c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.Messages(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("newsletter_messages_fetched"),
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) React(c *fiber.Ctx) error {
+	var request domainNewsletter.ReactRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	err = controller.Service.React(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("newsletter_reacted"),
 	})
 }
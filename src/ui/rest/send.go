@@ -23,6 +23,7 @@ func InitRestSend(app *fiber.App, service domainSend.ISendUsecase) Send {
 	app.Post("/send/audio", rest.SendAudio)
 	app.Post("/send/poll", rest.SendPoll)
 	app.Post("/send/presence", rest.SendPresence)
+	app.Post("/broadcast/send", rest.SendBroadcast)
 	return rest
 }
 
@@ -222,3 +223,19 @@ func (controller *Send) SendPresence(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Send) SendBroadcast(c *fiber.Ctx) error {
+	var request domainSend.BroadcastRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	result, err := controller.Service.SendBroadcast(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("broadcast_processed", len(result)),
+		Results: result,
+	})
+}
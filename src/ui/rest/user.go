@@ -21,6 +21,14 @@ func InitRestUser(app *fiber.App, service domainUser.IUserUsecase) User {
 	app.Get("/user/my/groups", rest.UserMyListGroups)
 	app.Get("/user/my/newsletters", rest.UserMyListNewsletter)
 	app.Get("/user/my/contacts", rest.UserMyListContacts)
+	app.Get("/user/:jid/resolve", rest.UserResolve)
+	app.Post("/user/block", rest.UserBlock)
+	app.Post("/user/unblock", rest.UserUnblock)
+	app.Get("/user/blocklist", rest.UserBlocklist)
+	app.Get("/user/:jid/business", rest.UserBusinessProfile)
+	app.Get("/user/:jid/catalog", rest.UserCatalog)
+	app.Get("/user/:jid/presence", rest.UserPresence)
+	app.Get("/contacts/export", rest.ExportContacts)
 
 	return rest
 }
@@ -38,7 +46,7 @@ func (controller *User) UserInfo(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success get user info",
+		Message: utils.T("user_info_fetched"),
 		Results: response.Data[0],
 	})
 }
@@ -56,7 +64,7 @@ func (controller *User) UserAvatar(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success get avatar",
+		Message: utils.T("avatar_fetched"),
 		Results: response,
 	})
 }
@@ -75,7 +83,7 @@ func (controller *User) UserChangeAvatar(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success change avatar",
+		Message: utils.T("avatar_changed"),
 	})
 }
 
@@ -86,7 +94,7 @@ func (controller *User) UserMyPrivacySetting(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success get privacy",
+		Message: utils.T("privacy_fetched"),
 		Results: response,
 	})
 }
@@ -98,7 +106,7 @@ func (controller *User) UserMyListGroups(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success get list groups",
+		Message: utils.T("groups_listed"),
 		Results: response,
 	})
 }
@@ -110,7 +118,7 @@ func (controller *User) UserMyListNewsletter(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success get list newsletter",
+		Message: utils.T("newsletters_listed"),
 		Results: response,
 	})
 }
@@ -122,7 +130,7 @@ func (controller *User) UserMyListContacts(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success get list contacts",
+		Message: utils.T("contacts_listed"),
 		Results: response,
 	})
 }
@@ -138,6 +146,144 @@ func (controller *User) UserChangePushName(c *fiber.Ctx) error {
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success change push name",
+		Message: utils.T("push_name_changed"),
+	})
+}
+
+func (controller *User) UserResolve(c *fiber.Ctx) error {
+	var request domainUser.ResolveRequest
+	err := c.ParamsParser(&request)
+	utils.PanicIfNeeded(err)
+
+	whatsapp.SanitizePhone(&request.JID)
+
+	response, err := controller.Service.Resolve(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("jid_resolved"),
+		Results: response,
+	})
+}
+
+func (controller *User) UserBlock(c *fiber.Ctx) error {
+	var request domainUser.BlockRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	whatsapp.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.Block(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("user_blocked"),
+		Results: response,
+	})
+}
+
+func (controller *User) UserUnblock(c *fiber.Ctx) error {
+	var request domainUser.BlockRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	whatsapp.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.Unblock(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("user_unblocked"),
+		Results: response,
+	})
+}
+
+func (controller *User) UserBlocklist(c *fiber.Ctx) error {
+	response, err := controller.Service.Blocklist(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("blocklist_fetched"),
+		Results: response,
+	})
+}
+
+func (controller *User) UserBusinessProfile(c *fiber.Ctx) error {
+	var request domainUser.BusinessProfileRequest
+	err := c.ParamsParser(&request)
+	utils.PanicIfNeeded(err)
+
+	whatsapp.SanitizePhone(&request.JID)
+
+	response, err := controller.Service.BusinessProfile(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("business_profile_fetched"),
+		Results: response,
+	})
+}
+
+func (controller *User) UserCatalog(c *fiber.Ctx) error {
+	var request domainUser.CatalogRequest
+	err := c.ParamsParser(&request)
+	utils.PanicIfNeeded(err)
+	err = c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	whatsapp.SanitizePhone(&request.JID)
+
+	response, err := controller.Service.Catalog(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("catalog_fetched"),
+		Results: response,
+	})
+}
+
+func (controller *User) ExportContacts(c *fiber.Ctx) error {
+	request := domainUser.ExportContactsRequest{Page: 1, PerPage: 100}
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ExportContacts(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("contacts_exported"),
+		Results: response,
+	})
+}
+
+func (controller *User) UserPresence(c *fiber.Ctx) error {
+	var request domainUser.PresenceRequest
+	err := c.ParamsParser(&request)
+	utils.PanicIfNeeded(err)
+
+	whatsapp.SanitizePhone(&request.JID)
+
+	response, err := controller.Service.Presence(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: utils.T("user_presence_fetched"),
+		Results: response,
 	})
 }
@@ -6,12 +6,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	fiberUtils "github.com/gofiber/fiber/v2/utils"
 	"github.com/sirupsen/logrus"
@@ -19,6 +25,7 @@ import (
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type serviceApp struct {
@@ -163,8 +170,7 @@ func (service serviceApp) Logout(ctx context.Context) (err error) {
 }
 
 func (service serviceApp) Reconnect(_ context.Context) (err error) {
-	service.WaCli.Disconnect()
-	return service.WaCli.Connect()
+	return whatsapp.Reconnect()
 }
 
 func (service serviceApp) FirstDevice(ctx context.Context) (response domainApp.DevicesResponse, err error) {
@@ -211,3 +217,125 @@ func (service serviceApp) FetchDevices(ctx context.Context) (response []domainAp
 
 	return response, nil
 }
+
+var appStartedAt = time.Now()
+
+func (service serviceApp) Info(_ context.Context) (response domainApp.InfoResponse, err error) {
+	response = domainApp.InfoResponse{
+		Version:       config.AppVersion,
+		BuildCommit:   config.AppBuildCommit,
+		BuildTime:     config.AppBuildTime,
+		GoVersion:     runtime.Version(),
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		StartedAt:     appStartedAt,
+		UptimeSeconds: int64(time.Since(appStartedAt).Seconds()),
+		Webhooks:      []string{},
+		WebhookRoutes: []string{},
+	}
+
+	// Fall back to the VCS stamp Go embeds when the build did not set ldflags.
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && response.BuildCommit == "":
+				response.BuildCommit = setting.Value
+			case setting.Key == "vcs.time" && response.BuildTime == "":
+				response.BuildTime = setting.Value
+			}
+		}
+	}
+
+	for _, webhook := range config.WhatsappWebhook {
+		response.Webhooks = append(response.Webhooks, utils.RedactURL(webhook))
+	}
+	for _, route := range config.WhatsappWebhookRoutes {
+		pattern, target, _ := strings.Cut(route, "=")
+		response.WebhookRoutes = append(response.WebhookRoutes, pattern+"="+utils.RedactURL(target))
+	}
+
+	response.Features = map[string]bool{
+		"debug":              config.AppDebug,
+		"basic_auth":         len(config.AppBasicAuthCredential) > 0,
+		"trusted_proxies":    len(config.AppTrustedProxies) > 0,
+		"auto_reply":         config.WhatsappAutoReplyMessage != "",
+		"webhook":            len(config.WhatsappWebhook) > 0 || len(config.WhatsappWebhookRoutes) > 0,
+		"account_validation": config.WhatsappAccountValidation,
+		"chat_storage":       config.WhatsappChatStorage,
+		"message_signature":  config.WhatsappMessageSignature != "",
+		"send_delay":         config.WhatsappSendMaxDelayMs > 0,
+		"pre_send_typing":    config.WhatsappPreSendTyping,
+		"send_rate_limit":    config.WhatsappSendRateLimit > 0,
+		"contact_export":     config.WhatsappContactExport,
+	}
+
+	return response, nil
+}
+
+func (service serviceApp) LinkedDevices(ctx context.Context) (response []domainApp.LinkedDeviceResponse, err error) {
+	whatsapp.MustLogin(service.WaCli)
+
+	own := *service.WaCli.Store.ID
+	devices, err := service.WaCli.GetUserDevicesContext(ctx, []types.JID{own.ToNonAD()})
+	if err != nil {
+		return nil, err
+	}
+
+	response = make([]domainApp.LinkedDeviceResponse, 0, len(devices))
+	for _, device := range devices {
+		response = append(response, domainApp.LinkedDeviceResponse{
+			Device:   device.String(),
+			DeviceID: device.Device,
+			Primary:  device.Device == 0,
+			Current:  device.Device == own.Device,
+		})
+	}
+	return response, nil
+}
+
+// LogoutDevice logs out one of the account's linked devices and returns the
+// remaining ones. WhatsApp only accepts removing other companions from the
+// primary phone, so a linked device can log out itself but nothing else.
+func (service serviceApp) LogoutDevice(ctx context.Context, request domainApp.LogoutDeviceRequest) (response []domainApp.LinkedDeviceResponse, err error) {
+	if err = validations.ValidateLogoutDevice(ctx, request); err != nil {
+		return nil, err
+	}
+
+	devices, err := service.LinkedDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceID, err := parseDeviceID(request.DeviceID, service.WaCli.Store.ID.User)
+	if err != nil {
+		return nil, err
+	}
+	index := slices.IndexFunc(devices, func(device domainApp.LinkedDeviceResponse) bool {
+		return device.DeviceID == deviceID
+	})
+	switch {
+	case index < 0:
+		return nil, pkgError.ValidationError(utils.T("device_not_linked", deviceID))
+	case devices[index].Primary:
+		return nil, pkgError.ValidationError(utils.T("primary_device_logout_denied"))
+	case !devices[index].Current:
+		return nil, pkgError.ValidationError(utils.T("device_logout_primary_only", deviceID))
+	}
+
+	if err = service.Logout(ctx); err != nil {
+		return nil, err
+	}
+	return append(devices[:index:index], devices[index+1:]...), nil
+}
+
+// parseDeviceID accepts a numeric device id or a device JID of the given user.
+func parseDeviceID(value, user string) (uint16, error) {
+	if id, err := strconv.ParseUint(value, 10, 16); err == nil {
+		return uint16(id), nil
+	}
+	jid, err := types.ParseJID(value)
+	if err != nil || jid.User != user {
+		return 0, pkgError.ValidationError(utils.T("device_id_invalid", value))
+	}
+	return jid.Device, nil
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+
+	domainCommunity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/community"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type serviceCommunity struct {
+	WaCli *whatsmeow.Client
+}
+
+func NewCommunityService(waCli *whatsmeow.Client) domainCommunity.ICommunityUsecase {
+	return &serviceCommunity{
+		WaCli: waCli,
+	}
+}
+
+func (service serviceCommunity) ListGroups(ctx context.Context, request domainCommunity.ListGroupsRequest) (result []domainCommunity.LinkedGroup, err error) {
+	if err = validations.ValidateListCommunityGroups(ctx, request); err != nil {
+		return result, err
+	}
+
+	communityJID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.CommunityID)
+	if err != nil {
+		return result, err
+	}
+
+	groups, err := service.WaCli.GetSubGroups(communityJID)
+	if err != nil {
+		return result, err
+	}
+
+	result = []domainCommunity.LinkedGroup{}
+	for _, group := range groups {
+		result = append(result, domainCommunity.LinkedGroup{
+			JID:               group.JID.String(),
+			Name:              group.Name,
+			IsDefaultSubGroup: group.IsDefaultSubGroup,
+		})
+	}
+	return result, nil
+}
+
+func (service serviceCommunity) LinkGroup(ctx context.Context, request domainCommunity.LinkGroupRequest) (err error) {
+	communityJID, groupJID, err := service.validateLink(ctx, request)
+	if err != nil {
+		return err
+	}
+	return service.WaCli.LinkGroup(communityJID, groupJID)
+}
+
+func (service serviceCommunity) UnlinkGroup(ctx context.Context, request domainCommunity.LinkGroupRequest) (err error) {
+	communityJID, groupJID, err := service.validateLink(ctx, request)
+	if err != nil {
+		return err
+	}
+	return service.WaCli.UnlinkGroup(communityJID, groupJID)
+}
+
+func (service serviceCommunity) validateLink(ctx context.Context, request domainCommunity.LinkGroupRequest) (communityJID, groupJID types.JID, err error) {
+	if err = validations.ValidateLinkCommunityGroup(ctx, request); err != nil {
+		return communityJID, groupJID, err
+	}
+
+	communityJID, err = whatsapp.ValidateJidWithLogin(service.WaCli, request.CommunityID)
+	if err != nil {
+		return communityJID, groupJID, err
+	}
+	groupJID, err = whatsapp.ValidateJidWithLogin(service.WaCli, request.GroupID)
+	return communityJID, groupJID, err
+}
@@ -2,15 +2,21 @@ package usecase
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"net/http"
+	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
 )
 
 type serviceGroup struct {
@@ -36,6 +42,34 @@ func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domai
 	return jid.String(), nil
 }
 
+// GetGroupInfoFromLink previews the group behind an invite link without joining it.
+func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request domainGroup.GetGroupInfoFromLinkRequest) (response domainGroup.GetGroupInfoFromLinkResponse, err error) {
+	if err = validations.ValidateGetGroupInfoFromLink(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	groupInfo, err := service.WaCli.GetGroupInfoFromLink(request.Link)
+	if errors.Is(err, whatsmeow.ErrInviteLinkRevoked) {
+		return response, pkgError.InviteLinkRevoked(utils.T("invite_link_revoked"))
+	} else if errors.Is(err, whatsmeow.ErrInviteLinkInvalid) {
+		return response, pkgError.InviteLinkInvalid(utils.T("invite_link_invalid"))
+	} else if err != nil {
+		return response, err
+	}
+
+	return domainGroup.GetGroupInfoFromLinkResponse{
+		GroupID:          groupInfo.JID.String(),
+		Name:             groupInfo.Name,
+		Description:      groupInfo.Topic,
+		ParticipantCount: len(groupInfo.Participants),
+		CreatedAt:        groupInfo.GroupCreated,
+		IsAnnounce:       groupInfo.IsAnnounce,
+		IsLocked:         groupInfo.IsLocked,
+		ApprovalRequired: groupInfo.IsJoinApprovalRequired,
+	}, nil
+}
+
 func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.LeaveGroupRequest) (err error) {
 	if err = validations.ValidateLeaveGroup(ctx, request); err != nil {
 		return err
@@ -49,6 +83,31 @@ func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.
 	return service.WaCli.LeaveGroup(JID)
 }
 
+// RefreshGroup drops the cached name of a group, e.g. after a rename made
+// elsewhere, and returns the name fetched from WhatsApp.
+func (service serviceGroup) RefreshGroup(ctx context.Context, request domainGroup.RefreshGroupRequest) (response domainGroup.RefreshGroupResponse, err error) {
+	if err = validations.ValidateRefreshGroup(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	JID, err := whatsapp.ResolveRecipientJID(request.GroupID)
+	if err != nil {
+		return response, err
+	}
+	if JID.Server != types.GroupServer {
+		return response, pkgError.ValidationError(utils.T("group_id_not_group"))
+	}
+
+	name, err := whatsapp.RefreshGroupName(ctx, JID)
+	if err != nil {
+		return response, err
+	}
+	response.GroupID = JID.String()
+	response.Name = name
+	return response, nil
+}
+
 func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) (groupID string, err error) {
 	if err = validations.ValidateCreateGroup(ctx, request); err != nil {
 		return groupID, err
@@ -101,13 +160,13 @@ func (service serviceGroup) ManageParticipant(ctx context.Context, request domai
 			result = append(result, domainGroup.ParticipantStatus{
 				Participant: participant.JID.String(),
 				Status:      "error",
-				Message:     "Failed to add participant",
+				Message:     utils.T("participant_add_failed"),
 			})
 		} else {
 			result = append(result, domainGroup.ParticipantStatus{
 				Participant: participant.JID.String(),
 				Status:      "success",
-				Message:     "Action success",
+				Message:     utils.T("action_success"),
 			})
 		}
 	}
@@ -165,13 +224,13 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 			result = append(result, domainGroup.ParticipantStatus{
 				Participant: participant.JID.String(),
 				Status:      "error",
-				Message:     fmt.Sprintf("Action %s failed (code %d)", request.Action, participant.Error),
+				Message:     utils.T("participant_action_failed", request.Action, participant.Error),
 			})
 		} else {
 			result = append(result, domainGroup.ParticipantStatus{
 				Participant: participant.JID.String(),
 				Status:      "success",
-				Message:     fmt.Sprintf("Action %s success", request.Action),
+				Message:     utils.T("participant_action_success", request.Action),
 			})
 		}
 	}
@@ -194,3 +253,143 @@ func (service serviceGroup) participantToJID(participants []string) ([]types.JID
 	}
 	return participantsJID, nil
 }
+
+// Broadcast sends the same message to every selected group one after another;
+// each send goes through the shared rate limiter. Announcement groups where the
+// device is not an admin are skipped rather than attempted.
+func (service serviceGroup) Broadcast(ctx context.Context, request domainGroup.BroadcastRequest) (result []domainGroup.BroadcastStatus, err error) {
+	if err = validations.ValidateGroupBroadcast(ctx, request); err != nil {
+		return result, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	groups, err := service.WaCli.GetJoinedGroups()
+	if err != nil {
+		return result, err
+	}
+
+	if request.Filter == domainGroup.BroadcastFilterList {
+		wanted := make(map[string]bool)
+		for _, groupID := range request.GroupIDs {
+			whatsapp.SanitizePhone(&groupID)
+			wanted[groupID] = true
+		}
+		var selected []*types.GroupInfo
+		for _, group := range groups {
+			if wanted[group.JID.String()] {
+				selected = append(selected, group)
+				delete(wanted, group.JID.String())
+			}
+		}
+		for groupID := range wanted {
+			result = append(result, domainGroup.BroadcastStatus{GroupID: groupID, Status: "skipped", Message: utils.T("not_group_member")})
+		}
+		groups = selected
+	}
+
+	msg, err := service.buildBroadcastMessage(ctx, request)
+	if err != nil {
+		return result, err
+	}
+
+	for _, group := range groups {
+		status := domainGroup.BroadcastStatus{GroupID: group.JID.String(), Name: group.Name}
+		isAdmin := service.isGroupAdmin(group)
+		switch {
+		case request.Filter == domainGroup.BroadcastFilterAdmin && !isAdmin:
+			continue
+		case group.IsAnnounce && !isAdmin:
+			status.Status = "skipped"
+			status.Message = utils.T("group_admins_only")
+		default:
+			resp, sendErr := whatsapp.SendMessage(ctx, group.JID, proto.Clone(msg).(*waE2E.Message))
+			if sendErr != nil {
+				status.Status = "failed"
+				status.Message = sendErr.Error()
+			} else {
+				status.Status = "sent"
+				status.MessageID = resp.ID
+			}
+		}
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+func (service serviceGroup) isGroupAdmin(group *types.GroupInfo) bool {
+	if service.WaCli.Store.ID == nil {
+		return false
+	}
+	own := service.WaCli.Store.ID.ToNonAD()
+	ownLID := service.WaCli.Store.LID.ToNonAD()
+	for _, participant := range group.Participants {
+		if participant.JID == own || participant.PhoneNumber == own || (!ownLID.IsEmpty() && participant.LID == ownLID) {
+			return participant.IsAdmin || participant.IsSuperAdmin
+		}
+	}
+	return false
+}
+
+// buildBroadcastMessage uploads the media once so every group reuses the same upload.
+func (service serviceGroup) buildBroadcastMessage(ctx context.Context, request domainGroup.BroadcastRequest) (*waE2E.Message, error) {
+	text := utils.AppendSignature(request.Message, false)
+	if request.Media == nil {
+		return &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String(text)}}, nil
+	}
+
+	data := helpers.MultipartFormFileHeaderToBytes(request.Media)
+	mimeType := http.DetectContentType(data)
+	mediaType, maxSize := whatsmeow.MediaDocument, config.WhatsappSettingMaxFileSize
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		mediaType, maxSize = whatsmeow.MediaImage, config.WhatsappSettingMaxImageSize
+	case strings.HasPrefix(mimeType, "video/"):
+		mediaType, maxSize = whatsmeow.MediaVideo, config.WhatsappSettingMaxVideoSize
+	}
+	if int64(len(data)) > maxSize {
+		return nil, pkgError.ValidationError(utils.T("media_too_large", maxSize))
+	}
+
+	uploaded, err := service.WaCli.Upload(ctx, data, mediaType)
+	if err != nil {
+		return nil, pkgError.WaUploadMediaError(utils.T("upload_media_failed", err))
+	}
+
+	switch mediaType {
+	case whatsmeow.MediaImage:
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			Caption:       proto.String(text),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+		}}, nil
+	case whatsmeow.MediaVideo:
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			Caption:       proto.String(text),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+		}}, nil
+	default:
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+			Caption:       proto.String(text),
+			FileName:      proto.String(request.Media.Filename),
+			Mimetype:      proto.String(mimeType),
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+		}}, nil
+	}
+}
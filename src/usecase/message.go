@@ -2,11 +2,11 @@ package usecase
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
@@ -50,7 +50,7 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 	})
 
 	response.MessageID = request.MessageID
-	response.Status = fmt.Sprintf("Mark as read success %s", request.MessageID)
+	response.Status = utils.T("mark_read_success", request.MessageID)
 	return response, nil
 }
 
@@ -74,13 +74,13 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 			SenderTimestampMS: proto.Int64(time.Now().UnixMilli()),
 		},
 	}
-	ts, err := service.WaCli.SendMessage(ctx, dataWaRecipient, msg)
+	ts, err := whatsapp.SendMessage(ctx, dataWaRecipient, msg)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Reaction sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp)
+	response.Status = utils.T("reaction_sent_to", request.Phone, ts.Timestamp)
 	return response, nil
 }
 
@@ -93,13 +93,13 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 		return response, err
 	}
 
-	ts, err := service.WaCli.SendMessage(context.Background(), dataWaRecipient, service.WaCli.BuildRevoke(dataWaRecipient, types.EmptyJID, request.MessageID))
+	ts, err := whatsapp.SendMessage(ctx, dataWaRecipient, service.WaCli.BuildRevoke(dataWaRecipient, types.EmptyJID, request.MessageID))
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Revoke success %s (server timestamp: %s)", request.Phone, ts.Timestamp)
+	response.Status = utils.T("revoke_success", request.Phone, ts.Timestamp)
 	return response, nil
 }
 
@@ -148,13 +148,13 @@ func (service serviceMessage) UpdateMessage(ctx context.Context, request domainM
 	}
 
 	msg := &waE2E.Message{Conversation: proto.String(request.Message)}
-	ts, err := service.WaCli.SendMessage(context.Background(), dataWaRecipient, service.WaCli.BuildEdit(dataWaRecipient, request.MessageID, msg))
+	ts, err := whatsapp.SendMessage(ctx, dataWaRecipient, service.WaCli.BuildEdit(dataWaRecipient, request.MessageID, msg))
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Update message success %s (server timestamp: %s)", request.Phone, ts.Timestamp)
+	response.Status = utils.T("update_message_success", request.Phone, ts.Timestamp)
 	return response, nil
 }
 
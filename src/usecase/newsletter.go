@@ -7,6 +7,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type serviceNewsletter struct {
@@ -31,3 +32,58 @@ func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNew
 
 	return service.WaCli.UnfollowNewsletter(JID)
 }
+
+func (service serviceNewsletter) Messages(ctx context.Context, request domainNewsletter.MessagesRequest) (response domainNewsletter.MessagesResponse, err error) {
+	if err = validations.ValidateNewsletterMessages(ctx, request); err != nil {
+		return response, err
+	}
+
+	JID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	info, err := service.WaCli.GetNewsletterInfo(JID)
+	if err != nil {
+		return response, err
+	}
+	count := request.Count
+	if count == 0 {
+		count = 20
+	}
+	messages, err := service.WaCli.GetNewsletterMessages(JID, &whatsmeow.GetNewsletterMessagesParams{
+		Count:  count,
+		Before: types.MessageServerID(request.Before),
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.NewsletterID = JID.String()
+	response.ReactionMode = string(info.ThreadMeta.Settings.ReactionCodes.Value)
+	response.Messages = make([]domainNewsletter.MessageStats, 0, len(messages))
+	for _, message := range messages {
+		response.Messages = append(response.Messages, domainNewsletter.MessageStats{
+			ServerID:  int(message.MessageServerID),
+			MessageID: message.MessageID,
+			Type:      message.Type,
+			Timestamp: message.Timestamp,
+			Views:     message.ViewsCount,
+			Reactions: message.ReactionCounts,
+		})
+	}
+	return response, nil
+}
+
+func (service serviceNewsletter) React(ctx context.Context, request domainNewsletter.ReactRequest) (err error) {
+	if err = validations.ValidateNewsletterReact(ctx, request); err != nil {
+		return err
+	}
+
+	JID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.NewsletterID)
+	if err != nil {
+		return err
+	}
+
+	return service.WaCli.NewsletterSendReaction(JID, types.MessageServerID(request.ServerID), request.Reaction, "")
+}
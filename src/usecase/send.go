@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"slices"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
@@ -39,7 +40,7 @@ func NewSendService(waCli *whatsmeow.Client, appService app.IAppUsecase) domainS
 
 // wrapSendMessage wraps the message sending process with message ID saving
 func (service serviceSend) wrapSendMessage(ctx context.Context, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
-	ts, err := service.WaCli.SendMessage(ctx, recipient, msg)
+	ts, err := whatsapp.SendMessage(ctx, recipient, msg)
 	if err != nil {
 		return whatsmeow.SendResponse{}, err
 	}
@@ -54,15 +55,31 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 	if err != nil {
 		return response, err
 	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
+	message := request.Message
+	var mentionAll []string
+	if request.MentionAll {
+		var tokens string
+		if mentionAll, tokens, err = whatsapp.MentionAll(dataWaRecipient); err != nil {
+			return response, err
+		}
+		if tokens != "" {
+			message += "\n\n" + tokens
+		}
+	}
+	text := utils.AppendSignature(message, request.SkipSignature)
+
 	// Create base message
 	msg := &waE2E.Message{
 		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
-			Text:        proto.String(request.Message),
+			Text:        proto.String(text),
 			ContextInfo: &waE2E.ContextInfo{},
 		},
 	}
@@ -74,16 +91,35 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 	}
 
 	parsedMentions := service.getMentionFromText(ctx, request.Message)
+	for _, jid := range mentionAll {
+		if !slices.Contains(parsedMentions, jid) {
+			parsedMentions = append(parsedMentions, jid)
+		}
+	}
 	if len(parsedMentions) > 0 {
 		msg.ExtendedTextMessage.ContextInfo.MentionedJID = parsedMentions
 	}
 
 	// Reply message
-	if request.ReplyMessageID != nil && *request.ReplyMessageID != "" {
+	if request.ReplyChat != "" {
+		replyChat, err := whatsapp.ResolveRecipientJID(request.ReplyChat)
+		if err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("reply_chat: %v", err))
+		}
+		quote, err := whatsapp.CrossChatQuote(replyChat, *request.ReplyMessageID)
+		if err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("reply_message_id: %v", err))
+		}
+		contextInfo := msg.ExtendedTextMessage.ContextInfo
+		contextInfo.StanzaID = quote.StanzaID
+		contextInfo.Participant = quote.Participant
+		contextInfo.RemoteJID = quote.RemoteJID
+		contextInfo.QuotedMessage = quote.QuotedMessage
+	} else if request.ReplyMessageID != nil && *request.ReplyMessageID != "" {
 		record, err := utils.FindRecordFromStorage(*request.ReplyMessageID)
 		if err == nil { // Only set reply context if we found the message ID
 			msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{
-				Text: proto.String(request.Message),
+				Text: proto.String(text),
 				ContextInfo: &waE2E.ContextInfo{
 					StanzaID:    request.ReplyMessageID,
 					Participant: proto.String(record.JID),
@@ -101,13 +137,14 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 		}
 	}
 
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, request.Message)
+	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, text)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Message sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = utils.T("message_sent_to", request.Phone, ts.Timestamp.String())
+	response.Mentioned = len(mentionAll)
 	return response, nil
 }
 
@@ -116,6 +153,9 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	if err != nil {
 		return response, err
 	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
 		return response, err
@@ -132,18 +172,24 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	if request.ImageURL != nil && *request.ImageURL != "" {
 		// Download image from URL
 		imageData, fileName, err := utils.DownloadImageFromURL(*request.ImageURL)
-		oriImagePath = fmt.Sprintf("%s/%s", config.PathSendItems, fileName)
 		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download image from URL %v", err))
+			return response, pkgError.InternalServerError(utils.T("download_image_failed", err))
+		}
+		oriImagePath, err = utils.SafeJoin(config.PathSendItems, fileName)
+		if err != nil {
+			return response, pkgError.ValidationError(err.Error())
 		}
 		imageName = fileName
 		err = os.WriteFile(oriImagePath, imageData, 0644)
 		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to save downloaded image %v", err))
+			return response, pkgError.InternalServerError(utils.T("save_downloaded_image_failed", err))
 		}
 	} else if request.Image != nil {
 		// Save image to server
-		oriImagePath = fmt.Sprintf("%s/%s", config.PathSendItems, request.Image.Filename)
+		oriImagePath, err = utils.SafeJoin(config.PathSendItems, request.Image.Filename)
+		if err != nil {
+			return response, pkgError.ValidationError(err.Error())
+		}
 		err = fasthttp.SaveMultipartFile(request.Image, oriImagePath)
 		if err != nil {
 			return response, err
@@ -155,14 +201,17 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	/* Generate thumbnail with smalled image size */
 	srcImage, err := imaging.Open(oriImagePath)
 	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to open image %v", err))
+		return response, pkgError.InternalServerError(utils.T("open_image_failed", err))
 	}
 
 	// Resize Thumbnail
 	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
-	imageThumbnail = fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, imageName)
+	imageThumbnail, err = utils.SafeJoin(config.PathSendItems, "thumbnails-"+imageName)
+	if err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	if err = imaging.Save(resizedImage, imageThumbnail); err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
+		return response, pkgError.InternalServerError(utils.T("save_thumbnail_failed", err))
 	}
 	deletedItems = append(deletedItems, imageThumbnail)
 
@@ -170,12 +219,15 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 		// Resize image
 		openImageBuffer, err := imaging.Open(oriImagePath)
 		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to open image %v", err))
+			return response, pkgError.InternalServerError(utils.T("open_image_failed", err))
 		}
 		newImage := imaging.Resize(openImageBuffer, 600, 0, imaging.Lanczos)
-		newImagePath := fmt.Sprintf("%s/new-%s", config.PathSendItems, imageName)
+		newImagePath, err := utils.SafeJoin(config.PathSendItems, "new-"+imageName)
+		if err != nil {
+			return response, pkgError.ValidationError(err.Error())
+		}
 		if err = imaging.Save(newImage, newImagePath); err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to save image %v", err))
+			return response, pkgError.InternalServerError(utils.T("save_image_failed", err))
 		}
 		deletedItems = append(deletedItems, newImagePath)
 		imagePath = newImagePath
@@ -184,7 +236,7 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	}
 
 	// Send to WA server
-	dataWaCaption := request.Caption
+	dataWaCaption := utils.AppendCaptionSignature(request.Caption, request.SkipSignature)
 	dataWaImage, err := os.ReadFile(imagePath)
 	if err != nil {
 		return response, err
@@ -196,7 +248,7 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	}
 	dataWaThumbnail, err := os.ReadFile(imageThumbnail)
 	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to read thumbnail %v", err))
+		return response, pkgError.InternalServerError(utils.T("read_thumbnail_failed", err))
 	}
 
 	msg := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
@@ -235,7 +287,7 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Message sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = utils.T("message_sent_to", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -244,6 +296,9 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 	if err != nil {
 		return response, err
 	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
 		return response, err
@@ -269,7 +324,7 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 		FileName:      proto.String(request.File.Filename),
 		FileEncSHA256: uploadedFile.FileEncSHA256,
 		DirectPath:    proto.String(uploadedFile.DirectPath),
-		Caption:       proto.String(request.Caption),
+		Caption:       proto.String(utils.AppendCaptionSignature(request.Caption, request.SkipSignature)),
 	}}
 
 	if request.IsForwarded {
@@ -289,7 +344,7 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Document sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = utils.T("document_sent_to", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -298,6 +353,9 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	if err != nil {
 		return response, err
 	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
 		return response, err
@@ -311,16 +369,19 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 
 	generateUUID := fiberUtils.UUIDv4()
 	// Save video to server
-	oriVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+request.Video.Filename)
+	oriVideoPath, err := utils.SafeJoin(config.PathSendItems, generateUUID+request.Video.Filename)
+	if err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	err = fasthttp.SaveMultipartFile(request.Video, oriVideoPath)
 	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to store video in server %v", err))
+		return response, pkgError.InternalServerError(utils.T("store_video_failed", err))
 	}
 
 	// Check if ffmpeg is installed
 	_, err = exec.LookPath("ffmpeg")
 	if err != nil {
-		return response, pkgError.InternalServerError("ffmpeg not installed")
+		return response, pkgError.InternalServerError(utils.T("ffmpeg_not_installed"))
 	}
 
 	// Get thumbnail video with ffmpeg
@@ -328,18 +389,18 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	cmdThumbnail := exec.Command("ffmpeg", "-i", oriVideoPath, "-ss", "00:00:01.000", "-vframes", "1", thumbnailVideoPath)
 	err = cmdThumbnail.Run()
 	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to create thumbnail %v", err))
+		return response, pkgError.InternalServerError(utils.T("create_thumbnail_failed", err))
 	}
 
 	// Resize Thumbnail
 	srcImage, err := imaging.Open(thumbnailVideoPath)
 	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to open image %v", err))
+		return response, pkgError.InternalServerError(utils.T("open_image_failed", err))
 	}
 	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
 	thumbnailResizeVideoPath := fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, generateUUID+".png")
 	if err = imaging.Save(resizedImage, thumbnailResizeVideoPath); err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
+		return response, pkgError.InternalServerError(utils.T("save_thumbnail_failed", err))
 	}
 
 	deletedItems = append(deletedItems, thumbnailVideoPath)
@@ -352,7 +413,7 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 		cmdCompress := exec.Command("ffmpeg", "-i", oriVideoPath, "-strict", "-2", compresVideoPath)
 		err = cmdCompress.Run()
 		if err != nil {
-			return response, pkgError.InternalServerError("failed to compress video")
+			return response, pkgError.InternalServerError(utils.T("compress_video_failed"))
 		}
 
 		videoPath = compresVideoPath
@@ -367,9 +428,10 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	if err != nil {
 		return response, err
 	}
+	dataWaVideo, _ = whatsapp.TranscodeVideo(ctx, dataWaVideo, http.DetectContentType(dataWaVideo))
 	uploaded, err := service.uploadMedia(ctx, whatsmeow.MediaVideo, dataWaVideo, dataWaRecipient)
 	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("Failed to upload file: %v", err))
+		return response, pkgError.InternalServerError(utils.T("upload_file_failed", err))
 	}
 	dataWaThumbnail, err := os.ReadFile(videoThumbnail)
 	if err != nil {
@@ -379,7 +441,7 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	msg := &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
 		URL:                 proto.String(uploaded.URL),
 		Mimetype:            proto.String(http.DetectContentType(dataWaVideo)),
-		Caption:             proto.String(request.Caption),
+		Caption:             proto.String(utils.AppendCaptionSignature(request.Caption, request.SkipSignature)),
 		FileLength:          proto.Uint64(uploaded.FileLength),
 		FileSHA256:          uploaded.FileSHA256,
 		FileEncSHA256:       uploaded.FileEncSHA256,
@@ -415,7 +477,7 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Video sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = utils.T("video_sent_to", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -424,15 +486,24 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 	if err != nil {
 		return response, err
 	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
 		return response, err
 	}
 
-	msgVCard := fmt.Sprintf("BEGIN:VCARD\nVERSION:3.0\nN:;%v;;;\nFN:%v\nTEL;type=CELL;waid=%v:+%v\nEND:VCARD",
-		request.ContactName, request.ContactName, request.ContactPhone, request.ContactPhone)
+	msgVCard, displayName, err := whatsapp.ContactVCard(request.ContactPhone, request.ContactName)
+	if err != nil {
+		// This route has always taken contact_phone as given, local formats
+		// included, so a number that does not normalize keeps the plain card.
+		msgVCard = fmt.Sprintf("BEGIN:VCARD\nVERSION:3.0\nN:;%v;;;\nFN:%v\nTEL;type=CELL;waid=%v:+%v\nEND:VCARD",
+			request.ContactName, request.ContactName, request.ContactPhone, request.ContactPhone)
+		displayName = request.ContactName
+	}
 	msg := &waE2E.Message{ContactMessage: &waE2E.ContactMessage{
-		DisplayName: proto.String(request.ContactName),
+		DisplayName: proto.String(displayName),
 		Vcard:       proto.String(msgVCard),
 	}}
 
@@ -451,7 +522,7 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Contact sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = utils.T("contact_sent_to", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -460,6 +531,9 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 	if err != nil {
 		return response, err
 	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
 		return response, err
@@ -519,7 +593,7 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Link sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = utils.T("link_sent_to", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -528,6 +602,9 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 	if err != nil {
 		return response, err
 	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
 		return response, err
@@ -557,7 +634,7 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Send location success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = utils.T("location_sent_to", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -566,6 +643,9 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 	if err != nil {
 		return response, err
 	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
 		return response, err
@@ -573,10 +653,11 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 
 	autioBytes := helpers.MultipartFormFileHeaderToBytes(request.Audio)
 	audioMimeType := http.DetectContentType(autioBytes)
+	autioBytes, audioMimeType = whatsapp.TranscodeAudio(ctx, autioBytes, audioMimeType)
 
 	audioUploaded, err := service.uploadMedia(ctx, whatsmeow.MediaAudio, autioBytes, dataWaRecipient)
 	if err != nil {
-		err = pkgError.WaUploadMediaError(fmt.Sprintf("Failed to upload audio: %v", err))
+		err = pkgError.WaUploadMediaError(utils.T("upload_audio_failed", err))
 		return response, err
 	}
 
@@ -607,7 +688,7 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Send audio success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = utils.T("audio_sent_to", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -616,6 +697,9 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 	if err != nil {
 		return response, err
 	}
+	if ctx, err = whatsapp.WithRequestedMessageID(ctx, request.MessageID); err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
 	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
 		return response, err
@@ -631,7 +715,7 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Send poll success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = utils.T("poll_sent_to", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -647,10 +731,53 @@ func (service serviceSend) SendPresence(ctx context.Context, request domainSend.
 	}
 
 	response.MessageID = "presence"
-	response.Status = fmt.Sprintf("Send presence success %s", request.Type)
+	response.Status = utils.T("presence_type_sent", request.Type)
 	return response, nil
 }
 
+// SendBroadcast sends the message to each recipient as its own 1:1 chat, the
+// way a WhatsApp broadcast list does, one after another through the send rate
+// limiter. A failing recipient does not stop the others.
+func (service serviceSend) SendBroadcast(ctx context.Context, request domainSend.BroadcastRequest) (result []domainSend.BroadcastStatus, err error) {
+	if err = validations.ValidateSendBroadcast(ctx, request); err != nil {
+		return result, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	text := utils.AppendSignature(request.Message, request.SkipSignature)
+	msg := &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String(text)}}
+
+	seen := make(map[types.JID]bool, len(request.Phones))
+	for _, phone := range request.Phones {
+		status := domainSend.BroadcastStatus{Phone: phone}
+		recipient, resolveErr := whatsapp.ResolveRecipientJID(phone)
+		switch {
+		case resolveErr != nil:
+			status.Status = "failed"
+			status.Message = resolveErr.Error()
+		case recipient.Server != types.DefaultUserServer && recipient.Server != types.HiddenUserServer:
+			status.Status = "skipped"
+			status.Message = "broadcast recipients must be contacts, not groups or channels"
+		case seen[recipient]:
+			status.Status = "skipped"
+			status.Message = utils.T("duplicate_recipient")
+		default:
+			seen[recipient] = true
+			resp, sendErr := service.wrapSendMessage(ctx, recipient, proto.Clone(msg).(*waE2E.Message), request.Message)
+			if sendErr != nil {
+				status.Status = "failed"
+				status.Message = sendErr.Error()
+			} else {
+				status.Status = "sent"
+				status.MessageID = resp.ID
+			}
+		}
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
 func (service serviceSend) getMentionFromText(_ context.Context, messages string) (result []string) {
 	mentions := utils.ContainsMention(messages)
 	for _, mention := range mentions {
@@ -663,10 +790,14 @@ func (service serviceSend) getMentionFromText(_ context.Context, messages string
 }
 
 func (service serviceSend) uploadMedia(ctx context.Context, mediaType whatsmeow.MediaType, media []byte, recipient types.JID) (uploaded whatsmeow.UploadResponse, err error) {
+	// The policy is checked again on send; checking here saves the upload.
+	if err = whatsapp.CheckRecipient(recipient); err != nil {
+		return uploaded, err
+	}
 	if recipient.Server == types.NewsletterServer {
 		uploaded, err = service.WaCli.UploadNewsletter(ctx, media, mediaType)
 	} else {
-		uploaded, err = service.WaCli.Upload(ctx, media, mediaType)
+		uploaded, err = whatsapp.UploadMedia(ctx, service.WaCli, media, mediaType)
 	}
 	return uploaded, err
 }
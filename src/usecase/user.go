@@ -6,16 +6,22 @@ import (
 	"errors"
 	"fmt"
 	"image"
+	"slices"
+	"sort"
 	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/disintegration/imaging"
+	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
 	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
 )
 
 type serviceUser struct {
@@ -93,7 +99,7 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 		if err != nil {
 			chanErr <- err
 		} else if pic == nil {
-			chanErr <- errors.New("no avatar found")
+			chanErr <- errors.New(utils.T("avatar_not_found"))
 		} else {
 			response.URL = pic.URL
 			response.ID = pic.ID
@@ -111,7 +117,7 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 			return response, nil
 		default:
 			if waktu.Add(2 * time.Second).Before(time.Now()) {
-				return response, pkgError.ContextError("Error timeout get avatar !")
+				return response, pkgError.ContextError(utils.T("avatar_timeout"))
 			}
 		}
 	}
@@ -242,3 +248,292 @@ func (service serviceUser) ChangePushName(ctx context.Context, request domainUse
 	}
 	return nil
 }
+
+// Resolve maps a phone JID to its LID (or the reverse) using the device's LID store.
+func (service serviceUser) Resolve(ctx context.Context, request domainUser.ResolveRequest) (response domainUser.ResolveResponse, err error) {
+	if err = validations.ValidateUserResolve(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	jid, err := whatsapp.ParseJID(request.JID)
+	if err != nil {
+		return response, err
+	}
+	jid = jid.ToNonAD()
+	response.JID = jid.String()
+
+	var phoneJID, lid types.JID
+	switch jid.Server {
+	case types.HiddenUserServer:
+		lid = jid
+		phoneJID, err = service.WaCli.Store.LIDs.GetPNForLID(ctx, jid)
+	case types.DefaultUserServer:
+		phoneJID = jid
+		lid, err = service.WaCli.Store.LIDs.GetLIDForPN(ctx, jid)
+	default:
+		return response, pkgError.InvalidJID(utils.T("resolve_jid_unsupported"))
+	}
+	if err != nil {
+		return response, pkgError.InternalServerError(utils.T("read_lid_mapping_failed", err))
+	}
+
+	if !phoneJID.IsEmpty() {
+		response.PhoneJID = phoneJID.String()
+	}
+	if !lid.IsEmpty() {
+		response.LID = lid.String()
+	}
+	if phoneJID.IsEmpty() && lid.IsEmpty() {
+		return response, nil
+	}
+
+	infoJID := phoneJID
+	if infoJID.IsEmpty() {
+		infoJID = lid
+	}
+	users, err := service.WaCli.GetUserInfo([]types.JID{infoJID})
+	if err != nil {
+		logrus.Warnf("Failed to fetch verified name for %s: %v", infoJID.String(), err)
+		return response, nil
+	}
+	for _, info := range users {
+		if info.VerifiedName != nil && info.VerifiedName.Details != nil {
+			response.VerifiedName = info.VerifiedName.Details.GetVerifiedName()
+		}
+	}
+
+	return response, nil
+}
+
+func (service serviceUser) Block(ctx context.Context, request domainUser.BlockRequest) (response domainUser.BlockResponse, err error) {
+	return service.updateBlocklist(ctx, request, events.BlocklistChangeActionBlock)
+}
+
+func (service serviceUser) Unblock(ctx context.Context, request domainUser.BlockRequest) (response domainUser.BlockResponse, err error) {
+	return service.updateBlocklist(ctx, request, events.BlocklistChangeActionUnblock)
+}
+
+func (service serviceUser) Blocklist(_ context.Context) (response domainUser.BlocklistResponse, err error) {
+	whatsapp.MustLogin(service.WaCli)
+
+	blocklist, err := service.WaCli.GetBlocklist()
+	if err != nil {
+		return response, err
+	}
+	response.Data = blocklistJIDs(blocklist)
+	return response, nil
+}
+
+// updateBlocklist only calls WhatsApp when the contact is not already in the
+// requested state, so repeated block/unblock calls are harmless.
+func (service serviceUser) updateBlocklist(ctx context.Context, request domainUser.BlockRequest, action events.BlocklistChangeAction) (response domainUser.BlockResponse, err error) {
+	if err = validations.ValidateUserBlock(ctx, request); err != nil {
+		return response, err
+	}
+	jid, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	jid = jid.ToNonAD()
+
+	blocklist, err := service.WaCli.GetBlocklist()
+	if err != nil {
+		return response, err
+	}
+
+	wantBlocked := action == events.BlocklistChangeActionBlock
+	isBlocked := slices.Contains(blocklist.JIDs, jid)
+	if isBlocked != wantBlocked {
+		blocklist, err = service.WaCli.UpdateBlocklist(jid, action)
+		if err != nil {
+			return response, err
+		}
+		response.Changed = true
+	}
+
+	response.Phone = jid.String()
+	response.Blocked = wantBlocked
+	response.Blocklist = blocklistJIDs(blocklist)
+	return response, nil
+}
+
+func blocklistJIDs(blocklist *types.Blocklist) []string {
+	jids := make([]string, 0, len(blocklist.JIDs))
+	for _, jid := range blocklist.JIDs {
+		jids = append(jids, jid.String())
+	}
+	return jids
+}
+
+func (service serviceUser) BusinessProfile(ctx context.Context, request domainUser.BusinessProfileRequest) (response domainUser.BusinessProfileResponse, err error) {
+	if err = validations.ValidateUserBusinessProfile(ctx, request); err != nil {
+		return response, err
+	}
+	jid, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.JID)
+	if err != nil {
+		return response, err
+	}
+
+	profile, err := service.WaCli.GetBusinessProfile(jid)
+	if err != nil {
+		return response, err
+	}
+
+	response.JID = profile.JID.String()
+	response.Email = profile.Email
+	response.Address = profile.Address
+	response.ProfileOptions = profile.ProfileOptions
+	response.BusinessHoursTimeZone = profile.BusinessHoursTimeZone
+	for _, category := range profile.Categories {
+		response.Categories = append(response.Categories, domainUser.BusinessCategory{
+			ID:   category.ID,
+			Name: category.Name,
+		})
+	}
+	for _, hours := range profile.BusinessHours {
+		response.BusinessHours = append(response.BusinessHours, domainUser.BusinessHours{
+			DayOfWeek: hours.DayOfWeek,
+			Mode:      hours.Mode,
+			OpenTime:  hours.OpenTime,
+			CloseTime: hours.CloseTime,
+		})
+	}
+	return response, nil
+}
+
+// Catalog returns one page of the product catalog of a business account.
+func (service serviceUser) Catalog(ctx context.Context, request domainUser.CatalogRequest) (response domainUser.CatalogResponse, err error) {
+	if err = validations.ValidateUserCatalog(ctx, request); err != nil {
+		return response, err
+	}
+	jid, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.JID)
+	if err != nil {
+		return response, err
+	}
+
+	products, next, err := whatsapp.CatalogPage(ctx, service.WaCli, jid, request.After)
+	if err != nil {
+		return response, err
+	}
+
+	response.JID = jid.String()
+	response.NextCursor = next
+	response.Products = make([]domainUser.CatalogProduct, 0, len(products))
+	for _, product := range products {
+		response.Products = append(response.Products, domainUser.CatalogProduct{
+			ID:              product.ID,
+			RetailerID:      product.RetailerID,
+			Name:            product.Name,
+			Description:     product.Description,
+			PriceAmount1000: product.PriceAmount1000,
+			Currency:        product.Currency,
+			URL:             product.URL,
+			ImageURL:        product.ImageURL,
+			Hidden:          product.Hidden,
+		})
+	}
+	return response, nil
+}
+
+// Presence returns the last presence received from a contact. It subscribes to
+// the contact's presence, so a contact never seen before is reported as not
+// found until its first update arrives.
+func (service serviceUser) Presence(ctx context.Context, request domainUser.PresenceRequest) (response domainUser.PresenceResponse, err error) {
+	if err = validations.ValidateUserPresence(ctx, request); err != nil {
+		return response, err
+	}
+	jid, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.JID)
+	if err != nil {
+		return response, err
+	}
+	if err = service.WaCli.SubscribePresence(jid); err != nil {
+		logrus.Warnf("Failed to subscribe to presence of %s: %v", jid.String(), err)
+	}
+
+	presence, ok := whatsapp.LastPresence(jid)
+	if !ok {
+		return response, pkgError.NotFoundError(utils.T("presence_not_received", jid.String()))
+	}
+	response.JID = jid.String()
+	response.Status = "offline"
+	if presence.Online {
+		response.Status = "online"
+	}
+	if !presence.LastSeen.IsZero() {
+		response.LastSeen = &presence.LastSeen
+	}
+	response.UpdatedAt = presence.UpdatedAt
+	return response, nil
+}
+
+// ExportContacts merges the contact store with the participants of every joined
+// group, deduplicated by phone JID, and returns one page sorted by JID.
+func (service serviceUser) ExportContacts(ctx context.Context, request domainUser.ExportContactsRequest) (response domainUser.ExportContactsResponse, err error) {
+	if !config.WhatsappContactExport {
+		return response, pkgError.FeatureDisabledError(utils.T("contact_export_disabled"))
+	}
+	if err = validations.ValidateExportContacts(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	entries := make(map[types.JID]*domainUser.ExportContact)
+	entry := func(jid types.JID) *domainUser.ExportContact {
+		jid = jid.ToNonAD()
+		if existing, ok := entries[jid]; ok {
+			return existing
+		}
+		created := &domainUser.ExportContact{JID: jid.String(), Groups: []string{}}
+		if jid.Server == types.DefaultUserServer {
+			created.Phone = jid.User
+		}
+		entries[jid] = created
+		return created
+	}
+
+	contacts, err := service.WaCli.Store.Contacts.GetAllContacts(ctx)
+	if err != nil {
+		return response, err
+	}
+	for jid, contact := range contacts {
+		if jid.Server != types.DefaultUserServer {
+			continue
+		}
+		exported := entry(jid)
+		exported.InContacts = true
+		exported.Name = contact.FullName
+		if exported.Name == "" {
+			exported.Name = contact.PushName
+		}
+	}
+
+	groups, err := service.WaCli.GetJoinedGroups()
+	if err != nil {
+		return response, err
+	}
+	for _, group := range groups {
+		for _, participant := range group.Participants {
+			jid := participant.JID
+			if !participant.PhoneNumber.IsEmpty() {
+				jid = participant.PhoneNumber
+			}
+			exported := entry(jid)
+			exported.Groups = append(exported.Groups, group.JID.String())
+		}
+	}
+
+	all := make([]domainUser.ExportContact, 0, len(entries))
+	for _, exported := range entries {
+		all = append(all, *exported)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].JID < all[j].JID })
+
+	response.Page = request.Page
+	response.PerPage = request.PerPage
+	response.Total = len(all)
+	start := min((request.Page-1)*request.PerPage, len(all))
+	end := min(start+request.PerPage, len(all))
+	response.Data = all[start:end]
+	return response, nil
+}
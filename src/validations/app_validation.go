@@ -3,6 +3,7 @@ package validations
 import (
 	"context"
 	"fmt"
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"regexp"
@@ -19,3 +20,15 @@ func ValidateLoginWithCode(ctx context.Context, phoneNumber string) error {
 	}
 	return nil
 }
+
+func ValidateLogoutDevice(ctx context.Context, request domainApp.LogoutDeviceRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.DeviceID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
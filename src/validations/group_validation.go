@@ -21,6 +21,18 @@ func ValidateJoinGroupWithLink(ctx context.Context, request domainGroup.JoinGrou
 	return nil
 }
 
+func ValidateGetGroupInfoFromLink(ctx context.Context, request domainGroup.GetGroupInfoFromLinkRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Link, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidateLeaveGroup(ctx context.Context, request domainGroup.LeaveGroupRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.GroupID, validation.Required),
@@ -33,6 +45,18 @@ func ValidateLeaveGroup(ctx context.Context, request domainGroup.LeaveGroupReque
 	return nil
 }
 
+func ValidateRefreshGroup(ctx context.Context, request domainGroup.RefreshGroupRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidateCreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Title, validation.Required),
@@ -87,3 +111,17 @@ func ValidateManageGroupRequestParticipants(ctx context.Context, request domainG
 
 	return nil
 }
+
+func ValidateGroupBroadcast(ctx context.Context, request domainGroup.BroadcastRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Message, validation.When(request.Media == nil, validation.Required)),
+		validation.Field(&request.Filter, validation.In(domainGroup.BroadcastFilterAll, domainGroup.BroadcastFilterAdmin, domainGroup.BroadcastFilterList)),
+		validation.Field(&request.GroupIDs, validation.When(request.Filter == domainGroup.BroadcastFilterList, validation.Required)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
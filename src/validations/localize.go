@@ -0,0 +1,34 @@
+package validations
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// LocalizeErrors translates the messages of the validation rules used by this
+// package into config.AppLanguage. The catalog keys are the ozzo error codes,
+// so it has to run once the configuration is loaded and before any request.
+func LocalizeErrors() {
+	validation.ErrRequired = localize(validation.ErrRequired)
+	validation.ErrMinGreaterEqualThanRequired = localize(validation.ErrMinGreaterEqualThanRequired)
+	validation.ErrMaxLessEqualThanRequired = localize(validation.ErrMaxLessEqualThanRequired)
+	validation.ErrInInvalid = localize(validation.ErrInInvalid)
+	validation.ErrMatchInvalid = localize(validation.ErrMatchInvalid)
+	validation.ErrLengthTooLong = localize(validation.ErrLengthTooLong)
+	validation.ErrLengthTooShort = localize(validation.ErrLengthTooShort)
+	validation.ErrLengthInvalid = localize(validation.ErrLengthInvalid)
+	validation.ErrLengthOutOfRange = localize(validation.ErrLengthOutOfRange)
+
+	// The is rules keep the error they were built with, so they are rebuilt.
+	is.ErrURL = localize(is.ErrURL)
+	is.ErrLatitude = localize(is.ErrLatitude)
+	is.ErrLongitude = localize(is.ErrLongitude)
+	is.URL = is.URL.ErrorObject(is.ErrURL)
+	is.Latitude = is.Latitude.ErrorObject(is.ErrLatitude)
+	is.Longitude = is.Longitude.ErrorObject(is.ErrLongitude)
+}
+
+func localize(err validation.Error) validation.Error {
+	return err.SetMessage(utils.T(err.Code()))
+}
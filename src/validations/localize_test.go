@@ -0,0 +1,31 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalizeErrors(t *testing.T) {
+	original := config.AppLanguage
+	defer func() {
+		config.AppLanguage = original
+		LocalizeErrors()
+	}()
+
+	config.AppLanguage = "pt"
+	LocalizeErrors()
+
+	err := ValidateExportContacts(context.Background(), domainUser.ExportContactsRequest{Page: 0, PerPage: 5000})
+	assert.Equal(t, pkgError.ValidationError("page: não pode ficar em branco; per_page: deve ser no máximo 1000."), err)
+
+	config.AppLanguage = "en"
+	LocalizeErrors()
+
+	err = ValidateExportContacts(context.Background(), domainUser.ExportContactsRequest{Page: 0, PerPage: 5000})
+	assert.Equal(t, pkgError.ValidationError("page: cannot be blank; per_page: must be no greater than 1000."), err)
+}
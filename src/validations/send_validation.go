@@ -2,12 +2,12 @@ package validations
 
 import (
 	"context"
-	"fmt"
 	"sort"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/dustin/go-humanize"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -17,6 +17,7 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
 		validation.Field(&request.Message, validation.Required),
+		validation.Field(&request.ReplyMessageID, validation.When(request.ReplyChat != "", validation.Required.Error(utils.T("required_with_reply_chat")))),
 	)
 
 	if err != nil {
@@ -35,7 +36,7 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 	}
 
 	if request.Image == nil && (request.ImageURL == nil || *request.ImageURL == "") {
-		return pkgError.ValidationError("either Image or ImageURL must be provided")
+		return pkgError.ValidationError(utils.T("image_or_url_required"))
 	}
 
 	if request.Image != nil {
@@ -46,18 +47,18 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		}
 
 		if !availableMimes[request.Image.Header.Get("Content-Type")] {
-			return pkgError.ValidationError("your image is not allowed. please use jpg/jpeg/png")
+			return pkgError.ValidationError(utils.T("image_type_not_allowed"))
 		}
 	}
 
 	if request.ImageURL != nil {
 		if *request.ImageURL == "" {
-			return pkgError.ValidationError("ImageURL cannot be empty")
+			return pkgError.ValidationError(utils.T("image_url_required"))
 		}
 
 		err := validation.Validate(*request.ImageURL, is.URL)
 		if err != nil {
-			return pkgError.ValidationError("ImageURL must be a valid URL")
+			return pkgError.ValidationError(utils.T("image_url_invalid"))
 		}
 	}
 
@@ -76,7 +77,7 @@ func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error
 
 	if request.File.Size > config.WhatsappSettingMaxFileSize { // 10MB
 		maxSizeString := humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize))
-		return pkgError.ValidationError(fmt.Sprintf("max file upload is %s, please upload in cloud and send via text if your file is higher than %s", maxSizeString, maxSizeString))
+		return pkgError.ValidationError(utils.T("file_upload_too_large", maxSizeString, maxSizeString))
 	}
 
 	return nil
@@ -99,12 +100,12 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 	}
 
 	if !availableMimes[request.Video.Header.Get("Content-Type")] {
-		return pkgError.ValidationError("your video type is not allowed. please use mp4/mkv/avi")
+		return pkgError.ValidationError(utils.T("video_type_not_allowed"))
 	}
 
 	if request.Video.Size > config.WhatsappSettingMaxVideoSize { // 30MB
 		maxSizeString := humanize.Bytes(uint64(config.WhatsappSettingMaxVideoSize))
-		return pkgError.ValidationError(fmt.Sprintf("max video upload is %s, please upload in cloud and send via text if your file is higher than %s", maxSizeString, maxSizeString))
+		return pkgError.ValidationError(utils.T("video_upload_too_large", maxSizeString, maxSizeString))
 	}
 
 	return nil
@@ -194,7 +195,7 @@ func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) err
 	}
 
 	if !availableMimes[request.Audio.Header.Get("Content-Type")] {
-		return pkgError.ValidationError(fmt.Sprintf("your audio type is not allowed. please use (%s)", availableMimesStr))
+		return pkgError.ValidationError(utils.T("audio_type_not_allowed", availableMimesStr))
 	}
 
 	return nil
@@ -203,7 +204,7 @@ func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) err
 func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error {
 	// Validate options first to ensure it is not blank before validating MaxAnswer
 	if len(request.Options) == 0 {
-		return pkgError.ValidationError("options: cannot be blank.")
+		return pkgError.ValidationError("options: " + utils.T("validation_required") + ".")
 	}
 
 	err := validation.ValidateStructWithContext(ctx, &request,
@@ -225,7 +226,7 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 	uniqueOptions := make(map[string]bool)
 	for _, option := range request.Options {
 		if _, ok := uniqueOptions[option]; ok {
-			return pkgError.ValidationError("options should be unique")
+			return pkgError.ValidationError(utils.T("poll_options_not_unique"))
 		}
 		uniqueOptions[option] = true
 	}
@@ -244,3 +245,16 @@ func ValidateSendPresence(ctx context.Context, request domainSend.PresenceReques
 
 	return nil
 }
+
+func ValidateSendBroadcast(ctx context.Context, request domainSend.BroadcastRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phones, validation.Required, validation.Length(1, domainSend.BroadcastMaxRecipients), validation.Each(validation.Required)),
+		validation.Field(&request.Message, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
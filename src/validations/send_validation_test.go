@@ -44,6 +44,15 @@ func TestValidateSendMessage(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("message: cannot be blank."),
 		},
+		{
+			name: "should error with reply chat but no reply message id",
+			args: args{request: domainSend.MessageRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				Message:   "Hello this is testing",
+				ReplyChat: "120363000000000000@g.us",
+			}},
+			err: pkgError.ValidationError("reply_message_id: is required with reply_chat."),
+		},
 	}
 
 	for _, tt := range tests {
@@ -709,3 +718,52 @@ func TestValidateSendPresence(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSendBroadcast(t *testing.T) {
+	type args struct {
+		request domainSend.BroadcastRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with recipients and message",
+			args: args{request: domainSend.BroadcastRequest{
+				Phones:  []string{"6289685028129", "6289685028130"},
+				Message: "Hello",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without recipients",
+			args: args{request: domainSend.BroadcastRequest{
+				Message: "Hello",
+			}},
+			err: pkgError.ValidationError("phones: cannot be blank."),
+		},
+		{
+			name: "should error with empty recipient",
+			args: args{request: domainSend.BroadcastRequest{
+				Phones:  []string{"6289685028129", ""},
+				Message: "Hello",
+			}},
+			err: pkgError.ValidationError("phones: (1: cannot be blank.)."),
+		},
+		{
+			name: "should error without message",
+			args: args{request: domainSend.BroadcastRequest{
+				Phones: []string{"6289685028129"},
+			}},
+			err: pkgError.ValidationError("message: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendBroadcast(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -77,3 +77,70 @@ func TestValidateUserInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateUserResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainUser.ResolveRequest
+		err     any
+	}{
+		{
+			name:    "should success with lid",
+			request: domainUser.ResolveRequest{JID: "123456789012345@lid"},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty jid",
+			request: domainUser.ResolveRequest{JID: ""},
+			err:     pkgError.ValidationError("jid: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUserResolve(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateExportContacts(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainUser.ExportContactsRequest
+		err     any
+	}{
+		{
+			name:    "should success",
+			request: domainUser.ExportContactsRequest{Page: 1, PerPage: 100},
+			err:     nil,
+		},
+		{
+			name:    "should error with too large page size",
+			request: domainUser.ExportContactsRequest{Page: 1, PerPage: 5000},
+			err:     pkgError.ValidationError("per_page: must be no greater than 1000."),
+		},
+		{
+			name:    "should error with zero page",
+			request: domainUser.ExportContactsRequest{Page: 0, PerPage: 100},
+			err:     pkgError.ValidationError("page: cannot be blank."),
+		},
+		{
+			name:    "should error with zero page size",
+			request: domainUser.ExportContactsRequest{Page: 1, PerPage: 0},
+			err:     pkgError.ValidationError("per_page: cannot be blank."),
+		},
+		{
+			name:    "should error with negative page",
+			request: domainUser.ExportContactsRequest{Page: -1, PerPage: 100},
+			err:     pkgError.ValidationError("page: must be no less than 1."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExportContacts(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}